@@ -1,7 +1,10 @@
 package algoliasearch
 
 import (
+	"context"
+	"io"
 	"net/http"
+	"time"
 )
 
 // Client is a representation of an Algolia application. Once initialized it
@@ -12,18 +15,97 @@ type Client interface {
 	// Algolia servers.
 	SetExtraHeader(key, value string)
 
-	// SetTimeout specifies timeouts to use with the HTTP connection.
+	// SetUserToken sets the `X-Algolia-UserToken` header of all the requests
+	// made by the client, which identifies the end user the requests are
+	// made for (e.g. for personalization and analytics). It can be overridden
+	// per request with RequestOptions.UserToken. An empty `token` removes the
+	// header.
+	SetUserToken(token string)
+
+	// SetDebug dumps all the requests sent by the client, and the responses
+	// received, to `w`: method, URL, headers (with the API key redacted),
+	// bodies truncated to 2KB, attempt number and timing. It is meant to
+	// troubleshoot the exchanges with the API and should not be enabled in
+	// production. A nil `w` disables the dumps.
+	SetDebug(w io.Writer)
+
+	// SetTimeout specifies timeouts to use with the HTTP connection,
+	// expressed in milliseconds.
+	//
+	// Deprecated: use SetTimeouts, which also supports write timeouts and a
+	// deadline for the whole call.
 	SetTimeout(connectTimeout, readTimeout int)
 
+	// SetTimeouts specifies the connect, read and write timeouts to use with
+	// the HTTP connection along with the deadline of each call, retries
+	// included (see Timeouts).
+	SetTimeouts(timeouts Timeouts)
+
+	// SetMetricsHook specifies a hook receiving the metrics of the
+	// connections of the client: number of opened connections, new vs.
+	// reused connections and DNS, connect and TLS handshake timings (see
+	// ConnectionsOpenMetric). A nil `hook` disables the metrics.
+	SetMetricsHook(hook MetricsHook)
+
+	// SetOnRetry specifies a hook called each time a request failed on a host
+	// (network error or 5XX response) and is retried on the next one. A nil
+	// `hook` disables the notifications.
+	SetOnRetry(hook func(HostFailure))
+
+	// SetOnHostFail specifies a hook called each time a host is marked as
+	// down, after a request failed on it. As the state of the hosts is
+	// shared by the clients of the same application, the host is then tried
+	// last by all of them for a while. A nil `hook` disables the
+	// notifications.
+	SetOnHostFail(hook func(HostFailure))
+
+	// SetWaitSchedule specifies the polling schedule used to wait for tasks
+	// (e.g. WaitScheduleAggressive or WaitScheduleRelaxed).
+	// WaitScheduleDefault is used if none is specified.
+	SetWaitSchedule(schedule WaitSchedule)
+
+	// SetPreSearchHook specifies a hook which is called before every search
+	// query (Index.Search and MultipleQueries) to derive extra query
+	// parameters from the query. The time spent in the hook is reported in
+	// the PreSearchHookDuration field of the results. Setting it to `nil`
+	// disables the hook.
+	SetPreSearchHook(hook PreSearchHook)
+
+	// SetRateLimitRetryBudget specifies the maximum time spent waiting before
+	// sending again requests rejected by the API because of rate limiting
+	// (429 HTTP status code). The delay between two attempts is given by the
+	// `Retry-After` header of the response. Once the budget is exhausted, a
+	// `*RateLimitedErr` is returned. Defaults to 1 minute; setting it to 0
+	// disables those retries.
+	SetRateLimitRetryBudget(budget time.Duration)
+
 	// SetMaxIdleConnsPerHosts specifies the value for `MaxIdleConnsPerHost` of
 	// the underlying http.Transport.
 	SetMaxIdleConnsPerHosts(maxIdleConnsPerHost int)
 
 	// SetHTTPClient allows a custom HTTP client to be specified.
 	// NOTE: using this may prevent timeouts set on this client from
-	// working if the underlying transport is not of type *http.Transport.
+	// working if the underlying transport is not of type *http.Transport,
+	// in which case SetRequester should be preferred.
 	SetHTTPClient(client *http.Client)
 
+	// SetRequester makes the client send its requests with the given
+	// Requester (e.g. a retrying or instrumented HTTP client) instead of its
+	// own HTTP client. The connect and write timeouts are then left to the
+	// requester while the read timeout and the deadline of the calls set
+	// with SetTimeouts still apply.
+	SetRequester(r Requester)
+
+	// IsAlive checks that the Algolia servers can be reached with the current
+	// network settings. A nil error is returned if one of the hosts answered
+	// successfully. This is a cheap call that can be used as a health check,
+	// for instance before starting a large indexing job.
+	IsAlive() error
+
+	// IsAliveWithRequestOptions is the same as IsAlive but it also accepts
+	// extra RequestOptions.
+	IsAliveWithRequestOptions(opts *RequestOptions) error
+
 	// ListIndexes returns the list of all indexes belonging to this Algolia
 	// application.
 	ListIndexes() (indexes []IndexRes, err error)
@@ -32,6 +114,25 @@ type Client interface {
 	// accepts extra RequestOptions.
 	ListIndexesWithRequestOptions(opts *RequestOptions) (indexes []IndexRes, err error)
 
+	// ListIndexesByPage returns the given `page` (zero-based) of the indexes
+	// belonging to this Algolia application, along with the total number of
+	// pages.
+	ListIndexesByPage(page int) (res ListIndexesRes, err error)
+
+	// ListIndexesByPageWithRequestOptions is the same as ListIndexesByPage but
+	// it also accepts extra RequestOptions.
+	ListIndexesByPageWithRequestOptions(page int, opts *RequestOptions) (res ListIndexesRes, err error)
+
+	// ListAllIndexes returns the list of all indexes belonging to this Algolia
+	// application by loading all the pages of indexes one after the other.
+	// It should be preferred to ListIndexes for applications with a large
+	// number of indexes.
+	ListAllIndexes() (indexes []IndexRes, err error)
+
+	// ListAllIndexesWithRequestOptions is the same as ListAllIndexes but it
+	// also accepts extra RequestOptions.
+	ListAllIndexesWithRequestOptions(opts *RequestOptions) (indexes []IndexRes, err error)
+
 	// InitIndex returns an Index object targeting `name`.
 	InitIndex(name string) Index
 
@@ -71,6 +172,42 @@ type Client interface {
 	// accepts extra RequestOptions.
 	ClearIndexWithRequestOptions(name string, opts *RequestOptions) (res UpdateTaskRes, err error)
 
+	// GetTask returns the status of the task identified by its `taskID` on
+	// the `indexName` index, like Index.GetStatus does.
+	GetTask(indexName string, taskID int) (res TaskStatusRes, err error)
+
+	// GetTaskWithRequestOptions is the same as GetTask but it also accepts
+	// extra RequestOptions.
+	GetTaskWithRequestOptions(indexName string, taskID int, opts *RequestOptions) (res TaskStatusRes, err error)
+
+	// WaitTask stops the current execution until the task identified by its
+	// `taskID` on the `indexName` index is finished, like Index.WaitTask
+	// does. It lets generic tooling wait for tasks known only by their index
+	// name and ID.
+	WaitTask(indexName string, taskID int) error
+
+	// WaitTaskWithRequestOptions is the same as WaitTask but it also accepts
+	// extra RequestOptions.
+	WaitTaskWithRequestOptions(indexName string, taskID int, opts *RequestOptions) error
+
+	// GetAppTask returns the status of the application-level task identified
+	// by its `taskID`, e.g. a task started by SetDictionarySettings.
+	GetAppTask(taskID int) (res TaskStatusRes, err error)
+
+	// GetAppTaskWithRequestOptions is the same as GetAppTask but it also
+	// accepts extra RequestOptions.
+	GetAppTaskWithRequestOptions(taskID int, opts *RequestOptions) (res TaskStatusRes, err error)
+
+	// WaitAppTask stops the current execution until the application-level
+	// task identified by its `taskID` is finished, polling its status with
+	// the same schedule as Index.WaitTask. Application-level tasks, such as
+	// the ones of the dictionaries, cannot be waited for with Index.WaitTask.
+	WaitAppTask(taskID int) error
+
+	// WaitAppTaskWithRequestOptions is the same as WaitAppTask but it also
+	// accepts extra RequestOptions.
+	WaitAppTaskWithRequestOptions(taskID int, opts *RequestOptions) error
+
 	// AddUserKey creates a new API key from the supplied `ACL` and the
 	// specified optional parameters. More details here:
 	// https://www.algolia.com/doc/rest#add-a-global-api-key
@@ -81,12 +218,23 @@ type Client interface {
 	// AddAPIKey creates a new API key from the supplied `ACL` and the
 	// specified optional parameters. More details here:
 	// https://www.algolia.com/doc/rest#add-a-global-api-key
+	// The ACLs are left to the API: use AddAPIKeyWithParams to have them checked
+	// against the ACL constants (e.g. ACLSearch).
 	AddAPIKey(ACL []string, params Map) (res AddKeyRes, err error)
 
 	// AddAPIKeyWithRequestOptions is the same as AddAPIKey but it also accepts
 	// extra RequestOptions.
 	AddAPIKeyWithRequestOptions(ACL []string, params Map, opts *RequestOptions) (res AddKeyRes, err error)
 
+	// AddAPIKeyWithParams creates a new API key described by the typed
+	// `params`, which are validated before the request is sent (e.g.
+	// misspelled ACLs are rejected instead of being ignored by the API).
+	AddAPIKeyWithParams(params KeyParams) (res AddKeyRes, err error)
+
+	// AddAPIKeyWithParamsWithRequestOptions is the same as
+	// AddAPIKeyWithParams but it also accepts extra RequestOptions.
+	AddAPIKeyWithParamsWithRequestOptions(params KeyParams, opts *RequestOptions) (res AddKeyRes, err error)
+
 	// UpdateUserKey updates the API key identified by its value `key` with the
 	// given parameters.
 	//
@@ -101,6 +249,15 @@ type Client interface {
 	// accepts extra RequestOptions.
 	UpdateAPIKeyWithRequestOptions(key string, params Map, opts *RequestOptions) (res UpdateKeyRes, err error)
 
+	// UpdateAPIKeyWithParams replaces the parameters of the API key
+	// identified by its value `key` by the typed `params`, which are
+	// validated like for AddAPIKeyWithParams.
+	UpdateAPIKeyWithParams(key string, params KeyParams) (res UpdateKeyRes, err error)
+
+	// UpdateAPIKeyWithParamsWithRequestOptions is the same as
+	// UpdateAPIKeyWithParams but it also accepts extra RequestOptions.
+	UpdateAPIKeyWithParamsWithRequestOptions(key string, params KeyParams, opts *RequestOptions) (res UpdateKeyRes, err error)
+
 	// GetUserKey returns the key identified by its value `key`.
 	//
 	// Deprecated: Use GetAPIKey instead.
@@ -125,6 +282,21 @@ type Client interface {
 	// accepts extra RequestOptions.
 	DeleteAPIKeyWithRequestOptions(key string, opts *RequestOptions) (res DeleteRes, err error)
 
+	// ScopedClient returns a Client authenticated with an API key described
+	// by the `params` (validated like for AddAPIKeyWithParams). The key is
+	// generated on the first call and the client is cached: subsequent calls
+	// with the same parameters return the same client. Scoped keys are valid
+	// for one hour unless a `Validity` is given, so that stale keys are
+	// automatically removed by the API. A new key is transparently generated,
+	// and the previous one deleted, when the cached one is about to expire.
+	// With an index prefix (see WithIndexPrefix), the `Indexes` the key is
+	// restricted to are prefixed as well.
+	ScopedClient(params KeyParams) (Client, error)
+
+	// ReleaseScopedClients deletes the API keys of all the clients returned
+	// by ScopedClient. It should be called by short-lived jobs once done.
+	ReleaseScopedClients() error
+
 	// GetLogs retrieves the logs according to the given `params` map which can
 	// contain the following fields:
 	//   - `length` (number of entries to retrieve)
@@ -141,27 +313,154 @@ type Client interface {
 	GetLogsWithRequestOptions(params Map, opts *RequestOptions) (logs []LogRes, err error)
 
 	// MultipleQueries performs all the queries specified in `queries` and
-	// aggregates the results. The `strategy` can either be set to `none`
-	// (default) which executes all the queries until the last one, or set to
-	// `stopIfEnoughMatches` to limit the number of results according to the
-	// `hitsPerPage` parameter. More details here:
+	// aggregates the results. The `strategy` can either be set to
+	// StrategyNone (default) which executes all the queries until the last
+	// one, or set to StrategyStopIfEnoughMatches to limit the number of
+	// results according to the `hitsPerPage` parameter. More details here:
 	// https://www.algolia.com/doc/rest#query-multiple-indexes
-	MultipleQueries(queries []IndexedQuery, strategy string) (res []MultipleQueryRes, err error)
+	MultipleQueries(queries []IndexedQuery, strategy MultipleQueriesStrategy) (res []MultipleQueryRes, err error)
 
 	// MultipleQueriesWithRequestOptions is the same as MultipleQueries but it
 	// also accepts extra RequestOptions.
-	MultipleQueriesWithRequestOptions(queries []IndexedQuery, strategy string, opts *RequestOptions) (res []MultipleQueryRes, err error)
+	MultipleQueriesWithRequestOptions(queries []IndexedQuery, strategy MultipleQueriesStrategy, opts *RequestOptions) (res []MultipleQueryRes, err error)
+
+	// FederatedSearch searches for `query` with each of the `queries`,
+	// typically targeting different indexes (e.g. products, articles and
+	// FAQ), in a single MultipleQueries call. The hits of all the queries
+	// are merged into a single interleaved list, each hit being attributed
+	// to its query, according to the weights of the queries.
+	FederatedSearch(query string, queries []FederatedQuery) (res FederatedRes, err error)
+
+	// FederatedSearchWithRequestOptions is the same as FederatedSearch but
+	// it also accepts extra RequestOptions.
+	FederatedSearchWithRequestOptions(query string, queries []FederatedQuery, opts *RequestOptions) (res FederatedRes, err error)
+
+	// BrowseIndexes browses all the records of the indexes `names`, matching
+	// the given `params`, by browsing up to `concurrency` indexes at the same
+	// time (4 if not positive). The records of all the indexes are returned
+	// one by one by the iterator, along with the name of their index.
+	BrowseIndexes(names []string, params Map, concurrency int) (it IndexesIterator, err error)
+
+	// BrowseIndexesWithRequestOptions is the same as BrowseIndexes but it
+	// also accepts extra RequestOptions.
+	BrowseIndexesWithRequestOptions(names []string, params Map, concurrency int, opts *RequestOptions) (it IndexesIterator, err error)
 
-	// Batch performs all queries in `operations`.
+	// Batch performs all queries in `operations`, which can be built with
+	// BatchBuilder.IndexedOperations.
 	Batch(operations []BatchOperationIndexed) (res MultipleBatchRes, err error)
 
 	// BatchWithRequestOptions is the same as Batch but it also accepts extra
 	// RequestOptions.
 	BatchWithRequestOptions(operations []BatchOperationIndexed, opts *RequestOptions) (res MultipleBatchRes, err error)
+
+	// CoordinatedBatch applies the given `writes` on their respective
+	// indices, one after the other, and waits for all of them to be
+	// processed. If a write fails, the following ones are not sent and the
+	// compensation operations of all the writes which have been sent are
+	// applied. The outcome of every write is reported in `res`, in the same
+	// order as `writes`, the writes which were not sent failing with
+	// NotAppliedErr, and a non-nil error is returned if any write failed.
+	CoordinatedBatch(writes []CoordinatedWrite) (res []CoordinatedWriteRes, err error)
+
+	// CoordinatedBatchWithRequestOptions is the same as CoordinatedBatch but
+	// it also accepts extra RequestOptions.
+	CoordinatedBatchWithRequestOptions(writes []CoordinatedWrite, opts *RequestOptions) (res []CoordinatedWriteRes, err error)
+
+	// GetDictionarySettings retrieves the settings of the dictionaries of the
+	// application.
+	GetDictionarySettings() (settings DictionarySettings, err error)
+
+	// GetDictionarySettingsWithRequestOptions is the same as
+	// GetDictionarySettings but it also accepts extra RequestOptions.
+	GetDictionarySettingsWithRequestOptions(opts *RequestOptions) (settings DictionarySettings, err error)
+
+	// SetDictionarySettings updates the settings of the dictionaries of the
+	// application, e.g. to disable the standard stopwords of some languages.
+	// The returned task can be waited for with WaitAppTask.
+	SetDictionarySettings(settings DictionarySettings) (res UpdateTaskRes, err error)
+
+	// SetDictionarySettingsWithRequestOptions is the same as
+	// SetDictionarySettings but it also accepts extra RequestOptions.
+	SetDictionarySettingsWithRequestOptions(settings DictionarySettings, opts *RequestOptions) (res UpdateTaskRes, err error)
+
+	// SearchDictionaryEntries searches the entries of the `dictionary`
+	// matching the given `params`. To retrieve all of them, see
+	// DictionaryEntryIterator.
+	SearchDictionaryEntries(dictionary DictionaryName, params SearchDictionaryEntriesParams) (res SearchDictionaryEntriesRes, err error)
+
+	// SearchDictionaryEntriesWithRequestOptions is the same as
+	// SearchDictionaryEntries but it also accepts extra RequestOptions.
+	SearchDictionaryEntriesWithRequestOptions(dictionary DictionaryName, params SearchDictionaryEntriesParams, opts *RequestOptions) (res SearchDictionaryEntriesRes, err error)
+
+	// SearchUserIDs searches the users of a multi-cluster application whose
+	// userID matches the `query`, e.g. to find out which cluster they are
+	// assigned to. The search is restricted to the `clusterName` cluster if
+	// not empty. To retrieve the first page, `page` should be set to 0 and a
+	// zero `hitsPerPage` returns the default 20 users per page.
+	SearchUserIDs(query, clusterName string, page, hitsPerPage int) (res SearchUserIDsRes, err error)
+
+	// SearchUserIDsWithRequestOptions is the same as SearchUserIDs but it
+	// also accepts extra RequestOptions.
+	SearchUserIDsWithRequestOptions(query, clusterName string, page, hitsPerPage int, opts *RequestOptions) (res SearchUserIDsRes, err error)
 }
 
 // Index is a representation used to manipulate an Algolia index.
 type Index interface {
+	// SetScrubbedAttributes specifies the attributes which are always removed
+	// from the records returned by this Index (via Search, Browse, BrowseAll,
+	// GetObject and GetObjects), including from their highlighting and
+	// snippeting results. Nested attributes can be specified using the dot
+	// notation (e.g. `billing.iban`). This acts as a client-side safety net
+	// for sensitive attributes, even if a call does not restrict the
+	// retrieved attributes. Calling it with an empty slice disables the
+	// scrubbing.
+	SetScrubbedAttributes(attributes []string)
+
+	// SetExtraHeader allows to set custom headers on the requests made for
+	// this Index only (e.g. a tenant identifier), on top of the ones set with
+	// Client.SetExtraHeader. The headers given per request with
+	// RequestOptions.ExtraHeaders take precedence.
+	SetExtraHeader(key, value string)
+
+	// RouteHeavyQueriesTo routes the search queries considered as heavy by
+	// `isHeavy` (IsHeavyQuery if `nil`) to the `replica` index instead of
+	// the index itself, which keeps the query cache of the index hot for
+	// regular traffic. As a guardrail, an error is returned if the replica
+	// does not exist or is not a replica of the index. Calling it with an
+	// empty `replica` disables the routing. It is safe to call concurrently
+	// with the searches.
+	RouteHeavyQueriesTo(replica string, isHeavy HeavyQueryPredicate) error
+
+	// RouteHeavyQueriesToWithRequestOptions is the same as
+	// RouteHeavyQueriesTo but it also accepts extra RequestOptions.
+	RouteHeavyQueriesToWithRequestOptions(replica string, isHeavy HeavyQueryPredicate, opts *RequestOptions) error
+
+	// AddReplica adds the `name` replica to the `replicas` setting of the
+	// index, keeping the existing replicas. Virtual replicas are added using
+	// NewVirtualReplica to build the `name`. The call waits for the update to
+	// be published and reads the replicas again: as the API has no
+	// conditional write, ReplicasModifiedErr is returned if they were
+	// concurrently modified in the meantime, in which case either update may
+	// have been lost. If `settings` is not nil, they are applied to the
+	// replica once it is created: the call then also waits for the replica
+	// task to be over.
+	AddReplica(name string, settings Map) (res UpdateTaskRes, err error)
+
+	// AddReplicaWithRequestOptions is the same as AddReplica but it also
+	// accepts extra RequestOptions.
+	AddReplicaWithRequestOptions(name string, settings Map, opts *RequestOptions) (res UpdateTaskRes, err error)
+
+	// RemoveReplica removes the `name` replica, either standard or virtual,
+	// from the `replicas` setting of the index, which turns it into a regular
+	// index. As for AddReplica, the call waits for the update to be published
+	// and ReplicasModifiedErr is returned if the replicas were concurrently
+	// modified.
+	RemoveReplica(name string) (res UpdateTaskRes, err error)
+
+	// RemoveReplicaWithRequestOptions is the same as RemoveReplica but it also
+	// accepts extra RequestOptions.
+	RemoveReplicaWithRequestOptions(name string, opts *RequestOptions) (res UpdateTaskRes, err error)
+
 	// Delete removes the Algolia index.
 	Delete() (res DeleteTaskRes, err error)
 
@@ -187,15 +486,26 @@ type Index interface {
 	GetObjectWithRequestOptions(objectID string, attributes []string, opts *RequestOptions) (object Object, err error)
 
 	// GetObjects retrieves the objects identified according to their
-	// `objectIDs`.
+	// `objectIDs`, in the same order. Large lists are transparently split
+	// into several calls. The position of the objects which do not exist
+	// holds a nil Object (see GetObjectsWithMissing to list them).
 	GetObjects(objectIDs []string) (objects []Object, err error)
 
 	// GetObjectsWithRequestOptions is the same as GetObjects but it also
 	// accepts extra RequestOptions.
 	GetObjectsWithRequestOptions(objectIDs []string, opts *RequestOptions) (objects []Object, err error)
 
+	// GetObjectsWithMissing is the same as GetObjects but it also returns
+	// the objectIDs of the objects which do not exist, in order.
+	GetObjectsWithMissing(objectIDs []string) (objects []Object, missing []string, err error)
+
+	// GetObjectsWithMissingWithRequestOptions is the same as
+	// GetObjectsWithMissing but it also accepts extra RequestOptions.
+	GetObjectsWithMissingWithRequestOptions(objectIDs []string, opts *RequestOptions) (objects []Object, missing []string, err error)
+
 	// GetObjectsAttrs retrieves the selected attributes of the objects
-	// identified according to their `objectIDs`.
+	// identified according to their `objectIDs`. It behaves as GetObjects
+	// regarding large lists and missing objects.
 	GetObjectsAttrs(objectIDs, attributesToRetrieve []string) (objs []Object, err error)
 
 	// GetObjectsAttrsWithRequestOptions is the same as GetObjectsAttrs but it
@@ -224,16 +534,81 @@ type Index interface {
 	// accepts extra RequestOptions.
 	SetSettingsWithRequestOptions(settings Map, opts *RequestOptions) (res UpdateTaskRes, err error)
 
+	// ExportConfig retrieves the whole configuration of the index (settings,
+	// synonyms and rules), which can be encoded as JSON to be versioned and
+	// later applied to any index with ImportConfig.
+	ExportConfig() (cfg IndexConfig, err error)
+
+	// ExportConfigWithRequestOptions is the same as ExportConfig but it also
+	// accepts extra RequestOptions.
+	ExportConfigWithRequestOptions(opts *RequestOptions) (cfg IndexConfig, err error)
+
+	// ImportConfig applies the configuration `cfg`, usually obtained with
+	// ExportConfig, to the index: its settings are set and its synonyms and
+	// rules are saved. If `clearExisting` is true, the settings which are not
+	// part of the configuration are reset to their default value and the
+	// synonyms and rules which are not part of it are removed, which makes the
+	// import idempotent. The changes are not forwarded to the replicas. The
+	// returned task IDs can be waited for with WaitTasks.
+	ImportConfig(cfg IndexConfig, clearExisting bool) (res ImportConfigRes, err error)
+
+	// ImportConfigWithRequestOptions is the same as ImportConfig but it also
+	// accepts extra RequestOptions.
+	ImportConfigWithRequestOptions(cfg IndexConfig, clearExisting bool, opts *RequestOptions) (res ImportConfigRes, err error)
+
+	// GetStats returns the statistics of the index (number of entries, data
+	// and file sizes, last build time, number of pending tasks, etc.) as
+	// listed by `Client.ListAllIndexes`. `IndexNotFoundErr` is returned if
+	// the index does not exist.
+	GetStats() (stats IndexRes, err error)
+
+	// GetStatsWithRequestOptions is the same as GetStats but it also accepts
+	// extra RequestOptions.
+	GetStatsWithRequestOptions(opts *RequestOptions) (stats IndexRes, err error)
+
+	// PendingTaskCount returns the number of tasks of the index which are
+	// not processed yet. It can be used by ingestion pipelines to slow down
+	// when the task queue of the index grows too much.
+	PendingTaskCount() (count int, err error)
+
+	// PendingTaskCountWithRequestOptions is the same as PendingTaskCount but
+	// it also accepts extra RequestOptions.
+	PendingTaskCountWithRequestOptions(opts *RequestOptions) (count int, err error)
+
 	// WaitTask stops the current execution until the task identified by its
 	// `taskID` is finished. The waiting time between each check is usually
 	// implemented by starting at 1s and increases by a factor of 2 at each
-	// retry (but is bounded at around 20min).
+	// retry (but is bounded at around 20min), unless another WaitSchedule was
+	// selected.
 	WaitTask(taskID int) error
 
 	// WaitTaskWithRequestOptions is the same as WaitTask but it also accepts
 	// extra RequestOptions.
 	WaitTaskWithRequestOptions(taskID int, opts *RequestOptions) error
 
+	// WaitTaskAsync is the same as WaitTask but it returns right away. The
+	// returned channel receives a single value, nil once the task is
+	// finished or the error which stopped the polling, and is then closed,
+	// so that the completion of the task can be selected along with
+	// timeouts or shutdown signals. The polling stops once the `ctx` is
+	// done, the channel then receiving the error of the `ctx`.
+	WaitTaskAsync(ctx context.Context, taskID int) <-chan error
+
+	// WaitTaskAsyncWithRequestOptions is the same as WaitTaskAsync but it
+	// also accepts extra RequestOptions.
+	WaitTaskAsyncWithRequestOptions(ctx context.Context, taskID int, opts *RequestOptions) <-chan error
+
+	// WaitTasks stops the current execution until all the tasks identified
+	// by their `taskIDs` are finished. As the tasks of an index are
+	// processed in order, only the status of the most recent task (i.e. the
+	// one with the highest taskID) is checked, which avoids polling the
+	// status of every single task.
+	WaitTasks(taskIDs []int) error
+
+	// WaitTasksWithRequestOptions is the same as WaitTasks but it also
+	// accepts extra RequestOptions.
+	WaitTasksWithRequestOptions(taskIDs []int, opts *RequestOptions) error
+
 	// ListKeys lists all the keys that can access the index.
 	ListKeys() (keys []Key, err error)
 
@@ -334,7 +709,8 @@ type Index interface {
 	// PartialUpdateObjectNoCreate but it also accepts extra RequestOptions.
 	PartialUpdateObjectNoCreateWithRequestOptions(object Object, opts *RequestOptions) (res UpdateTaskRes, err error)
 
-	// AddObjects adds several objects to the index.
+	// AddObjects adds several objects to the index. The objects sharing the
+	// same objectID are handled according to RequestOptions.Duplicates.
 	AddObjects(objects []Object) (BatchRes, error)
 
 	// AddObjectsWithRequestOptions is the same as AddObjects but it also
@@ -342,7 +718,8 @@ type Index interface {
 	AddObjectsWithRequestOptions(objects []Object, opts *RequestOptions) (BatchRes, error)
 
 	// UpdateObjects adds or replaces several objects at the same time,
-	// according to their respective `objectID` attribute.
+	// according to their respective `objectID` attribute. The objects sharing
+	// the same objectID are handled according to RequestOptions.Duplicates.
 	UpdateObjects(objects []Object) (BatchRes, error)
 
 	// UpdateObjectsWithRequestOptions is the same as UpdateObjects but it also
@@ -366,23 +743,41 @@ type Index interface {
 	// PartialUpdateObjectsNoCreate but it also accepts extra RequestOptions.
 	PartialUpdateObjectsNoCreateWithRequestOptions(objects []Object, opts *RequestOptions) (BatchRes, error)
 
+	// PartialUpdateMany partially updates several objects at the same time.
+	// The `changes` map associates the objectID of every record to update to
+	// the attributes to modify. If `createIfNotExists` is `false`, the records
+	// which do not exist yet are not created. The operations are sent in
+	// chunks of 1000 operations and the responses of all the underlying batch
+	// requests are returned. If a chunk fails, the following ones are not sent
+	// and its response is the last one, holding the failed operations.
+	PartialUpdateMany(changes map[string]Map, createIfNotExists bool) (res BatchesRes, err error)
+
+	// PartialUpdateManyWithRequestOptions is the same as PartialUpdateMany but
+	// it also accepts extra RequestOptions.
+	PartialUpdateManyWithRequestOptions(changes map[string]Map, createIfNotExists bool, opts *RequestOptions) (res BatchesRes, err error)
+
 	// DeleteObjects removes several objects at the same time, according to
-	// their respective `objectID` attribute.
-	DeleteObjects(objectIDs []string) (BatchRes, error)
+	// their respective `objectID` attribute. As for PartialUpdateMany, the
+	// deletions are sent in chunks of 1000 operations and the responses of
+	// all the underlying batch requests are returned; they can be waited for
+	// at once with BatchesRes.Wait.
+	DeleteObjects(objectIDs []string) (BatchesRes, error)
 
 	// DeleteObjectsWithRequestOptions is the same as DeleteObjects but it also
 	// accepts extra RequestOptions.
-	DeleteObjectsWithRequestOptions(objectIDs []string, opts *RequestOptions) (BatchRes, error)
+	DeleteObjectsWithRequestOptions(objectIDs []string, opts *RequestOptions) (BatchesRes, error)
 
 	// Batch processes all the specified `operations` in a batch manner. The
 	// operations's actions could be one of the following:
-	//   - `addObject`
-	//   - `updateObject`
-	//   - `partialUpdateObject`
-	//   - `partialUpdateObjectNoCreate`
-	//   - `deleteObject`
-	//   - `clear`
-	// More details here:
+	//   - `ActionAddObject`
+	//   - `ActionUpdateObject`
+	//   - `ActionPartialUpdateObject`
+	//   - `ActionPartialUpdateObjectNoCreate`
+	//   - `ActionDeleteObject`
+	//   - `ActionClear`
+	// The operations are checked before being sent: an unknown action or a
+	// missing `objectID` is reported without sending any request. They can
+	// also be built with a BatchBuilder. More details here:
 	// https://www.algolia.com/doc/rest#batch-write-operations.
 	Batch(operations []BatchOperation) (res BatchRes, err error)
 
@@ -404,6 +799,26 @@ type Index interface {
 	// RequestOptions.
 	MoveWithRequestOptions(name string, opts *RequestOptions) (UpdateTaskRes, error)
 
+	// Snapshot copies the index into a new one whose name is the name of the
+	// index followed by the current UTC time formatted with `suffixFormat`, a
+	// time layout such as DefaultSnapshotSuffixFormat (used if empty), e.g.
+	// `products_backup_20240101T0300`. The copy can be waited for with
+	// WaitTask.
+	Snapshot(suffixFormat string) (res SnapshotRes, err error)
+
+	// SnapshotWithRequestOptions is the same as Snapshot but it also accepts
+	// extra RequestOptions.
+	SnapshotWithRequestOptions(suffixFormat string, opts *RequestOptions) (res SnapshotRes, err error)
+
+	// PruneSnapshots deletes the snapshots of the index taken with Snapshot
+	// and the same `suffixFormat` more than `maxAge` ago. The names of the
+	// deleted indices are returned.
+	PruneSnapshots(suffixFormat string, maxAge time.Duration) (deleted []string, err error)
+
+	// PruneSnapshotsWithRequestOptions is the same as PruneSnapshots but it
+	// also accepts extra RequestOptions.
+	PruneSnapshotsWithRequestOptions(suffixFormat string, maxAge time.Duration, opts *RequestOptions) (deleted []string, err error)
+
 	// GetStatus returns the status of a task given its ID `taskID`.
 	GetStatus(taskID int) (res TaskStatusRes, err error)
 
@@ -428,7 +843,8 @@ type Index interface {
 	GetSynonymWithRequestOptions(objectID string, opts *RequestOptions) (s Synonym, err error)
 
 	// AddSynonym adds the given `synonym`. This addition can be forwarded to
-	// the index replicas by setting `forwardToReplicas` to `true`.
+	// the index replicas by setting `forwardToReplicas` to `true`. The synonym
+	// is checked with Synonym.Validate before being sent.
 	AddSynonym(synonym Synonym, forwardToReplicas bool) (res UpdateTaskRes, err error)
 
 	// AddSynonymWithRequestOptions is the same as AddSynonym but it also
@@ -456,7 +872,8 @@ type Index interface {
 	// BatchSynonyms adds all `synonyms` to the index. The index can be cleared
 	// before by setting `replaceExistingSynonyms` to `true`. The optional
 	// clear operation and the additions can be forwarded to the index replicas
-	// by setting `forwardToReplicas` to `true'.
+	// by setting `forwardToReplicas` to `true'. No synonym is sent if any of
+	// them is rejected by Synonym.Validate.
 	BatchSynonyms(synonyms []Synonym, replaceExistingSynonyms, forwardToReplicas bool) (res UpdateTaskRes, err error)
 
 	// BatchSynonymsWithRequestOptions is the same as BatchSynonyms but it also
@@ -489,6 +906,17 @@ type Index interface {
 	// extra RequestOptions.
 	BrowseAllWithRequestOptions(params Map, opts *RequestOptions) (it IndexIterator, err error)
 
+	// BrowseAllFrom is the same as BrowseAll but the iteration starts at the
+	// given `cursor`, as returned by IndexIterator.Cursor, so that an
+	// interrupted iteration can be resumed. The `params` should be the ones
+	// of the interrupted iteration. An empty `cursor` starts from the first
+	// record.
+	BrowseAllFrom(params Map, cursor string) (it IndexIterator, err error)
+
+	// BrowseAllFromWithRequestOptions is the same as BrowseAllFrom but it
+	// also accepts extra RequestOptions.
+	BrowseAllFromWithRequestOptions(params Map, cursor string, opts *RequestOptions) (it IndexIterator, err error)
+
 	// Search performs a search query according to the `query` search query and
 	// the given `params`. More details here:
 	// https://www.algolia.com/doc/rest#query-an-index
@@ -498,6 +926,27 @@ type Index interface {
 	// RequestOptions.
 	SearchWithRequestOptions(query string, params Map, opts *RequestOptions) (res QueryRes, err error)
 
+	// SearchRaw is the same as Search but the hits of the response are left
+	// undecoded, which avoids the cost of building a Map per hit when they
+	// are only forwarded (see RawQueryRes).
+	SearchRaw(query string, params Map) (res RawQueryRes, err error)
+
+	// SearchRawWithRequestOptions is the same as SearchRaw but it also
+	// accepts extra RequestOptions.
+	SearchRawWithRequestOptions(query string, params Map, opts *RequestOptions) (res RawQueryRes, err error)
+
+	// SearchAll returns an iterator over all the hits found for the `query`
+	// search query given the `params`, starting at the given `page` (0 by
+	// default). Calling `Next()` on the iterator returns the hits one by one,
+	// transparently loading the following pages until the last one is
+	// reached, as capped by the `paginationLimitedTo` setting of the index.
+	// The `offset` and `length` parameters cannot be used.
+	SearchAll(query string, params Map) (it SearchIterator, err error)
+
+	// SearchAllWithRequestOptions is the same as SearchAll but it also
+	// accepts extra RequestOptions.
+	SearchAllWithRequestOptions(query string, params Map, opts *RequestOptions) (it SearchIterator, err error)
+
 	// DeleteBy finds all the records that match the given query parameters
 	// and deletes them. However, those parameters do not support all the
 	// options of a query, only its filters (numeric, facet, or tag) and geo
@@ -512,7 +961,9 @@ type Index interface {
 
 	// DeleteByQuery finds all the records that match the `query`, according to
 	// the given 'params` and deletes them. It hangs until all the deletion
-	// operations have completed.
+	// operations have completed. A QueryDeleter can be used instead to follow
+	// the progress of the deletion, cap the number of deleted records or
+	// cancel it.
 	//
 	// Deprecated: Use DeleteBy instead.
 	DeleteByQuery(query string, params Map) error
@@ -549,6 +1000,17 @@ type Index interface {
 	// SearchForFacetValues but it also accepts extra RequestOptions.
 	SearchForFacetValuesWithRequestOptions(facet, query string, params Map, opts *RequestOptions) (res SearchFacetRes, err error)
 
+	// SearchForFacetValuesWithParams is the same as SearchForFacetValues but
+	// the facet query, the maximum number of facet values to return, their
+	// order and the search parameters restricting the matching records are
+	// given as SearchForFacetValuesParams.
+	SearchForFacetValuesWithParams(facet string, params SearchForFacetValuesParams) (res SearchFacetRes, err error)
+
+	// SearchForFacetValuesWithParamsWithRequestOptions is the same as
+	// SearchForFacetValuesWithParams but it also accepts extra
+	// RequestOptions.
+	SearchForFacetValuesWithParamsWithRequestOptions(facet string, params SearchForFacetValuesParams, opts *RequestOptions) (res SearchFacetRes, err error)
+
 	// SaveRule saves the given Rule for the current index. If a Rule with the
 	// same objectID already exists, it will get overriden. The operation can
 	// be forwarded to the index replicas by setting `forwardToReplicas` to
@@ -595,20 +1057,15 @@ type Index interface {
 	// accepts extra RequestOptions.
 	ClearRulesWithRequestOptions(forwardToReplicas bool, opts *RequestOptions) (ClearRulesRes, error)
 
-	// SearchRules allows to search for Rules for the current index. The
-	// given `Map` can be populated with any of the following fields, which are
-	// all optional:
-	//
-	//   - `query` (string):                     enable full text search within the Rule fields
-	//   - `anchoring` (RulePatternAnchoring):   restricts the search to Rules with a specific anchoring type
-	//   - `context` (string):                   restricts the search to rules with a specific context (exact match)
-	//   - `page` (int):                         requested page (zero-based, defaults to zero)
-	//   - `hitsPerPage` (int):                  maximum number of hits in a page (defaults to 20)
-	SearchRules(params Map) (SearchRulesRes, error)
+	// SearchRules allows to search for Rules for the current index. All the
+	// fields of the given SearchRulesParams are optional, see its
+	// documentation for their default values. The returned hits contain the
+	// `_highlightResult` of the matching Rules.
+	SearchRules(params SearchRulesParams) (SearchRulesRes, error)
 
 	// SearchRulesWithRequestOptions is the same as SearchRules but it also
 	// accepts extra RequestOptions.
-	SearchRulesWithRequestOptions(params Map, opts *RequestOptions) (SearchRulesRes, error)
+	SearchRulesWithRequestOptions(params SearchRulesParams, opts *RequestOptions) (SearchRulesRes, error)
 }
 
 // IndexIterator is used by the BrowseAll functions to iterate over all the
@@ -620,4 +1077,12 @@ type IndexIterator interface {
 	// occurs. When the last element is reached, an error is returned with the
 	// following message: "No more hits".
 	Next() (res Map, err error)
+
+	// Cursor returns the cursor from which the iteration can be resumed with
+	// Index.BrowseAllFrom, to be persisted by long-running iterations. As the
+	// cursors point to pages of records, the records already returned from
+	// the current page are returned again once resumed. Once the last record
+	// was returned, `done` is `true` and there is nothing left to resume: the
+	// empty `cursor` would start the iteration over.
+	Cursor() (cursor string, done bool)
 }
@@ -0,0 +1,218 @@
+package algoliasearch
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// AnalyticsClient gives access to the Algolia Analytics API, which manages
+// the A/B tests of an application.
+type AnalyticsClient interface {
+	// SetExtraHeader allows to set custom headers while reaching out to the
+	// Analytics API.
+	SetExtraHeader(key, value string)
+
+	// AddABTest creates the given A/B test. It is not active right away:
+	// WaitForABTest can be used to wait for the test to start.
+	AddABTest(abTest ABTest) (res ABTestTaskRes, err error)
+
+	// AddABTestWithRequestOptions is the same as AddABTest but it also
+	// accepts extra RequestOptions.
+	AddABTestWithRequestOptions(abTest ABTest, opts *RequestOptions) (res ABTestTaskRes, err error)
+
+	// GetABTest returns the A/B test identified by `id`, along with its
+	// results.
+	GetABTest(id int) (res ABTestRes, err error)
+
+	// GetABTestWithRequestOptions is the same as GetABTest but it also
+	// accepts extra RequestOptions.
+	GetABTestWithRequestOptions(id int, opts *RequestOptions) (res ABTestRes, err error)
+
+	// GetABTests lists the A/B tests of the application. The `params` map
+	// can contain `offset` and `limit` to paginate the tests.
+	GetABTests(params Map) (res GetABTestsRes, err error)
+
+	// GetABTestsWithRequestOptions is the same as GetABTests but it also
+	// accepts extra RequestOptions.
+	GetABTestsWithRequestOptions(params Map, opts *RequestOptions) (res GetABTestsRes, err error)
+
+	// StopABTest stops the A/B test identified by `id`. Its results remain
+	// available but it cannot be restarted.
+	StopABTest(id int) (res ABTestTaskRes, err error)
+
+	// StopABTestWithRequestOptions is the same as StopABTest but it also
+	// accepts extra RequestOptions.
+	StopABTestWithRequestOptions(id int, opts *RequestOptions) (res ABTestTaskRes, err error)
+
+	// DeleteABTest deletes the A/B test identified by `id` and its results.
+	DeleteABTest(id int) (res ABTestTaskRes, err error)
+
+	// DeleteABTestWithRequestOptions is the same as DeleteABTest but it also
+	// accepts extra RequestOptions.
+	DeleteABTestWithRequestOptions(id int, opts *RequestOptions) (res ABTestTaskRes, err error)
+
+	// WaitForABTest polls the A/B test identified by `id` until it is either
+	// active or stopped (which includes expired tests) and returns it. An
+	// error is returned if the test failed to start or did not start within
+	// 10 minutes.
+	WaitForABTest(id int) (res ABTestRes, err error)
+
+	// WaitForABTestWithRequestOptions is the same as WaitForABTest but it
+	// also accepts extra RequestOptions. The polling schedule and the maximum
+	// wait can be set with RequestOptions.WaitSchedule and
+	// RequestOptions.Timeout.
+	WaitForABTestWithRequestOptions(id int, opts *RequestOptions) (res ABTestRes, err error)
+}
+
+// abTestWaitSchedule is the default polling schedule of WaitForABTest, which
+// gives up after abTestWaitTimeout unless RequestOptions.Timeout is set.
+var abTestWaitSchedule = WaitSchedule{Initial: 100 * time.Millisecond, Max: 10 * time.Second}
+
+const abTestWaitTimeout = 10 * time.Minute
+
+type analyticsClient struct {
+	transport *Transport
+}
+
+// NewAnalyticsClient instantiates a new `AnalyticsClient` from the provided
+// `appID` and `apiKey`. The `region` is the region of the Analytics API the
+// application is hosted in: "us" or "de". If empty, the default Analytics
+// API host is used.
+func NewAnalyticsClient(appID, apiKey, region string) (AnalyticsClient, error) {
+	host := "analytics.algolia.com"
+	switch region {
+	case "":
+	case "us", "de":
+		host = "analytics." + region + ".algolia.com"
+	default:
+		return nil, fmt.Errorf("Invalid region %q: should be \"us\" or \"de\"", region)
+	}
+
+	return &analyticsClient{
+		transport: newTransportWithOnlyHosts(appID, apiKey, []string{host}),
+	}, nil
+}
+
+func (c *analyticsClient) SetExtraHeader(key, value string) {
+	c.transport.setExtraHeader(key, value)
+}
+
+func (c *analyticsClient) AddABTest(abTest ABTest) (res ABTestTaskRes, err error) {
+	return c.AddABTestWithRequestOptions(abTest, nil)
+}
+
+func (c *analyticsClient) AddABTestWithRequestOptions(abTest ABTest, opts *RequestOptions) (res ABTestTaskRes, err error) {
+	if err = checkABTest(abTest); err != nil {
+		return
+	}
+
+	err = c.transport.requestJSON(&res, "POST", "/2/abtests", abTest, write, opts)
+	return
+}
+
+func (c *analyticsClient) GetABTest(id int) (res ABTestRes, err error) {
+	return c.GetABTestWithRequestOptions(id, nil)
+}
+
+func (c *analyticsClient) GetABTestWithRequestOptions(id int, opts *RequestOptions) (res ABTestRes, err error) {
+	path := "/2/abtests/" + strconv.Itoa(id)
+	err = c.transport.requestJSON(&res, "GET", path, nil, read, opts)
+	return
+}
+
+func (c *analyticsClient) GetABTests(params Map) (res GetABTestsRes, err error) {
+	return c.GetABTestsWithRequestOptions(params, nil)
+}
+
+func (c *analyticsClient) GetABTestsWithRequestOptions(params Map, opts *RequestOptions) (res GetABTestsRes, err error) {
+	err = c.transport.requestJSON(&res, "GET", "/2/abtests", params, read, opts)
+	return
+}
+
+func (c *analyticsClient) StopABTest(id int) (res ABTestTaskRes, err error) {
+	return c.StopABTestWithRequestOptions(id, nil)
+}
+
+func (c *analyticsClient) StopABTestWithRequestOptions(id int, opts *RequestOptions) (res ABTestTaskRes, err error) {
+	path := "/2/abtests/" + strconv.Itoa(id) + "/stop"
+	err = c.transport.requestJSON(&res, "POST", path, nil, write, opts)
+	return
+}
+
+func (c *analyticsClient) DeleteABTest(id int) (res ABTestTaskRes, err error) {
+	return c.DeleteABTestWithRequestOptions(id, nil)
+}
+
+func (c *analyticsClient) DeleteABTestWithRequestOptions(id int, opts *RequestOptions) (res ABTestTaskRes, err error) {
+	path := "/2/abtests/" + strconv.Itoa(id)
+	err = c.transport.requestJSON(&res, "DELETE", path, nil, write, opts)
+	return
+}
+
+func (c *analyticsClient) WaitForABTest(id int) (res ABTestRes, err error) {
+	return c.WaitForABTestWithRequestOptions(id, nil)
+}
+
+func (c *analyticsClient) WaitForABTestWithRequestOptions(id int, opts *RequestOptions) (res ABTestRes, err error) {
+	timeout := abTestWaitTimeout
+	if opts != nil && opts.Timeout > 0 {
+		timeout = opts.Timeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	err = callWaitSchedule(opts, abTestWaitSchedule).poll(ctx, func() (bool, error) {
+		test, err := c.GetABTestWithRequestOptions(id, opts)
+		if err != nil {
+			return false, err
+		}
+		res = test
+
+		switch res.Status {
+		case ABTestStatusActive, ABTestStatusStopped, ABTestStatusExpired:
+			return true, nil
+		case ABTestStatusFailed:
+			return false, fmt.Errorf("A/B test %d failed to start", id)
+		}
+		return false, nil
+	})
+	if err == context.DeadlineExceeded {
+		err = fmt.Errorf("A/B test %d did not start within %s", id, timeout)
+	}
+	return
+}
+
+// checkABTest checks that the A/B test has a name, an end date and two
+// variants sharing the whole traffic.
+func checkABTest(abTest ABTest) error {
+	if abTest.Name == "" {
+		return fmt.Errorf("A/B test should have a name")
+	}
+
+	if _, err := time.Parse(time.RFC3339, abTest.EndAt); err != nil {
+		return fmt.Errorf("A/B test `endAt` should be a RFC 3339 date, got %q", abTest.EndAt)
+	}
+
+	if len(abTest.Variants) != 2 {
+		return fmt.Errorf("A/B test should have 2 variants, got %d", len(abTest.Variants))
+	}
+
+	total := 0
+	for _, v := range abTest.Variants {
+		if v.Index == "" {
+			return fmt.Errorf("A/B test variants should have an index")
+		}
+		if v.TrafficPercentage <= 0 {
+			return fmt.Errorf("A/B test variant %s should receive some traffic", v.Index)
+		}
+		total += v.TrafficPercentage
+	}
+
+	if total != 100 {
+		return fmt.Errorf("A/B test variants traffic percentages should add up to 100, got %d", total)
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package algoliasearch
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyticsClient(t *testing.T) {
+	t.Log("TestAnalyticsClient: Start a fake Analytics API")
+	var polls int
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /2/abtests":
+			w.Write([]byte(`{"abTestID":42,"index":"products","taskID":7}`))
+		case "GET /2/abtests/42":
+			polls++
+			status := "created"
+			if polls > 2 {
+				status = "active"
+			}
+			fmt.Fprintf(w, `{
+				"abTestID": 42,
+				"name": "ranking",
+				"status": %q,
+				"clickSignificance": 0.95,
+				"conversionSignificance": null,
+				"variants": [
+					{"index": "products", "trafficPercentage": 60, "clickThroughRate": 0.2, "searchCount": 100},
+					{"index": "products_new", "trafficPercentage": 40, "clickThroughRate": null, "searchCount": 0}
+				]
+			}`, status)
+		case "GET /2/abtests/43":
+			w.Write([]byte(`{"abTestID":43,"status":"failed"}`))
+		case "GET /2/abtests/44":
+			w.Write([]byte(`{"abTestID":44,"status":"created"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"Not found","status":404}`))
+		}
+	}))
+	defer server.Close()
+
+	transport := newTestTransport(server)
+	transport.onlyProvidedHosts = true
+	c := &analyticsClient{transport: transport}
+
+	abTest := ABTest{
+		Name:  "ranking",
+		EndAt: "2030-01-01T00:00:00Z",
+		Variants: []ABTestVariant{
+			{Index: "products", TrafficPercentage: 60},
+			{Index: "products_new", TrafficPercentage: 40},
+		},
+	}
+
+	t.Log("TestAnalyticsClient: Create an A/B test and wait for it to be active")
+	{
+		res, err := c.AddABTest(abTest)
+		require.Nil(t, err, "should create the A/B test without error")
+		require.Equal(t, 42, res.ABTestID)
+
+		test, err := c.WaitForABTest(res.ABTestID)
+		require.Nil(t, err, "should wait for the A/B test without error")
+		require.Equal(t, ABTestStatusActive, test.Status)
+		require.Equal(t, 3, polls)
+
+		require.Equal(t, 0.95, *test.ClickSignificance)
+		require.Nil(t, test.ConversionSignificance)
+		require.Len(t, test.Variants, 2)
+		require.Equal(t, 0.2, *test.Variants[0].ClickThroughRate)
+		require.Nil(t, test.Variants[1].ClickThroughRate)
+	}
+
+	t.Log("TestAnalyticsClient: Check failed A/B tests")
+	{
+		_, err := c.WaitForABTest(43)
+		require.NotNil(t, err, "should report the failed A/B test")
+	}
+
+	t.Log("TestAnalyticsClient: Check that the wait is bounded")
+	{
+		start := time.Now()
+		opts := &RequestOptions{Timeout: 200 * time.Millisecond, WaitSchedule: &WaitSchedule{Initial: 10 * time.Millisecond, Max: 50 * time.Millisecond}}
+		test, err := c.WaitForABTestWithRequestOptions(44, opts)
+		require.NotNil(t, err, "should give up waiting for the A/B test")
+		require.Equal(t, "created", test.Status)
+		require.True(t, time.Since(start) < time.Second)
+	}
+
+	t.Log("TestAnalyticsClient: Check the validation of the A/B tests")
+	{
+		invalid := abTest
+		invalid.Variants = []ABTestVariant{{Index: "products", TrafficPercentage: 60}, {Index: "products_new", TrafficPercentage: 60}}
+		_, err := c.AddABTest(invalid)
+		require.NotNil(t, err, "should reject traffic percentages not adding up to 100")
+
+		invalid = abTest
+		invalid.EndAt = "tomorrow"
+		_, err = c.AddABTest(invalid)
+		require.NotNil(t, err, "should reject invalid end dates")
+
+		_, err = NewAnalyticsClient("appID", "apiKey", "fr")
+		require.NotNil(t, err, "should reject unknown regions")
+	}
+}
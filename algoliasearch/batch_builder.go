@@ -0,0 +1,100 @@
+package algoliasearch
+
+// BatchBuilder builds the operations of a batch request (see Index.Batch
+// and Client.Batch) without having to spell out their actions:
+//
+//	operations, err := algoliasearch.NewBatchBuilder().
+//		Add(algoliasearch.Object{"name": "new"}).
+//		PartialUpdate(algoliasearch.Object{"objectID": "1", "stock": 0}, false).
+//		Delete("2").
+//		Operations()
+//
+// The operations are validated as they are added: the first invalid one,
+// e.g. an update without `objectID`, is reported by Operations and
+// IndexedOperations.
+type BatchBuilder struct {
+	operations []BatchOperation
+	err        error
+}
+
+// NewBatchBuilder returns a BatchBuilder without any operation.
+func NewBatchBuilder() *BatchBuilder {
+	return &BatchBuilder{}
+}
+
+// Add adds the `object`, whose objectID is generated by the engine if
+// missing.
+func (b *BatchBuilder) Add(object Object) *BatchBuilder {
+	return b.append(ActionAddObject, object)
+}
+
+// Update replaces the object identified by the `objectID` attribute of the
+// `object`, creating it if needed.
+func (b *BatchBuilder) Update(object Object) *BatchBuilder {
+	return b.append(ActionUpdateObject, object)
+}
+
+// PartialUpdate updates the attributes of the `object`, identified by its
+// `objectID` attribute. If `createIfNotExists` is `false`, the object is not
+// created if it does not exist yet.
+func (b *BatchBuilder) PartialUpdate(object Object, createIfNotExists bool) *BatchBuilder {
+	action := ActionPartialUpdateObject
+	if !createIfNotExists {
+		action = ActionPartialUpdateObjectNoCreate
+	}
+	return b.append(action, object)
+}
+
+// Delete deletes the object identified by the `objectID`.
+func (b *BatchBuilder) Delete(objectID string) *BatchBuilder {
+	return b.append(ActionDeleteObject, Object{"objectID": objectID})
+}
+
+// Clear removes all the objects of the index.
+func (b *BatchBuilder) Clear() *BatchBuilder {
+	return b.append(ActionClear, nil)
+}
+
+// Operations returns the operations built so far, in order, or the error of
+// the first invalid one.
+func (b *BatchBuilder) Operations() ([]BatchOperation, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	return b.operations, nil
+}
+
+// IndexedOperations is the same as Operations but targets the `indexName`
+// index, to be sent with Client.Batch. The operations of several builders
+// can be appended to target several indices in the same request.
+func (b *BatchBuilder) IndexedOperations(indexName string) ([]BatchOperationIndexed, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	operations := make([]BatchOperationIndexed, len(b.operations))
+	for n, op := range b.operations {
+		operations[n] = BatchOperationIndexed{
+			BatchOperation: op,
+			IndexName:      indexName,
+		}
+	}
+	return operations, nil
+}
+
+// append adds an operation with the given `action` and `body`, recording an
+// error instead if the operation is invalid.
+func (b *BatchBuilder) append(action string, body Object) *BatchBuilder {
+	if b.err != nil {
+		return b
+	}
+
+	op := BatchOperation{Action: action}
+	if body != nil {
+		op.Body = body
+	}
+	if b.err = checkBatchOperation(len(b.operations), op); b.err == nil {
+		b.operations = append(b.operations, op)
+	}
+	return b
+}
@@ -0,0 +1,56 @@
+package algoliasearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchBuilder(t *testing.T) {
+	t.Log("TestBatchBuilder: Check that the operations are built in order")
+	{
+		operations, err := NewBatchBuilder().
+			Clear().
+			Add(Object{"name": "new"}).
+			Update(Object{"objectID": "1", "name": "replaced"}).
+			PartialUpdate(Object{"objectID": "2", "stock": 0}, true).
+			PartialUpdate(Object{"objectID": "3", "stock": 0}, false).
+			Delete("4").
+			Operations()
+		require.Nil(t, err)
+		require.Equal(t, []BatchOperation{
+			{Action: "clear"},
+			{Action: "addObject", Body: Object{"name": "new"}},
+			{Action: "updateObject", Body: Object{"objectID": "1", "name": "replaced"}},
+			{Action: "partialUpdateObject", Body: Object{"objectID": "2", "stock": 0}},
+			{Action: "partialUpdateObjectNoCreate", Body: Object{"objectID": "3", "stock": 0}},
+			{Action: "deleteObject", Body: Object{"objectID": "4"}},
+		}, operations)
+	}
+
+	t.Log("TestBatchBuilder: Check that the indexed operations target the given index")
+	{
+		operations, err := NewBatchBuilder().Delete("1").IndexedOperations("products")
+		require.Nil(t, err)
+		require.Equal(t, []BatchOperationIndexed{
+			{BatchOperation: BatchOperation{Action: "deleteObject", Body: Object{"objectID": "1"}}, IndexName: "products"},
+		}, operations)
+	}
+
+	t.Log("TestBatchBuilder: Check that the first invalid operation is reported")
+	{
+		b := NewBatchBuilder().
+			Add(Object{"name": "no objectID needed"}).
+			PartialUpdate(Object{"stock": 0}, true).
+			Delete("")
+
+		_, err := b.Operations()
+		require.EqualError(t, err, "Invalid \"partialUpdateObject\" batch operation #1: Cannot extract `objectID` field from Object")
+
+		_, err = b.IndexedOperations("products")
+		require.NotNil(t, err)
+
+		_, err = NewBatchBuilder().Delete("").Operations()
+		require.EqualError(t, err, "Invalid \"deleteObject\" batch operation #0: `objectID` field is empty")
+	}
+}
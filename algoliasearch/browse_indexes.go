@@ -0,0 +1,166 @@
+package algoliasearch
+
+import (
+	"fmt"
+	"sync"
+)
+
+// defaultBrowseConcurrency is the number of indexes browsed concurrently by
+// Client.BrowseIndexes if none is specified.
+const defaultBrowseConcurrency = 4
+
+// IndexedRecord is a record returned by Client.BrowseIndexes along with the
+// name of the index it belongs to.
+type IndexedRecord struct {
+	IndexName string
+	Record    Map
+}
+
+// IndexesIterator is used by Client.BrowseIndexes to iterate over the
+// records of several indexes.
+type IndexesIterator interface {
+	// Next returns the next record of any of the browsed indexes. The records
+	// of a given index are returned in order but they are interleaved with
+	// the ones of the other indexes. Once all the records were returned,
+	// NoMoreHitsErr is returned. If an index cannot be browsed, a
+	// `*BrowseIndexErr` is returned and the iteration stops.
+	Next() (IndexedRecord, error)
+
+	// Close stops browsing the indexes. It should be called if the iteration
+	// is stopped before Next returns an error.
+	Close()
+}
+
+// BrowseIndexErr is the error returned by IndexesIterator.Next when the
+// index `IndexName` cannot be browsed.
+type BrowseIndexErr struct {
+	IndexName string
+	Err       error
+}
+
+func (e *BrowseIndexErr) Error() string {
+	return fmt.Sprintf("Cannot browse index %q: %s", e.IndexName, e.Err)
+}
+
+// Unwrap returns the error which stopped the browse.
+func (e *BrowseIndexErr) Unwrap() error {
+	return e.Err
+}
+
+type indexedRecordOrErr struct {
+	record IndexedRecord
+	err    error
+}
+
+type indexesIterator struct {
+	items chan indexedRecordOrErr
+	done  chan struct{}
+	once  sync.Once
+	err   error
+}
+
+func (c *client) BrowseIndexes(names []string, params Map, concurrency int) (IndexesIterator, error) {
+	return c.BrowseIndexesWithRequestOptions(names, params, concurrency, nil)
+}
+
+func (c *client) BrowseIndexesWithRequestOptions(names []string, params Map, concurrency int, opts *RequestOptions) (IndexesIterator, error) {
+	if err := checkQuery(params); err != nil {
+		return nil, err
+	}
+
+	if concurrency <= 0 {
+		concurrency = defaultBrowseConcurrency
+	}
+	if concurrency > len(names) {
+		concurrency = len(names)
+	}
+
+	queue := make(chan string, len(names))
+	for _, name := range names {
+		queue <- name
+	}
+	close(queue)
+
+	it := &indexesIterator{
+		items: make(chan indexedRecordOrErr, concurrency),
+		done:  make(chan struct{}),
+	}
+
+	var wg sync.WaitGroup
+	for n := 0; n < concurrency; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range queue {
+				if !it.browse(c.InitIndex(name), name, params, opts) {
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(it.items)
+	}()
+
+	return it, nil
+}
+
+// browse sends all the records of the `index` to the iterator. It returns
+// `false` if the iteration was stopped, either because the iterator was
+// closed or because the index could not be browsed.
+func (it *indexesIterator) browse(index Index, name string, params Map, opts *RequestOptions) bool {
+	records, err := index.BrowseAllWithRequestOptions(params, opts)
+	if err == NoMoreHitsErr {
+		return true
+	}
+
+	for err == nil {
+		var record Map
+		if record, err = records.Next(); err == nil {
+			if !it.send(indexedRecordOrErr{record: IndexedRecord{IndexName: name, Record: record}}) {
+				return false
+			}
+		}
+	}
+
+	if err == NoMoreHitsErr {
+		return true
+	}
+
+	it.send(indexedRecordOrErr{err: &BrowseIndexErr{IndexName: name, Err: err}})
+	return false
+}
+
+// send sends the `item` to the iterator unless it is closed.
+func (it *indexesIterator) send(item indexedRecordOrErr) bool {
+	select {
+	case it.items <- item:
+		return true
+	case <-it.done:
+		return false
+	}
+}
+
+func (it *indexesIterator) Next() (IndexedRecord, error) {
+	if it.err != nil {
+		return IndexedRecord{}, it.err
+	}
+
+	item, ok := <-it.items
+	if !ok {
+		it.err = NoMoreHitsErr
+	} else if item.err != nil {
+		it.err = item.err
+		it.Close()
+	}
+
+	return item.record, it.err
+}
+
+func (it *indexesIterator) Close() {
+	it.once.Do(func() {
+		close(it.done)
+	})
+}
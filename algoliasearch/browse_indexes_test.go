@@ -0,0 +1,71 @@
+package algoliasearch
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBrowseIndexes(t *testing.T) {
+	t.Log("TestBrowseIndexes: Start a server holding two records per index")
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/1/indexes/tenant_a/browse", "/1/indexes/tenant_b/browse", "/1/indexes/tenant_c/browse":
+			w.Write([]byte(`{"hits":[{"objectID":"1"},{"objectID":"2"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"Index does not exist","status":404}`))
+		}
+	}))
+	defer server.Close()
+	c := &client{transport: newTestTransport(server)}
+
+	t.Log("TestBrowseIndexes: Check that the records of all the indexes are returned")
+	{
+		it, err := c.BrowseIndexes([]string{"tenant_a", "tenant_b", "tenant_c"}, nil, 2)
+		require.Nil(t, err)
+
+		var records []string
+		for {
+			record, err := it.Next()
+			if err == NoMoreHitsErr {
+				break
+			}
+			require.Nil(t, err)
+			records = append(records, record.IndexName+"/"+record.Record["objectID"].(string))
+		}
+
+		sort.Strings(records)
+		require.Equal(t, []string{"tenant_a/1", "tenant_a/2", "tenant_b/1", "tenant_b/2", "tenant_c/1", "tenant_c/2"}, records)
+	}
+
+	t.Log("TestBrowseIndexes: Check that a failing index stops the iteration")
+	{
+		it, err := c.BrowseIndexes([]string{"missing"}, nil, 0)
+		require.Nil(t, err)
+
+		_, err = it.Next()
+		require.NotNil(t, err)
+		e, ok := err.(*BrowseIndexErr)
+		require.True(t, ok, "should be a *BrowseIndexErr")
+		require.Equal(t, "missing", e.IndexName)
+		require.True(t, IsNotFound(err))
+
+		_, err = it.Next()
+		require.NotNil(t, err)
+	}
+
+	t.Log("TestBrowseIndexes: Check that the iteration can be stopped")
+	{
+		it, err := c.BrowseIndexes([]string{"tenant_a", "tenant_b", "tenant_c"}, nil, 1)
+		require.Nil(t, err)
+
+		_, err = it.Next()
+		require.Nil(t, err)
+		it.Close()
+		it.Close()
+	}
+}
@@ -0,0 +1,61 @@
+package algoliasearch
+
+import "fmt"
+
+func checkBatchOperations(operations []BatchOperation) error {
+	for n, op := range operations {
+		if err := checkBatchOperation(n, op); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkBatchOperation checks the action of the `n`th operation of a batch
+// along with the body it requires, as the API rejects the whole batch with an
+// opaque error otherwise.
+func checkBatchOperation(n int, op BatchOperation) error {
+	switch op.Action {
+	case ActionAddObject:
+		if op.Body == nil {
+			return fmt.Errorf("Invalid %q batch operation #%d: the body is missing", op.Action, n)
+		}
+
+	case ActionUpdateObject, ActionPartialUpdateObject, ActionPartialUpdateObjectNoCreate, ActionDeleteObject:
+		if err := checkBatchObjectID(op.Body); err != nil {
+			return fmt.Errorf("Invalid %q batch operation #%d: %s", op.Action, n, err)
+		}
+
+	case ActionClear, ActionDelete:
+
+	default:
+		return fmt.Errorf("Invalid batch operation #%d: unknown action %q", n, op.Action)
+	}
+
+	return nil
+}
+
+// checkBatchObjectID checks that the `body` of a batch operation holds a
+// non-empty `objectID`. Only map bodies can be checked: the other ones, e.g.
+// structs, are left to the API.
+func checkBatchObjectID(body interface{}) error {
+	var m map[string]interface{}
+	switch b := body.(type) {
+	case nil:
+		return fmt.Errorf("the body is missing")
+	case Object:
+		m = b
+	case Map:
+		m = b
+	case map[string]interface{}:
+		m = b
+	default:
+		return nil
+	}
+
+	objectID, err := Object(m).ObjectID()
+	if err == nil && objectID == "" {
+		err = fmt.Errorf("`objectID` field is empty")
+	}
+	return err
+}
@@ -0,0 +1,50 @@
+package algoliasearch
+
+import "fmt"
+
+func checkDictionarySettings(settings DictionarySettings) error {
+	entries := settings.DisableStandardEntries
+	if entries == nil {
+		return nil
+	}
+
+	for name, languages := range map[DictionaryName]map[string]bool{
+		DictionaryPlurals:   entries.Plurals,
+		DictionaryStopwords: entries.Stopwords,
+		DictionaryCompounds: entries.Compounds,
+	} {
+		for language := range languages {
+			if language == "" {
+				return fmt.Errorf("Invalid standard entries of the %s dictionary: the language should not be empty", name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkDictionaryName checks that the `dictionary` is one of the known ones,
+// as unknown dictionaries are only reported by the API as missing routes.
+func checkDictionaryName(dictionary DictionaryName) error {
+	switch dictionary {
+	case DictionaryStopwords, DictionaryPlurals, DictionaryCompounds:
+		return nil
+	}
+	return fmt.Errorf("Invalid dictionary %q: should be one of %q, %q or %q", dictionary, DictionaryStopwords, DictionaryPlurals, DictionaryCompounds)
+}
+
+func checkSearchDictionaryEntriesParams(dictionary DictionaryName, params SearchDictionaryEntriesParams) error {
+	if err := checkDictionaryName(dictionary); err != nil {
+		return err
+	}
+
+	if params.Page < 0 {
+		return fmt.Errorf("`page` should be positive, got %d", params.Page)
+	}
+
+	if params.HitsPerPage < 0 {
+		return fmt.Errorf("`hitsPerPage` should be positive, got %d", params.HitsPerPage)
+	}
+
+	return nil
+}
@@ -1,5 +1,13 @@
 package algoliasearch
 
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+	"unicode"
+)
+
 func checkGenerateSecuredAPIKey(params Map) error {
 	if err := checkQuery(params, "userToken", "validUntil", "restrictIndices", "referers", "restrictSources"); err != nil {
 		return err
@@ -7,7 +15,7 @@ func checkGenerateSecuredAPIKey(params Map) error {
 
 	for k, v := range params {
 		switch k {
-		case "userToken", "restrictIndices", "restrictSources":
+		case "userToken", "restrictIndices":
 			if _, ok := v.(string); !ok {
 				return invalidType(k, "string")
 			}
@@ -17,9 +25,14 @@ func checkGenerateSecuredAPIKey(params Map) error {
 				return invalidType(k, "int")
 			}
 
+		case "restrictSources":
+			if err := checkRestrictSourcesParam(v); err != nil {
+				return err
+			}
+
 		case "referers":
-			if _, ok := v.([]string); !ok {
-				return invalidType(k, "[]string")
+			if err := checkReferersParam(v); err != nil {
+				return err
 			}
 		}
 	}
@@ -30,24 +43,139 @@ func checkGenerateSecuredAPIKey(params Map) error {
 func checkKey(params Map) error {
 	for k, v := range params {
 		switch k {
-		case "acl", "indexes", "referers":
+		case "acl", "indexes":
 			if _, ok := v.([]string); !ok {
 				return invalidType(k, "[]string")
 			}
 
+		case "referers":
+			if err := checkReferersParam(v); err != nil {
+				return err
+			}
+
+		case "restrictSources":
+			if err := checkRestrictSourcesParam(v); err != nil {
+				return err
+			}
+
 		case "description", "queryParameters":
 			if _, ok := v.(string); !ok {
 				return invalidType(k, "string")
 			}
 
-		case "maxHitsPerQuery", "maxQueriesPerIPPerHour", "validity":
+		case "maxHitsPerQuery", "maxQueriesPerIPPerHour":
 			if _, ok := v.(int); !ok {
 				return invalidType(k, "int")
 			}
 
+		case "validity":
+			switch v.(type) {
+			case int, time.Duration:
+				// OK
+			default:
+				return invalidType(k, "int or time.Duration")
+			}
+
 		default:
 		}
 	}
 
 	return nil
 }
+
+// checkACL checks that the given ACL is one of the known ones, as the API
+// silently ignores the unknown ACLs.
+func checkACL(acl ACL) error {
+	for _, a := range acls {
+		if acl == a {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("Invalid ACL %q", acl)
+}
+
+func checkKeyParams(params KeyParams) error {
+	if len(params.ACL) == 0 {
+		return fmt.Errorf("API keys should have at least one ACL")
+	}
+
+	for _, acl := range params.ACL {
+		if err := checkACL(acl); err != nil {
+			return err
+		}
+	}
+
+	if params.MaxQueriesPerIPPerHour < 0 {
+		return fmt.Errorf("`maxQueriesPerIPPerHour` should be positive, got %d", params.MaxQueriesPerIPPerHour)
+	}
+
+	if params.MaxHitsPerQuery < 0 {
+		return fmt.Errorf("`maxHitsPerQuery` should be positive, got %d", params.MaxHitsPerQuery)
+	}
+
+	if params.Validity < 0 {
+		return fmt.Errorf("`validity` should be positive, got %s", params.Validity)
+	}
+
+	if err := checkRestrictSources(params.RestrictSources); err != nil {
+		return err
+	}
+
+	if err := checkReferers(params.Referers); err != nil {
+		return err
+	}
+
+	return checkQuery(params.QueryParameters)
+}
+
+// checkRestrictSourcesParam checks that the `restrictSources` parameter is a
+// semicolon-separated list of IP addresses or CIDR networks, or an empty
+// string for no restriction.
+func checkRestrictSourcesParam(v interface{}) error {
+	sources, ok := v.(string)
+	if !ok {
+		return invalidType("restrictSources", "string")
+	}
+	if sources == "" {
+		return nil
+	}
+	return checkRestrictSources(strings.Split(sources, ";"))
+}
+
+// checkRestrictSources checks that the given `sources` are IP addresses
+// (e.g. `10.0.0.1`) or CIDR networks (e.g. `192.168.1.0/24`).
+func checkRestrictSources(sources []string) error {
+	for _, source := range sources {
+		if _, _, err := net.ParseCIDR(source); err == nil {
+			continue
+		}
+		if net.ParseIP(source) != nil {
+			continue
+		}
+		return fmt.Errorf("`restrictSources` should only contain IP addresses or CIDR networks (e.g. \"192.168.1.0/24\"), got %q", source)
+	}
+	return nil
+}
+
+// checkReferersParam checks that the `referers` parameter is a list of valid
+// referer patterns.
+func checkReferersParam(v interface{}) error {
+	referers, ok := v.([]string)
+	if !ok {
+		return invalidType("referers", "[]string")
+	}
+	return checkReferers(referers)
+}
+
+// checkReferers checks that the given `referers` are valid referer patterns,
+// i.e. non-empty URLs or domains without spaces where `*` stands for any
+// sequence of characters (e.g. `https://algolia.com/*` or `*.algolia.com`).
+func checkReferers(referers []string) error {
+	for _, referer := range referers {
+		if referer == "" || strings.IndexFunc(referer, unicode.IsSpace) >= 0 {
+			return fmt.Errorf("`referers` should only contain URL or domain patterns (e.g. \"https://algolia.com/*\"), got %q", referer)
+		}
+	}
+	return nil
+}
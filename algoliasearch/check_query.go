@@ -1,5 +1,7 @@
 package algoliasearch
 
+import "fmt"
+
 func checkQuery(query Map, ignore ...string) error {
 Outer:
 	for k, v := range query {
@@ -12,23 +14,23 @@ Outer:
 
 		switch k {
 		case "query",
-			"queryType",
 			"removeWordsIfNoResults",
 			"highlightPreTag",
 			"highlightPostTag",
 			"snippetEllipsisText",
 			"filters",
-			"aroundLatLng",
-			"exactOnSingleWordQuery",
-			"sortFacetValuesBy":
+			"exactOnSingleWordQuery":
 			if _, ok := v.(string); !ok {
 				return invalidType(k, "string")
 			}
 
+		case "sortFacetValuesBy":
+			if err := checkSortFacetValuesBy(v); err != nil {
+				return err
+			}
+
 		case "attributesToRetrieve",
 			"disableTypoToleranceOnAttributes",
-			"attributesToSnippet",
-			"attributesToHighlight",
 			"alternativesAsExact",
 			"responseFields",
 			"disableExactOnAttributes":
@@ -45,26 +47,73 @@ Outer:
 			"maxValuesPerFacet",
 			"aroundPrecision",
 			"minimumAroundRadius",
-			"maxFacetHits",
 			"offset",
 			"length":
 			if _, ok := v.(int); !ok {
 				return invalidType(k, "int")
 			}
 
+		case "relevancyStrictness":
+			if err := checkRelevancyStrictness(v); err != nil {
+				return err
+			}
+
+		case "maxFacetHits":
+			if err := checkMaxFacetHits(v); err != nil {
+				return err
+			}
+
 		case "allowTyposOnNumericTokens",
 			"advancedSyntax",
 			"analytics",
+			"clickAnalytics",
 			"synonyms",
 			"replaceSynonymsInHighlight",
 			"aroundLatLngViaIP",
 			"facetingAfterDistinct",
 			"restrictHighlightAndSnippetArrays",
-			"percentileComputation":
+			"percentileComputation",
+			"decompoundQuery",
+			"enableReRanking",
+			"enableRules",
+			"enablePersonalization",
+			"enableABTest":
 			if _, ok := v.(bool); !ok {
 				return invalidType(k, "bool")
 			}
 
+		case "queryLanguages",
+			"naturalLanguages":
+			if err := checkLanguages(k, v); err != nil {
+				return err
+			}
+
+		case "personalizationImpact":
+			if err := checkPersonalizationImpact(v); err != nil {
+				return err
+			}
+
+		case "userToken":
+			if err := checkUserToken(v); err != nil {
+				return err
+			}
+
+		case "ruleContexts":
+			if err := checkRuleContexts(v); err != nil {
+				return err
+			}
+
+		case "queryType":
+			if err := checkQueryType(k, v); err != nil {
+				return err
+			}
+
+		case "attributesToHighlight",
+			"attributesToSnippet":
+			if err := checkHighlightAttributes(k, v); err != nil {
+				return err
+			}
+
 		case "removeStopWords",
 			"ignorePlurals":
 			switch v.(type) {
@@ -91,18 +140,21 @@ Outer:
 			}
 
 		case "numericFilters",
-			"tagFilters":
-			switch v.(type) {
-			case string, []interface{}:
-				// OK
-			default:
-				return invalidType(k, "string or []interface{}")
+			"tagFilters",
+			"facetFilters",
+			"optionalFilters",
+			"reRankingApplyFilter":
+			if err := checkFilterGroups(k, v); err != nil {
+				return err
 			}
 
-		case "analyticsTags",
-			"restrictSearchableAttributes",
+		case "analyticsTags":
+			if err := checkAnalyticsTags(v); err != nil {
+				return err
+			}
+
+		case "restrictSearchableAttributes",
 			"facets",
-			"facetFilters",
 			"optionalWords":
 			switch v.(type) {
 			case string, []string:
@@ -111,13 +163,11 @@ Outer:
 				return invalidType(k, "string or []string")
 			}
 
-		case "insideBoundingBox",
+		case "aroundLatLng",
+			"insideBoundingBox",
 			"insidePolygon":
-			switch v.(type) {
-			case string, [][]float64:
-				// OK
-			default:
-				return invalidType(k, "string or [][]float64")
+			if err := checkGeoParam(k, v); err != nil {
+				return err
 			}
 
 		case "typoTolerance":
@@ -132,5 +182,62 @@ Outer:
 		}
 
 	}
+	return checkPagination(query)
+}
+
+// checkPagination checks that the offset/length pagination of the query,
+// if any, is complete, within the range accepted by the engine and not
+// mixed with the page/hitsPerPage pagination.
+func checkPagination(query Map) error {
+	offset, hasOffset := query["offset"].(int)
+	length, hasLength := query["length"].(int)
+
+	if !hasOffset && !hasLength {
+		return nil
+	}
+
+	if hasOffset != hasLength {
+		return fmt.Errorf("`offset` and `length` should be used together")
+	}
+
+	for _, k := range []string{"page", "hitsPerPage"} {
+		if _, ok := query[k]; ok {
+			return fmt.Errorf("`offset` and `length` cannot be used with `%s`", k)
+		}
+	}
+
+	if offset < 0 {
+		return fmt.Errorf("`offset` should be positive, got %d", offset)
+	}
+	if length < 0 || length > 1000 {
+		return fmt.Errorf("`length` should be between 0 and 1000, got %d", length)
+	}
+
+	return nil
+}
+
+// checkMaxFacetHits checks that the `maxFacetHits` setting or query
+// parameter is within the range accepted by the engine.
+func checkMaxFacetHits(v interface{}) error {
+	n, ok := v.(int)
+	if !ok {
+		return invalidType("maxFacetHits", "int")
+	}
+	if n < 1 || n > 100 {
+		return fmt.Errorf("`maxFacetHits` should be between 1 and 100, got %d", n)
+	}
+	return nil
+}
+
+// checkSortFacetValuesBy checks that the `sortFacetValuesBy` setting or query
+// parameter is either `count` or `alpha`.
+func checkSortFacetValuesBy(v interface{}) error {
+	sortBy, ok := v.(string)
+	if !ok {
+		return invalidType("sortFacetValuesBy", "string")
+	}
+	if sortBy != "count" && sortBy != "alpha" {
+		return fmt.Errorf("`sortFacetValuesBy` should be \"count\" or \"alpha\", got %q", sortBy)
+	}
 	return nil
 }
@@ -1,11 +1,40 @@
 package algoliasearch
 
+import (
+	"encoding/json"
+	"fmt"
+)
+
 func checkRule(rule Rule) error {
 	return checkRules([]Rule{rule})
 }
 
 func checkRules(rules []Rule) error {
 	for _, rule := range rules {
+		for _, r := range rule.Validity {
+			if !r.Until.After(r.From) {
+				return fmt.Errorf("Invalid validity of rule %s: `Until` (%s) should be after `From` (%s)", rule.ObjectID, r.Until, r.From)
+			}
+		}
+
+		for _, c := range append([]RuleCondition{rule.Condition}, rule.Conditions...) {
+			if c.Pattern != "" && c.Anchoring == "" {
+				return fmt.Errorf("Invalid condition of rule %s: a pattern requires an anchoring", rule.ObjectID)
+			}
+			if c.Anchoring != "" {
+				if err := c.Anchoring.Validate(); err != nil {
+					return fmt.Errorf("Invalid condition of rule %s: %s", rule.ObjectID, err)
+				}
+			}
+			if c.Alternatives && c.Anchoring == "" {
+				return fmt.Errorf("Invalid condition of rule %s: alternatives require a pattern and its anchoring", rule.ObjectID)
+			}
+		}
+
+		if err := checkRuleConsequence(rule.Consequence); err != nil {
+			return fmt.Errorf("Invalid consequence of rule %s: %s", rule.ObjectID, err)
+		}
+
 		consequenceParams := rule.Consequence.Params
 
 		// The elements of the RuleConsequence's Params map are first checked
@@ -25,9 +54,13 @@ func checkRules(rules []Rule) error {
 			switch k {
 
 			case "query":
-				switch v.(type) {
-				case string, QueryIncrementalEdit:
+				switch v := v.(type) {
+				case string:
 					// OK
+				case QueryIncrementalEdit:
+					if err := checkQueryIncrementalEdit(v); err != nil {
+						return err
+					}
 				default:
 					return invalidType(k, "string or QueryIncrementalEdit")
 				}
@@ -47,30 +80,124 @@ func checkRules(rules []Rule) error {
 	return nil
 }
 
-func checkSearchRulesParams(params Map) error {
-	for k, v := range params {
-		switch k {
+func checkRuleConsequence(c RuleConsequence) error {
+	positions := make(map[int]bool)
+	for _, p := range c.Promote {
+		if p.ObjectID == "" {
+			return fmt.Errorf("promoted objects should have an objectID")
+		}
+		if p.Position < 0 {
+			return fmt.Errorf("promoted object %s should have a non-negative position", p.ObjectID)
+		}
+		if positions[p.Position] {
+			return fmt.Errorf("several objects are promoted at position %d", p.Position)
+		}
+		positions[p.Position] = true
+	}
 
-		case "query",
-			"context":
-			if _, ok := v.(string); !ok {
-				return invalidType(k, "string")
+	// The objects of a group occupy consecutive positions, none of which can
+	// be shared with another promoted object.
+	for _, g := range c.PromoteGroups {
+		if len(g.ObjectIDs) == 0 {
+			return fmt.Errorf("groups of promoted objects should have at least one objectID")
+		}
+		if g.Position < 0 {
+			return fmt.Errorf("group of promoted objects %v should have a non-negative position", g.ObjectIDs)
+		}
+		for n, objectID := range g.ObjectIDs {
+			if objectID == "" {
+				return fmt.Errorf("promoted objects should have an objectID")
 			}
-
-		case "anchoring":
-			if _, ok := v.(RulePatternAnchoring); !ok {
-				return invalidType(k, "RulePatternAnchoring")
+			if positions[g.Position+n] {
+				return fmt.Errorf("several objects are promoted at position %d", g.Position+n)
 			}
+			positions[g.Position+n] = true
+		}
+	}
 
-		case "page",
-			"hitsPerPage":
-			if _, ok := v.(int); !ok {
-				return invalidType(k, "int")
-			}
+	for _, h := range c.Hide {
+		if h.ObjectID == "" {
+			return fmt.Errorf("hidden objects should have an objectID")
+		}
+	}
+
+	if err := checkUserData(c.UserData); err != nil {
+		return err
+	}
+
+	return checkRenderingContent(c.RenderingContent)
+}
+
+// checkUserData checks that the `userData` of a rule consequence or of the
+// settings, if any, is encoded as a JSON object or array.
+func checkUserData(userData interface{}) error {
+	if userData == nil {
+		return nil
+	}
 
+	data, err := json.Marshal(userData)
+	if err != nil {
+		return fmt.Errorf("userData cannot be encoded: %s", err)
+	}
+	if len(data) == 0 || (data[0] != '{' && data[0] != '[') {
+		return fmt.Errorf("userData should be a JSON object or array")
+	}
+
+	return nil
+}
+
+func checkQueryIncrementalEdit(edit QueryIncrementalEdit) error {
+	for _, e := range edit.AllEdits() {
+		if e.Delete == "" {
+			return fmt.Errorf("query edits should have a word to delete")
+		}
+
+		switch e.Type {
+		case EditTypeRemove:
+			if e.Insert != "" {
+				return fmt.Errorf("query edits of type %q should not have a word to insert", e.Type)
+			}
+		case EditTypeReplace:
+			if e.Insert == "" {
+				return fmt.Errorf("query edits of type %q should have a word to insert", e.Type)
+			}
 		default:
+			return fmt.Errorf("query edits should be of type %q or %q, got %q", EditTypeRemove, EditTypeReplace, e.Type)
+		}
+	}
+
+	return nil
+}
+
+func checkRenderingContent(rc *RenderingContent) error {
+	if rc == nil || rc.FacetOrdering == nil {
+		return nil
+	}
+
+	for facet, order := range rc.FacetOrdering.Values {
+		switch order.SortRemainingBy {
+		case "", SortRemainingByCount, SortRemainingByAlpha, SortRemainingByHidden:
 			// OK
+		default:
+			return fmt.Errorf("sortRemainingBy of facet %s should be %q, %q or %q, got %q", facet, SortRemainingByCount, SortRemainingByAlpha, SortRemainingByHidden, order.SortRemainingBy)
+		}
+	}
+
+	return nil
+}
+
+func checkSearchRulesParams(params SearchRulesParams) error {
+	if params.Page < 0 {
+		return fmt.Errorf("`page` should be positive, got %d", params.Page)
+	}
 
+	if params.HitsPerPage < 0 {
+		return fmt.Errorf("`hitsPerPage` should be positive, got %d", params.HitsPerPage)
+	}
+
+	if params.Anchoring != "" {
+		if err := params.Anchoring.Validate(); err != nil {
+			return err
 		}
 	}
 
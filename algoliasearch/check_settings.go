@@ -21,18 +21,26 @@ func checkSettings(settings Map) error {
 			"responseFields",
 			"disablePrefixOnAttributes",
 			"disableExactOnAttributes",
-			"alternativesAsExact":
+			"alternativesAsExact",
+			"attributesToTransliterate":
 			if _, ok := v.([]string); !ok {
 				return invalidType(k, "[]string")
 			}
 
+		case "indexLanguages",
+			"queryLanguages":
+			if err := checkLanguages(k, v); err != nil {
+				return err
+			}
+
 		case "allowCompressionOfIntegerArray",
 			"advancedSyntax",
 			"allowTyposOnNumericTokens",
 			"replaceSynonymsInHighlight",
 			"forwardToSlaves",
 			"forwardToReplicas",
-			"restrictHighlightAndSnippetArrays":
+			"restrictHighlightAndSnippetArrays",
+			"attributeCriteriaComputedByMinProximity":
 			if _, ok := v.(bool); !ok {
 				return invalidType(k, "bool")
 			}
@@ -42,7 +50,6 @@ func checkSettings(settings Map) error {
 			"minProximity",
 			"minWordSizefor1Typo",
 			"minWordSizefor2Typos",
-			"maxFacetHits",
 			"paginationLimitedTo":
 			if _, ok := v.(int); !ok {
 				return invalidType(k, "int")
@@ -55,8 +62,7 @@ func checkSettings(settings Map) error {
 			"snippetEllipsisText",
 			"attributeForDistinct",
 			"removeWordsIfNoResults",
-			"exactOnSingleWordQuery",
-			"sortFacetValuesBy":
+			"exactOnSingleWordQuery":
 			if _, ok := v.(string); !ok {
 				return invalidType(k, "string")
 			}
@@ -94,6 +100,50 @@ func checkSettings(settings Map) error {
 				return invalidType(k, "string or []string")
 			}
 
+		case "maxFacetHits":
+			if err := checkMaxFacetHits(v); err != nil {
+				return err
+			}
+
+		case "sortFacetValuesBy":
+			if err := checkSortFacetValuesBy(v); err != nil {
+				return err
+			}
+
+		case "relevancyStrictness":
+			if err := checkRelevancyStrictness(v); err != nil {
+				return err
+			}
+
+		case "decompoundedAttributes":
+			if _, ok := v.(map[string][]string); !ok {
+				return invalidType(k, "map[string][]string")
+			}
+
+		case "customNormalization":
+			if _, ok := v.(map[string]map[string]string); !ok {
+				return invalidType(k, "map[string]map[string]string")
+			}
+
+		case "userData":
+			if err := checkUserData(v); err != nil {
+				return err
+			}
+
+		case "renderingContent":
+			switch v := v.(type) {
+			case RenderingContent:
+				if err := checkRenderingContent(&v); err != nil {
+					return err
+				}
+			case *RenderingContent:
+				if err := checkRenderingContent(v); err != nil {
+					return err
+				}
+			default:
+				return invalidType(k, "RenderingContent or *RenderingContent")
+			}
+
 		default:
 		}
 	}
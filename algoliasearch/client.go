@@ -1,14 +1,23 @@
 package algoliasearch
 
 import (
-	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 )
 
 type client struct {
-	transport *Transport
+	dryRun        bool
+	dryRunLog     io.Writer
+	indexPrefix   string
+	preSearchHook PreSearchHook
+	readOnly      bool
+	scoped        scopedClients
+	transport     *Transport
+	waitSched     WaitSchedule
 }
 
 // NewClient instantiates a new `Client` from the provided `appID` and
@@ -32,6 +41,14 @@ func (c *client) SetExtraHeader(key, value string) {
 	c.transport.setExtraHeader(key, value)
 }
 
+func (c *client) SetUserToken(token string) {
+	c.transport.setUserToken(token)
+}
+
+func (c *client) SetDebug(w io.Writer) {
+	c.transport.setDebug(w)
+}
+
 func (c *client) SetTimeout(connectTimeout, readTimeout int) {
 	c.transport.setTimeout(
 		time.Duration(connectTimeout)*time.Millisecond,
@@ -39,12 +56,53 @@ func (c *client) SetTimeout(connectTimeout, readTimeout int) {
 	)
 }
 
+func (c *client) SetTimeouts(timeouts Timeouts) {
+	c.transport.setTimeouts(timeouts)
+}
+
+func (c *client) SetMetricsHook(hook MetricsHook) {
+	c.transport.setMetricsHook(hook)
+}
+
+func (c *client) SetOnRetry(hook func(HostFailure)) {
+	c.transport.setOnRetry(hook)
+}
+
+func (c *client) SetOnHostFail(hook func(HostFailure)) {
+	c.transport.setOnHostFail(hook)
+}
+
+func (c *client) SetWaitSchedule(schedule WaitSchedule) {
+	c.waitSched = schedule
+}
+
+func (c *client) SetPreSearchHook(hook PreSearchHook) {
+	c.preSearchHook = hook
+}
+
+func (c *client) SetRateLimitRetryBudget(budget time.Duration) {
+	c.transport.setRateLimitBudget(budget)
+}
+
 func (c *client) SetMaxIdleConnsPerHosts(maxIdleConnsPerHost int) {
 	c.transport.setMaxIdleConnsPerHost(maxIdleConnsPerHost)
 }
 
 func (c *client) SetHTTPClient(client *http.Client) {
-	c.transport.httpClient = client
+	c.transport.setHTTPClient(client)
+}
+
+func (c *client) SetRequester(r Requester) {
+	c.transport.setRequester(r)
+}
+
+func (c *client) IsAlive() error {
+	return c.IsAliveWithRequestOptions(nil)
+}
+
+func (c *client) IsAliveWithRequestOptions(opts *RequestOptions) error {
+	var res isAliveRes
+	return c.request(&res, "GET", "/1/isalive", nil, read, opts)
 }
 
 func (c *client) ListIndexes() (indexes []IndexRes, err error) {
@@ -55,12 +113,108 @@ func (c *client) ListIndexesWithRequestOptions(opts *RequestOptions) (indexes []
 	var res listIndexesRes
 
 	err = c.request(&res, "GET", "/1/indexes", nil, read, opts)
-	indexes = res.Items
+	indexes = c.unprefixIndexes(res.Items)
 	return
 }
 
+func (c *client) ListIndexesByPage(page int) (res ListIndexesRes, err error) {
+	return c.ListIndexesByPageWithRequestOptions(page, nil)
+}
+
+func (c *client) ListIndexesByPageWithRequestOptions(page int, opts *RequestOptions) (res ListIndexesRes, err error) {
+	params := Map{"page": page}
+	err = c.request(&res, "GET", "/1/indexes", params, read, opts)
+	res.Items = c.unprefixIndexes(res.Items)
+	return
+}
+
+func (c *client) ListAllIndexes() (indexes []IndexRes, err error) {
+	return c.ListAllIndexesWithRequestOptions(nil)
+}
+
+func (c *client) ListAllIndexesWithRequestOptions(opts *RequestOptions) (indexes []IndexRes, err error) {
+	var res ListIndexesRes
+
+	for page := 0; ; page++ {
+		if res, err = c.ListIndexesByPageWithRequestOptions(page, opts); err != nil {
+			return
+		}
+
+		indexes = append(indexes, res.Items...)
+
+		// Stop as soon as the last page has been reached or if the page is
+		// empty to avoid looping forever on inconsistent answers. The page
+		// is not checked for emptiness if the index prefix may have
+		// filtered it out.
+		if (len(res.Items) == 0 && c.indexPrefix == "") || page+1 >= res.NbPages {
+			return
+		}
+	}
+}
+
 func (c *client) InitIndex(name string) Index {
-	return NewIndex(name, c)
+	return NewIndex(c.prefixedIndexName(name), c)
+}
+
+// prefixedIndexName returns the name of the index `name` once prefixed with
+// the index prefix of the client (see WithIndexPrefix).
+func (c *client) prefixedIndexName(name string) string {
+	return c.indexPrefix + name
+}
+
+// unprefixIndexes only keeps the indexes whose name starts with the index
+// prefix of the client, if any, and removes the prefix from their names, as
+// well as from the names of their primary and replica indexes.
+func (c *client) unprefixIndexes(indexes []IndexRes) []IndexRes {
+	if c.indexPrefix == "" {
+		return indexes
+	}
+
+	filtered := make([]IndexRes, 0, len(indexes))
+	for _, index := range indexes {
+		if !strings.HasPrefix(index.Name, c.indexPrefix) {
+			continue
+		}
+
+		index.Name = strings.TrimPrefix(index.Name, c.indexPrefix)
+		if index.Primary != "" {
+			index.Primary = strings.TrimPrefix(index.Primary, c.indexPrefix)
+		}
+		index.Replicas = c.unprefixReplicas(index.Replicas)
+		filtered = append(filtered, index)
+	}
+
+	return filtered
+}
+
+// prefixReplicas returns the entries of a `replicas` setting (see
+// ParseReplica) whose names are prefixed with the index prefix of the
+// client, if any.
+func (c *client) prefixReplicas(replicas []string) []string {
+	return c.mapReplicaNames(replicas, c.prefixedIndexName)
+}
+
+// unprefixReplicas returns the entries of a `replicas` setting (see
+// ParseReplica) whose names are stripped from the index prefix of the
+// client, if any.
+func (c *client) unprefixReplicas(replicas []string) []string {
+	return c.mapReplicaNames(replicas, func(name string) string {
+		return strings.TrimPrefix(name, c.indexPrefix)
+	})
+}
+
+func (c *client) mapReplicaNames(replicas []string, f func(name string) string) []string {
+	if c.indexPrefix == "" || replicas == nil {
+		return replicas
+	}
+
+	res := make([]string, len(replicas))
+	for n, r := range replicas {
+		replica := ParseReplica(r)
+		replica.Name = f(replica.Name)
+		res[n] = replica.String()
+	}
+	return res
 }
 
 func (c *client) ListKeys() (keys []Key, err error) {
@@ -110,6 +264,44 @@ func (c *client) ClearIndexWithRequestOptions(name string, opts *RequestOptions)
 	return index.ClearWithRequestOptions(opts)
 }
 
+func (c *client) GetTask(indexName string, taskID int) (res TaskStatusRes, err error) {
+	return c.GetTaskWithRequestOptions(indexName, taskID, nil)
+}
+
+func (c *client) GetTaskWithRequestOptions(indexName string, taskID int, opts *RequestOptions) (res TaskStatusRes, err error) {
+	index := c.InitIndex(indexName)
+	return index.GetStatusWithRequestOptions(taskID, opts)
+}
+
+func (c *client) WaitTask(indexName string, taskID int) error {
+	return c.WaitTaskWithRequestOptions(indexName, taskID, nil)
+}
+
+func (c *client) WaitTaskWithRequestOptions(indexName string, taskID int, opts *RequestOptions) error {
+	index := c.InitIndex(indexName)
+	return index.WaitTaskWithRequestOptions(taskID, opts)
+}
+
+func (c *client) GetAppTask(taskID int) (res TaskStatusRes, err error) {
+	return c.GetAppTaskWithRequestOptions(taskID, nil)
+}
+
+func (c *client) GetAppTaskWithRequestOptions(taskID int, opts *RequestOptions) (res TaskStatusRes, err error) {
+	path := fmt.Sprintf("/1/task/%d", taskID)
+	err = c.request(&res, "GET", path, nil, read, opts)
+	return
+}
+
+func (c *client) WaitAppTask(taskID int) error {
+	return c.WaitAppTaskWithRequestOptions(taskID, nil)
+}
+
+func (c *client) WaitAppTaskWithRequestOptions(taskID int, opts *RequestOptions) error {
+	return c.pollTask(func() (TaskStatusRes, error) {
+		return c.GetAppTaskWithRequestOptions(taskID, opts)
+	}, opts)
+}
+
 func (c *client) AddUserKey(ACL []string, params Map) (AddKeyRes, error) {
 	return c.AddAPIKey(ACL, params)
 }
@@ -126,10 +318,23 @@ func (c *client) AddAPIKeyWithRequestOptions(ACL []string, params Map, opts *Req
 		return
 	}
 
-	err = c.request(&res, "POST", "/1/keys/", req, read, opts)
+	err = c.request(&res, "POST", "/1/keys/", encodeKeyParams(req), read, opts)
 	return
 }
 
+func (c *client) AddAPIKeyWithParams(params KeyParams) (res AddKeyRes, err error) {
+	return c.AddAPIKeyWithParamsWithRequestOptions(params, nil)
+}
+
+func (c *client) AddAPIKeyWithParamsWithRequestOptions(params KeyParams, opts *RequestOptions) (res AddKeyRes, err error) {
+	if err = checkKeyParams(params); err != nil {
+		return
+	}
+
+	acl, m := params.toMap()
+	return c.AddAPIKeyWithRequestOptions(acl, m, opts)
+}
+
 func (c *client) UpdateUserKey(key string, params Map) (UpdateKeyRes, error) {
 	return c.UpdateAPIKey(key, params)
 }
@@ -144,10 +349,24 @@ func (c *client) UpdateAPIKeyWithRequestOptions(key string, params Map, opts *Re
 	}
 
 	path := "/1/keys/" + url.QueryEscape(key)
-	err = c.request(&res, "PUT", path, params, write, opts)
+	err = c.request(&res, "PUT", path, encodeKeyParams(params), write, opts)
 	return
 }
 
+func (c *client) UpdateAPIKeyWithParams(key string, params KeyParams) (res UpdateKeyRes, err error) {
+	return c.UpdateAPIKeyWithParamsWithRequestOptions(key, params, nil)
+}
+
+func (c *client) UpdateAPIKeyWithParamsWithRequestOptions(key string, params KeyParams, opts *RequestOptions) (res UpdateKeyRes, err error) {
+	if err = checkKeyParams(params); err != nil {
+		return
+	}
+
+	acl, m := params.toMap()
+	m["acl"] = acl
+	return c.UpdateAPIKeyWithRequestOptions(key, m, opts)
+}
+
 func (c *client) GetUserKey(key string) (Key, error) {
 	return c.GetAPIKey(key)
 }
@@ -192,26 +411,45 @@ func (c *client) GetLogsWithRequestOptions(params Map, opts *RequestOptions) (lo
 	return
 }
 
-func (c *client) MultipleQueries(queries []IndexedQuery, strategy string) (res []MultipleQueryRes, err error) {
+func (c *client) MultipleQueries(queries []IndexedQuery, strategy MultipleQueriesStrategy) (res []MultipleQueryRes, err error) {
 	return c.MultipleQueriesWithRequestOptions(queries, strategy, nil)
 }
 
-func (c *client) MultipleQueriesWithRequestOptions(queries []IndexedQuery, strategy string, opts *RequestOptions) (res []MultipleQueryRes, err error) {
-	if strategy == "" {
-		strategy = "none"
+func (c *client) MultipleQueriesWithRequestOptions(queries []IndexedQuery, strategy MultipleQueriesStrategy, opts *RequestOptions) (res []MultipleQueryRes, err error) {
+	switch strategy {
+	case "":
+		strategy = StrategyNone
+	case StrategyNone, StrategyStopIfEnoughMatches:
+		// OK
+	default:
+		err = fmt.Errorf("Invalid strategy %q: should be %q or %q", strategy, StrategyNone, StrategyStopIfEnoughMatches)
+		return
 	}
 
-	for _, q := range queries {
-		if err = checkQuery(q.Params); err != nil {
+	requests := make([]map[string]string, len(queries))
+	hookDurations := make([]time.Duration, len(queries))
+	for i, q := range queries {
+		params := q.Params
+		if q.Query != "" {
+			params = duplicateMap(q.Params)
+			params["query"] = q.Query
+		}
+
+		if c.preSearchHook != nil {
+			params = duplicateMap(params)
+			query, _ := params["query"].(string)
+			if params, hookDurations[i], err = runPreSearchHook(c.preSearchHook, q.IndexName, query, params); err != nil {
+				return
+			}
+		}
+
+		if err = checkQuery(params); err != nil {
 			return
 		}
-	}
 
-	requests := make([]map[string]string, len(queries))
-	for i, q := range queries {
 		requests[i] = map[string]string{
-			"indexName": q.IndexName,
-			"params":    encodeMap(q.Params),
+			"indexName": c.prefixedIndexName(q.IndexName),
+			"params":    encodeMap(params),
 		}
 	}
 
@@ -223,6 +461,14 @@ func (c *client) MultipleQueriesWithRequestOptions(queries []IndexedQuery, strat
 	var m multipleQueriesRes
 	err = c.request(&m, "POST", "/1/indexes/*/queries", body, search, opts)
 	res = m.Results
+	for i := range res {
+		res[i].Index = strings.TrimPrefix(res[i].Index, c.indexPrefix)
+	}
+	if len(res) == len(hookDurations) {
+		for i := range res {
+			res[i].PreSearchHookDuration = hookDurations[i]
+		}
+	}
 	return
 }
 
@@ -231,21 +477,48 @@ func (c *client) Batch(operations []BatchOperationIndexed) (res MultipleBatchRes
 }
 
 func (c *client) BatchWithRequestOptions(operations []BatchOperationIndexed, opts *RequestOptions) (res MultipleBatchRes, err error) {
-	// TODO: Use check functions of index.go
+	for n, op := range operations {
+		if op.IndexName == "" {
+			err = fmt.Errorf("Invalid batch operation #%d: the index name is missing", n)
+			return
+		}
+		if err = checkBatchOperation(n, op.BatchOperation); err != nil {
+			return
+		}
+	}
+
+	// The index names of the results are left unprefixed, so that the failed
+	// operations can be sent again as-is.
+	requests := operations
+	if c.indexPrefix != "" {
+		requests = make([]BatchOperationIndexed, len(operations))
+		for n, op := range operations {
+			op.IndexName = c.prefixedIndexName(op.IndexName)
+			requests[n] = op
+		}
+	}
 
 	request := map[string][]BatchOperationIndexed{
-		"requests": operations,
+		"requests": requests,
 	}
 
 	err = c.request(&res, "POST", "/1/indexes/*/batch", request, write, opts)
+
+	batchOperations := make([]BatchOperation, len(operations))
+	for n, op := range operations {
+		batchOperations[n] = op.BatchOperation
+	}
+	res.Operations = newBatchOperationsRes(batchOperations, res.ObjectIDs, err)
+	for n, op := range operations {
+		res.Operations[n].IndexName = op.IndexName
+	}
 	return
 }
 
 func (c *client) request(res interface{}, method, path string, body interface{}, typeCall int, opts *RequestOptions) error {
-	r, err := c.transport.request(method, path, body, typeCall, opts)
-	if err != nil {
-		return err
+	if c.readOnly && !isReadOnlyRequest(method, path) {
+		return &ReadOnlyErr{Method: method, Path: path}
 	}
 
-	return json.Unmarshal(r, res)
+	return c.transport.requestJSON(res, method, path, body, typeCall, opts)
 }
@@ -0,0 +1,451 @@
+package algoliasearch
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultConnectTimeout is the default `TLSHandshakeTimeout` of the
+// underlying http.Transport.
+const defaultConnectTimeout = 2 * time.Second
+
+// Environment variables read by NewClientFromEnv.
+const (
+	EnvApplicationID  = "ALGOLIA_APPLICATION_ID"
+	EnvAPIKey         = "ALGOLIA_API_KEY"
+	EnvHosts          = "ALGOLIA_HOSTS"
+	EnvConnectTimeout = "ALGOLIA_CONNECT_TIMEOUT"
+	EnvReadTimeout    = "ALGOLIA_READ_TIMEOUT"
+)
+
+// ClientOption configures a Client built by NewClientWithOptions or
+// NewClientFromEnv.
+type ClientOption func(*clientConfig)
+
+// clientConfig gathers the configuration of a Client before it is built, so
+// that the options can be given in any order.
+type clientConfig struct {
+	hosts           []string
+	hostsPerm       func(n int) []int
+	indexPrefix     string
+	timeouts        Timeouts
+	httpClient      *http.Client
+	requester       Requester
+	debug           io.Writer
+	headers         map[string]string
+	userToken       string
+	rateLimitBudget *time.Duration
+	preSearchHook   PreSearchHook
+	metricsHook     MetricsHook
+	onRetry         func(HostFailure)
+	onHostFail      func(HostFailure)
+	waitSchedule    WaitSchedule
+	transportTuning []func(*http.Transport)
+	noKeepAlives    bool
+	readOnly        bool
+	dryRun          bool
+	dryRunLog       io.Writer
+}
+
+// WithHosts makes the client connect to the given `hosts` first, before the
+// default Algolia hosts, like NewClientWithHosts does.
+func WithHosts(hosts ...string) ClientOption {
+	return func(c *clientConfig) {
+		c.hosts = hosts
+	}
+}
+
+// WithHostsSeed makes the order in which the fallback hosts are tried
+// reproducible: they are still shuffled, but with an RNG seeded with `seed`.
+// It is meant for tests and record/replay fixtures.
+func WithHostsSeed(seed int64) ClientOption {
+	return func(c *clientConfig) {
+		c.hostsPerm = seededPerm(seed)
+	}
+}
+
+// WithFixedHostsOrder makes the client try the fallback hosts in their
+// declared order (`-1`, `-2` then `-3`) instead of shuffling them to spread
+// the load. It is meant for tests and record/replay fixtures.
+func WithFixedHostsOrder() ClientOption {
+	return func(c *clientConfig) {
+		c.hostsPerm = identityPerm
+	}
+}
+
+// WithIndexPrefix prefixes the names of all the indexes the client deals
+// with by `prefix` (e.g. "staging_"), so that several environments can share
+// the same application: the prefix is added by InitIndex, MoveIndex,
+// CopyIndex, MultipleQueries, Batch and to the `replicas` setting while the
+// indexes listed by ListIndexes are restricted to the prefixed ones. The
+// index names returned by the client (listed indexes, replicas, multiple
+// queries results, etc.) or given to the PreSearchHook are unprefixed.
+func WithIndexPrefix(prefix string) ClientOption {
+	return func(c *clientConfig) {
+		c.indexPrefix = prefix
+	}
+}
+
+// WithTimeouts sets the timeouts of the connections (`TLSHandshakeTimeout`)
+// and of the responses (`ResponseHeaderTimeout`), like Client.SetTimeout
+// does. A zero `connectTimeout` keeps the default 2s timeout while a zero
+// `readTimeout` disables the response timeout.
+func WithTimeouts(connectTimeout, readTimeout time.Duration) ClientOption {
+	return func(c *clientConfig) {
+		c.timeouts.Connect = connectTimeout
+		c.timeouts.Read = readTimeout
+	}
+}
+
+// WithWriteTimeout sets the maximum time spent by each write of the requests
+// on the connection (see Timeouts.Write).
+func WithWriteTimeout(timeout time.Duration) ClientOption {
+	return func(c *clientConfig) {
+		c.timeouts.Write = timeout
+	}
+}
+
+// WithCallTimeout sets the deadline of each call of the client, including
+// the retries on the other hosts (see Timeouts.Total).
+func WithCallTimeout(timeout time.Duration) ClientOption {
+	return func(c *clientConfig) {
+		c.timeouts.Total = timeout
+	}
+}
+
+// WithMaxIdleConnsPerHost sets the maximum number of idle connections kept
+// per host (64 by default), like Client.SetMaxIdleConnsPerHosts does.
+func WithMaxIdleConnsPerHost(n int) ClientOption {
+	return withTransportTuning(func(t *http.Transport) {
+		t.MaxIdleConnsPerHost = n
+	})
+}
+
+// WithMaxConnsPerHost limits the number of connections per host, idle or
+// not. Zero, the default, means no limit.
+func WithMaxConnsPerHost(n int) ClientOption {
+	return withTransportTuning(func(t *http.Transport) {
+		t.MaxConnsPerHost = n
+	})
+}
+
+// WithIdleConnTimeout sets the time after which an idle connection is
+// closed. Zero, the default, means no limit.
+func WithIdleConnTimeout(timeout time.Duration) ClientOption {
+	return withTransportTuning(func(t *http.Transport) {
+		t.IdleConnTimeout = timeout
+	})
+}
+
+// WithTLSHandshakeTimeout sets the maximum time spent in the TLS handshake.
+// It overrides the connect timeout set with WithTimeouts.
+func WithTLSHandshakeTimeout(timeout time.Duration) ClientOption {
+	return withTransportTuning(func(t *http.Transport) {
+		t.TLSHandshakeTimeout = timeout
+	})
+}
+
+// WithExpectContinueTimeout sets the time to wait for the first response
+// headers after sending the request headers, for requests having an
+// `Expect: 100-continue` header.
+func WithExpectContinueTimeout(timeout time.Duration) ClientOption {
+	return withTransportTuning(func(t *http.Transport) {
+		t.ExpectContinueTimeout = timeout
+	})
+}
+
+// WithoutKeepAlives makes the client open a new connection for each request
+// instead of reusing the idle ones.
+func WithoutKeepAlives() ClientOption {
+	return func(c *clientConfig) {
+		c.noKeepAlives = true
+	}
+}
+
+// WithHTTP2 enables or disables HTTP/2 on the underlying transport. By
+// default, as the transport uses its own dialer, the requests are sent over
+// HTTP/1.1. Enabling HTTP/2 multiplexes the concurrent requests over a single
+// connection per host while disabling it explicitly guarantees that HTTP/1.1
+// is used even if the transport was configured otherwise.
+func WithHTTP2(enabled bool) ClientOption {
+	return withTransportTuning(func(t *http.Transport) {
+		t.ForceAttemptHTTP2 = enabled
+		if enabled {
+			t.TLSNextProto = nil
+		} else {
+			// A non-nil empty map disables HTTP/2.
+			t.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+		}
+	})
+}
+
+// withTransportTuning returns an option applying `tune` to the underlying
+// http.Transport. As for the timeouts, the connection tuning options only
+// apply if the transport of the HTTP client is an `*http.Transport`.
+func withTransportTuning(tune func(*http.Transport)) ClientOption {
+	return func(c *clientConfig) {
+		c.transportTuning = append(c.transportTuning, tune)
+	}
+}
+
+// WithHTTPClient makes the client perform its requests with the given HTTP
+// client, like Client.SetHTTPClient does. The timeouts set with
+// WithTimeouts, as well as the connection tuning options (e.g.
+// WithMaxConnsPerHost), only apply if its transport is an `*http.Transport`.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *clientConfig) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithRequester makes the client send its requests with the given
+// Requester, like Client.SetRequester does. It takes precedence over
+// WithHTTPClient.
+func WithRequester(r Requester) ClientOption {
+	return func(c *clientConfig) {
+		c.requester = r
+	}
+}
+
+// WithDebug dumps all the requests and responses of the client to `w`, like
+// Client.SetDebug does.
+func WithDebug(w io.Writer) ClientOption {
+	return func(c *clientConfig) {
+		c.debug = w
+	}
+}
+
+// WithExtraHeader adds a custom header to all the requests of the client,
+// like Client.SetExtraHeader does.
+func WithExtraHeader(key, value string) ClientOption {
+	return func(c *clientConfig) {
+		if c.headers == nil {
+			c.headers = make(map[string]string)
+		}
+		c.headers[key] = value
+	}
+}
+
+// WithUserToken identifies the end user all the requests of the client are
+// made for, like Client.SetUserToken does.
+func WithUserToken(token string) ClientOption {
+	return func(c *clientConfig) {
+		c.userToken = token
+	}
+}
+
+// WithRateLimitRetryBudget sets the maximum time spent waiting before
+// retrying rate-limited requests, like Client.SetRateLimitRetryBudget does.
+func WithRateLimitRetryBudget(budget time.Duration) ClientOption {
+	return func(c *clientConfig) {
+		c.rateLimitBudget = &budget
+	}
+}
+
+// WithPreSearchHook sets the hook called before each search query, like
+// Client.SetPreSearchHook does.
+func WithPreSearchHook(hook PreSearchHook) ClientOption {
+	return func(c *clientConfig) {
+		c.preSearchHook = hook
+	}
+}
+
+// WithMetricsHook reports the metrics of the connections of the client to
+// the given hook, like Client.SetMetricsHook does.
+func WithMetricsHook(hook MetricsHook) ClientOption {
+	return func(c *clientConfig) {
+		c.metricsHook = hook
+	}
+}
+
+// WithOnRetry sets the hook called each time a request is retried on
+// another host, like Client.SetOnRetry does.
+func WithOnRetry(hook func(HostFailure)) ClientOption {
+	return func(c *clientConfig) {
+		c.onRetry = hook
+	}
+}
+
+// WithOnHostFail sets the hook called each time a host is marked as down,
+// like Client.SetOnHostFail does.
+func WithOnHostFail(hook func(HostFailure)) ClientOption {
+	return func(c *clientConfig) {
+		c.onHostFail = hook
+	}
+}
+
+// WithWaitSchedule sets the polling schedule used to wait for tasks, like
+// Client.SetWaitSchedule does.
+func WithWaitSchedule(schedule WaitSchedule) ClientOption {
+	return func(c *clientConfig) {
+		c.waitSchedule = schedule
+	}
+}
+
+// WithReadOnly makes the client refuse to modify the application: all the
+// methods which would write (e.g. AddObject, SetSettings, DeleteIndex or the
+// API key management ones) return a `*ReadOnlyErr` without sending any
+// request, while the search, browse and retrieval methods keep working.
+func WithReadOnly() ClientOption {
+	return func(c *clientConfig) {
+		c.readOnly = true
+	}
+}
+
+// WithDryRun makes the destructive operations of the client (DeleteIndex,
+// ClearIndex, MoveIndex and the Delete, Clear, Move, DeleteBy,
+// DeleteByQuery, ClearRules and ClearSynonyms methods of the Index) only
+// report what they would do instead of performing it: they return a
+// `*DryRunErr` describing the request which would have been sent, along with
+// the number of records (or rules, or synonyms) it would have deleted, as
+// estimated by a search. Each report is also written to `w`, if non-nil. The
+// dry-run mode can also be enabled per request with RequestOptions.DryRun.
+func WithDryRun(w io.Writer) ClientOption {
+	return func(c *clientConfig) {
+		c.dryRun = true
+		c.dryRunLog = w
+	}
+}
+
+// NewClientWithOptions instantiates a new `Client` from the provided `appID`
+// and `apiKey`, configured by the given options. Contrary to the setters of
+// the Client, which should not be called while requests are being sent, the
+// options are applied before the client is returned.
+func NewClientWithOptions(appID, apiKey string, opts ...ClientOption) Client {
+	var cfg clientConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	var t *Transport
+	if len(cfg.hosts) > 0 {
+		t = NewTransportWithHosts(appID, apiKey, cfg.hosts)
+	} else {
+		t = NewTransport(appID, apiKey)
+	}
+
+	t.hostsPerm = cfg.hostsPerm
+
+	if cfg.httpClient != nil {
+		t.setHTTPClient(cfg.httpClient)
+	}
+
+	if cfg.requester != nil {
+		t.setRequester(cfg.requester)
+	}
+
+	if cfg.timeouts != (Timeouts{}) {
+		t.setTimeouts(cfg.timeouts)
+	}
+
+	for _, tune := range cfg.transportTuning {
+		t.tuneHTTPTransport(tune)
+	}
+
+	if cfg.noKeepAlives {
+		t.disableKeepAlives()
+	}
+
+	for k, v := range cfg.headers {
+		t.setExtraHeader(k, v)
+	}
+
+	if cfg.userToken != "" {
+		t.setUserToken(cfg.userToken)
+	}
+
+	if cfg.rateLimitBudget != nil {
+		t.setRateLimitBudget(*cfg.rateLimitBudget)
+	}
+
+	if cfg.debug != nil {
+		t.setDebug(cfg.debug)
+	}
+
+	if cfg.metricsHook != nil {
+		t.setMetricsHook(cfg.metricsHook)
+	}
+
+	t.setOnRetry(cfg.onRetry)
+	t.setOnHostFail(cfg.onHostFail)
+
+	return &client{
+		dryRun:        cfg.dryRun,
+		dryRunLog:     cfg.dryRunLog,
+		indexPrefix:   cfg.indexPrefix,
+		preSearchHook: cfg.preSearchHook,
+		readOnly:      cfg.readOnly,
+		transport:     t,
+		waitSched:     cfg.waitSchedule,
+	}
+}
+
+// NewClientFromEnv instantiates a new `Client` whose application ID and API
+// key are read from the ALGOLIA_APPLICATION_ID and ALGOLIA_API_KEY
+// environment variables. The optional ALGOLIA_HOSTS variable is a
+// comma-separated list of hosts (see WithHosts) while ALGOLIA_CONNECT_TIMEOUT
+// and ALGOLIA_READ_TIMEOUT are timeouts expressed in milliseconds (see
+// WithTimeouts). The given options are applied after the ones read from the
+// environment, hence take precedence. A non-nil error is returned if a
+// variable is missing or invalid.
+func NewClientFromEnv(opts ...ClientOption) (Client, error) {
+	appID := os.Getenv(EnvApplicationID)
+	if appID == "" {
+		return nil, fmt.Errorf("Cannot create client: environment variable %s is not set", EnvApplicationID)
+	}
+
+	apiKey := os.Getenv(EnvAPIKey)
+	if apiKey == "" {
+		return nil, fmt.Errorf("Cannot create client: environment variable %s is not set", EnvAPIKey)
+	}
+
+	var envOpts []ClientOption
+
+	if hosts := os.Getenv(EnvHosts); hosts != "" {
+		var list []string
+		for _, host := range strings.Split(hosts, ",") {
+			if host = strings.TrimSpace(host); host != "" {
+				list = append(list, host)
+			}
+		}
+		envOpts = append(envOpts, WithHosts(list...))
+	}
+
+	connectTimeout, err := timeoutFromEnv(EnvConnectTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	readTimeout, err := timeoutFromEnv(EnvReadTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if connectTimeout > 0 || readTimeout > 0 {
+		envOpts = append(envOpts, WithTimeouts(connectTimeout, readTimeout))
+	}
+
+	return NewClientWithOptions(appID, apiKey, append(envOpts, opts...)...), nil
+}
+
+// timeoutFromEnv returns the timeout, expressed in milliseconds, held by the
+// environment variable `name`. Zero is returned if the variable is not set.
+func timeoutFromEnv(name string) (time.Duration, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0, nil
+	}
+
+	ms, err := strconv.Atoi(value)
+	if err != nil || ms < 0 {
+		return 0, fmt.Errorf("Cannot create client: environment variable %s should be a positive number of milliseconds, got %q", name, value)
+	}
+
+	return time.Duration(ms) * time.Millisecond, nil
+}
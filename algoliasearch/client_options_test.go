@@ -0,0 +1,374 @@
+package algoliasearch
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClientWithOptions(t *testing.T) {
+	t.Log("TestNewClientWithOptions: Check the default configuration")
+	{
+		c := NewClientWithOptions("appid", "apikey").(*client)
+		require.Nil(t, c.transport.providedHosts)
+		require.Nil(t, c.transport.debug)
+		require.Equal(t, defaultRateLimitBudget, c.transport.rateLimitBudget)
+	}
+
+	t.Log("TestNewClientWithOptions: Check that the options are applied")
+	{
+		var buf bytes.Buffer
+		httpClient := &http.Client{Transport: defaultTransport(time.Second)}
+
+		c := NewClientWithOptions("appid", "apikey",
+			WithTimeouts(0, 5*time.Second),
+			WithHTTPClient(httpClient),
+			WithHosts("a.example.com", "b.example.com"),
+			WithDebug(&buf),
+			WithExtraHeader("X-Custom", "value"),
+			WithUserToken("user-42"),
+			WithRateLimitRetryBudget(0),
+			WithWriteTimeout(time.Second),
+			WithCallTimeout(10*time.Second),
+		).(*client)
+
+		require.Equal(t, []string{"a.example.com", "b.example.com"}, c.transport.providedHosts)
+		require.False(t, c.transport.httpClient == httpClient, "should copy the given HTTP client before configuring it")
+		require.Equal(t, defaultConnectTimeout, c.transport.httpClient.Transport.(*http.Transport).TLSHandshakeTimeout)
+		require.Equal(t, 5*time.Second, c.transport.httpClient.Transport.(*http.Transport).ResponseHeaderTimeout)
+		require.Equal(t, time.Duration(0), httpClient.Transport.(*http.Transport).ResponseHeaderTimeout, "should not modify the given HTTP client")
+		require.Equal(t, time.Second, c.transport.writeTimeout)
+		require.Equal(t, 10*time.Second, c.transport.totalTimeout)
+		require.NotNil(t, c.transport.debug)
+		require.Equal(t, "value", c.transport.headers["X-Custom"])
+		require.Equal(t, "user-42", c.transport.headers[userTokenHeader])
+		require.Equal(t, time.Duration(0), c.transport.rateLimitBudget)
+	}
+}
+
+func TestTransportTuning(t *testing.T) {
+	t.Log("TestTransportTuning: Check that the connection tuning options are applied")
+	{
+		c := NewClientWithOptions("appid", "apikey",
+			WithTimeouts(time.Second, 0),
+			WithMaxIdleConnsPerHost(8),
+			WithMaxConnsPerHost(16),
+			WithIdleConnTimeout(90*time.Second),
+			WithTLSHandshakeTimeout(3*time.Second),
+			WithExpectContinueTimeout(time.Second),
+			WithoutKeepAlives(),
+		).(*client)
+
+		transport := c.transport.httpClient.Transport.(*http.Transport)
+		require.Equal(t, 8, transport.MaxIdleConnsPerHost)
+		require.Equal(t, 16, transport.MaxConnsPerHost)
+		require.Equal(t, 90*time.Second, transport.IdleConnTimeout)
+		require.Equal(t, 3*time.Second, transport.TLSHandshakeTimeout)
+		require.Equal(t, time.Second, transport.ExpectContinueTimeout)
+		require.True(t, transport.DisableKeepAlives)
+		require.NotContains(t, c.transport.headers, "Connection")
+	}
+
+	t.Log("TestTransportTuning: Check that the default transport is left untouched")
+	{
+		c := NewClientWithOptions("appid", "apikey").(*client)
+		transport := c.transport.httpClient.Transport.(*http.Transport)
+		require.Equal(t, 64, transport.MaxIdleConnsPerHost)
+		require.False(t, transport.DisableKeepAlives)
+		require.Equal(t, "keep-alive", c.transport.headers["Connection"])
+	}
+}
+
+func TestHTTP2(t *testing.T) {
+	t.Log("TestHTTP2: Start a server supporting HTTP/2 and replying with the protocol used")
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"proto":"` + r.Proto + `"}`))
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	proto := func(opt ClientOption) string {
+		var cfg clientConfig
+		opt(&cfg)
+		transport := newTestTransport(server)
+		for _, tune := range cfg.transportTuning {
+			transport.tuneHTTPTransport(tune)
+		}
+
+		res, err := transport.request("GET", "/1/isalive", nil, read, nil)
+		require.Nil(t, err)
+		return string(res)
+	}
+
+	t.Log("TestHTTP2: Check that HTTP/2 can be enabled or disabled")
+	{
+		require.Equal(t, `{"proto":"HTTP/2.0"}`, proto(WithHTTP2(true)))
+		require.Equal(t, `{"proto":"HTTP/1.1"}`, proto(WithHTTP2(false)))
+	}
+}
+
+func TestHostsOrder(t *testing.T) {
+	t.Log("TestHostsOrder: Check that the fallback hosts can be tried in a fixed order")
+	{
+		c := NewClientWithOptions("appid", "apikey", WithFixedHostsOrder()).(*client)
+		require.Equal(t, []string{
+			"appid-dsn.algolia.net",
+			"appid-1.algolianet.com",
+			"appid-2.algolianet.com",
+			"appid-3.algolianet.com",
+		}, c.transport.hostsToTry(read))
+	}
+
+	t.Log("TestHostsOrder: Check that the shuffling is reproducible with a seed")
+	{
+		a := NewClientWithOptions("appid", "apikey", WithHostsSeed(42)).(*client)
+		b := NewClientWithOptions("appid", "apikey", WithHostsSeed(42)).(*client)
+		for i := 0; i < 10; i++ {
+			require.Equal(t, a.transport.hostsToTry(write), b.transport.hostsToTry(write))
+		}
+	}
+}
+
+func TestNewClientFromEnv(t *testing.T) {
+	defer os.Unsetenv(EnvApplicationID)
+	defer os.Unsetenv(EnvAPIKey)
+	defer os.Unsetenv(EnvHosts)
+	defer os.Unsetenv(EnvReadTimeout)
+
+	t.Log("TestNewClientFromEnv: Check that the credentials are required")
+	{
+		os.Unsetenv(EnvApplicationID)
+		os.Unsetenv(EnvAPIKey)
+		_, err := NewClientFromEnv()
+		require.NotNil(t, err, "should fail without application ID")
+
+		os.Setenv(EnvApplicationID, "appid")
+		_, err = NewClientFromEnv()
+		require.NotNil(t, err, "should fail without API key")
+	}
+
+	t.Log("TestNewClientFromEnv: Check that the optional variables are read")
+	{
+		os.Setenv(EnvAPIKey, "apikey")
+		os.Setenv(EnvHosts, "a.example.com, b.example.com,")
+		os.Setenv(EnvReadTimeout, "3000")
+
+		c, err := NewClientFromEnv(WithUserToken("user-42"))
+		require.Nil(t, err)
+		transport := c.(*client).transport
+		require.Equal(t, "appid", transport.appId)
+		require.Equal(t, []string{"a.example.com", "b.example.com"}, transport.providedHosts)
+		require.Equal(t, 3*time.Second, transport.httpClient.Transport.(*http.Transport).ResponseHeaderTimeout)
+		require.Equal(t, "user-42", transport.headers[userTokenHeader])
+	}
+
+	t.Log("TestNewClientFromEnv: Check that invalid timeouts are rejected")
+	{
+		os.Setenv(EnvReadTimeout, "3s")
+		_, err := NewClientFromEnv()
+		require.NotNil(t, err)
+	}
+}
+
+func TestIndexPrefix(t *testing.T) {
+	t.Log("TestIndexPrefix: Start a server listing indexes of several environments")
+	var paths []string
+	var bodies []string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := ioutil.ReadAll(r.Body)
+		paths = append(paths, r.URL.Path)
+		bodies = append(bodies, string(data))
+
+		if r.Method == "GET" && r.URL.Path == "/1/indexes" {
+			w.Write([]byte(`{"items":[
+				{"name":"staging_products","replicas":["staging_products_asc","virtual(staging_products_desc)"]},
+				{"name":"staging_products_asc","primary":"staging_products"},
+				{"name":"products"}
+			],"nbPages":1}`))
+			return
+		}
+		if r.URL.Path == "/1/indexes/*/batch" {
+			w.Write([]byte(`{"taskID":{"staging_products":1},"objectIDs":["one"]}`))
+			return
+		}
+		if r.URL.Path == "/1/indexes/*/queries" {
+			w.Write([]byte(`{"results":[{"index":"staging_products","hits":[]}]}`))
+			return
+		}
+		if r.Method == "GET" && r.URL.Path == "/1/indexes/staging_products/settings" {
+			w.Write([]byte(`{"replicas":["staging_products_asc","virtual(staging_products_desc)"]}`))
+			return
+		}
+		w.Write([]byte(`{"taskID":1}`))
+	}))
+	defer server.Close()
+	c := &client{indexPrefix: "staging_", transport: newTestTransport(server)}
+	var hookIndexes []string
+	c.SetPreSearchHook(func(indexName, query string, params Map) (Map, error) {
+		hookIndexes = append(hookIndexes, indexName)
+		return nil, nil
+	})
+
+	t.Log("TestIndexPrefix: Check that the listed indexes are filtered and unprefixed")
+	{
+		indexes, err := c.ListIndexes()
+		require.Nil(t, err)
+		require.Len(t, indexes, 2)
+		require.Equal(t, "products", indexes[0].Name)
+		require.Equal(t, []string{"products_asc", "virtual(products_desc)"}, indexes[0].Replicas)
+		require.Equal(t, "products_asc", indexes[1].Name)
+		require.Equal(t, "products", indexes[1].Primary)
+
+		indexes, err = c.ListAllIndexes()
+		require.Nil(t, err)
+		require.Len(t, indexes, 2)
+	}
+
+	t.Log("TestIndexPrefix: Check that the index names are prefixed")
+	{
+		paths, bodies = nil, nil
+
+		_, err := c.InitIndex("products").GetSettings()
+		require.Nil(t, err)
+		require.Equal(t, "/1/indexes/staging_products/settings", paths[0])
+
+		_, err = c.MoveIndex("products_tmp", "products")
+		require.Nil(t, err)
+		require.Equal(t, "/1/indexes/staging_products_tmp/operation", paths[1])
+		require.Contains(t, bodies[1], `"destination":"staging_products"`)
+
+		_, err = c.CopyIndex("products", "products_copy")
+		require.Nil(t, err)
+		require.Contains(t, bodies[2], `"destination":"staging_products_copy"`)
+
+		res, err := c.Batch([]BatchOperationIndexed{{IndexName: "products", BatchOperation: BatchOperation{Action: "deleteObject", Body: Map{"objectID": "one"}}}})
+		require.Nil(t, err)
+		require.Contains(t, bodies[3], `"indexName":"staging_products"`)
+
+		t.Log("TestIndexPrefix: Check that the batch results can be sent again without prefixing twice")
+		require.Equal(t, "products", res.Operations[0].IndexName)
+		_, err = c.Batch([]BatchOperationIndexed{{IndexName: res.Operations[0].IndexName, BatchOperation: res.Operations[0].Operation}})
+		require.Nil(t, err)
+		require.Contains(t, bodies[4], `"indexName":"staging_products"`)
+
+		_, err = c.ScopedClient(KeyParams{ACL: []ACL{ACLSearch}, Indexes: []string{"products"}})
+		require.Nil(t, err)
+		require.Equal(t, "/1/keys/", paths[5])
+		require.Contains(t, bodies[5], `"indexes":["staging_products"]`)
+	}
+
+	t.Log("TestIndexPrefix: Check that the replicas are read and written without prefix")
+	{
+		paths, bodies = nil, nil
+
+		settings, err := c.InitIndex("products").GetSettings()
+		require.Nil(t, err)
+		require.Equal(t, []string{"products_asc", "virtual(products_desc)"}, settings.Replicas)
+
+		update := Map{"replicas": settings.Replicas}
+		_, err = c.InitIndex("products").SetSettings(update)
+		require.Nil(t, err)
+		require.Contains(t, bodies[1], `"replicas":["staging_products_asc","virtual(staging_products_desc)"]`)
+		require.Equal(t, []string{"products_asc", "virtual(products_desc)"}, update["replicas"], "should not modify the given settings")
+	}
+
+	t.Log("TestIndexPrefix: Check that the searched indexes are unprefixed")
+	{
+		res, err := c.MultipleQueries([]IndexedQuery{{IndexName: "products", Params: Map{}}}, StrategyNone)
+		require.Nil(t, err)
+		require.Equal(t, "products", res[0].Index)
+
+		_, err = c.InitIndex("products").Search("", nil)
+		require.Nil(t, err)
+		require.Equal(t, []string{"products", "products"}, hookIndexes)
+	}
+
+	t.Log("TestIndexPrefix: Check that the stats of an index are found")
+	{
+		stats, err := c.InitIndex("products").GetStats()
+		require.Nil(t, err)
+		require.Equal(t, "products", stats.Name)
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	t.Log("TestReadOnly: Start a server failing on the write requests")
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isReadOnlyRequest(r.Method, r.URL.Path) {
+			t.Errorf("unexpected request [%s] %s", r.Method, r.URL.Path)
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	c := &client{readOnly: true, transport: newTestTransport(server)}
+	index := c.InitIndex("products")
+
+	t.Log("TestReadOnly: Check that the read requests are sent")
+	{
+		_, err := index.Search("", nil)
+		require.Nil(t, err)
+
+		_, err = index.GetSettings()
+		require.Nil(t, err)
+
+		_, err = index.GetObjects([]string{"one"})
+		require.Nil(t, err)
+
+		_, err = index.SearchRules(SearchRulesParams{})
+		require.Nil(t, err)
+	}
+
+	t.Log("TestReadOnly: Check that the write requests are rejected")
+	{
+		_, err := index.AddObject(Object{"objectID": "one"})
+		require.True(t, IsReadOnly(err), "AddObject should be rejected")
+
+		_, err = index.SetSettings(Map{"hitsPerPage": 10})
+		require.True(t, IsReadOnly(err), "SetSettings should be rejected")
+
+		_, err = index.DeleteBy(Map{"filters": "brand:apple"})
+		require.True(t, IsReadOnly(err), "DeleteBy should be rejected")
+
+		_, err = c.DeleteIndex("products")
+		require.True(t, IsReadOnly(err), "DeleteIndex should be rejected")
+
+		_, err = c.AddAPIKey([]string{"search"}, nil)
+		require.True(t, IsReadOnly(err), "AddAPIKey should be rejected")
+		require.Equal(t, "Cannot perform request [POST] /1/keys/: the client is read-only", err.Error())
+	}
+
+	t.Log("TestReadOnly: Check that the indices named after a read route are not writable")
+	{
+		for _, name := range []string{"query", "browse", "queries"} {
+			_, err := c.InitIndex(name).AddObject(Object{"name": "one"})
+			require.True(t, IsReadOnly(err), "AddObject to %q should be rejected", name)
+		}
+
+		_, err := c.InitIndex("query").Search("", nil)
+		require.Nil(t, err)
+
+		_, err = c.InitIndex("browse").Browse(nil, "")
+		require.Nil(t, err)
+
+		_, err = index.SearchForFacetValues("brand", "app", nil)
+		require.Nil(t, err)
+
+		require.False(t, isReadOnlyRequest("POST", "/1/indexes/query"))
+		require.False(t, isReadOnlyRequest("POST", "/1/indexes/queries"))
+		require.False(t, isReadOnlyRequest("POST", "/1/indexes/browse"))
+		require.True(t, isReadOnlyRequest("POST", "/1/indexes/*/queries"))
+	}
+
+	t.Log("TestReadOnly: Check the option")
+	{
+		c := NewClientWithOptions("appid", "apikey", WithReadOnly()).(*client)
+		require.True(t, c.readOnly)
+	}
+}
@@ -1,9 +1,15 @@
 package algoliasearch
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/stretchr/testify/require"
 )
 
 func TestClientOperations(t *testing.T) {
@@ -44,7 +50,7 @@ func TestClientOperations(t *testing.T) {
 		waitTask(t, i, res.TaskID)
 
 		_, err = i.GetObject(objectID, nil)
-		if err == nil || err.Error() != "{\"message\":\"ObjectID does not exist\",\"status\":404}\n" {
+		if !IsNotFound(err) {
 			t.Fatalf("TestClientOperations: Object %s should be deleted after clear: %s", objectID, err)
 		}
 	}
@@ -413,6 +419,53 @@ func TestSlaveReplica(t *testing.T) {
 	}
 }
 
+func TestScopedClient(t *testing.T) {
+	t.Parallel()
+	c, i := initClientAndIndex(t, "TestScopedClient")
+	addOneObject(t, i)
+	defer c.ReleaseScopedClients()
+
+	var scoped Client
+
+	t.Log("TestScopedClient: Create a search-only scoped client")
+	{
+		var err error
+		scoped, err = c.ScopedClient(KeyParams{ACL: []ACL{ACLSearch}, Indexes: []string{"TestScopedClient"}})
+		if err != nil {
+			t.Fatalf("TestScopedClient: Cannot create the scoped client: %s", err)
+		}
+
+		if _, err = scoped.InitIndex("TestScopedClient").Search("", nil); err != nil {
+			t.Fatalf("TestScopedClient: Scoped client should be able to search: %s", err)
+		}
+
+		if _, err = scoped.InitIndex("TestScopedClient").AddObject(Object{"attribute": "value"}); err == nil {
+			t.Fatalf("TestScopedClient: Scoped client should not be able to write")
+		}
+	}
+
+	t.Log("TestScopedClient: Check that scoped clients are cached")
+	{
+		cached, err := c.ScopedClient(KeyParams{ACL: []ACL{ACLSearch}, Indexes: []string{"TestScopedClient"}})
+		if err != nil {
+			t.Fatalf("TestScopedClient: Cannot get the cached scoped client: %s", err)
+		}
+
+		if cached != scoped {
+			t.Fatalf("TestScopedClient: Scoped client should have been reused")
+		}
+	}
+}
+
+func TestIsAlive(t *testing.T) {
+	t.Parallel()
+	c := initClient(t)
+
+	if err := c.IsAlive(); err != nil {
+		t.Fatalf("TestIsAlive: Servers should be reachable but got: %s", err)
+	}
+}
+
 func TestDnsTimeout(t *testing.T) {
 	t.Parallel()
 
@@ -428,3 +481,146 @@ func TestDnsTimeout(t *testing.T) {
 		t.Fatalf("TestDnsTimeout: Spent %d seconds instead of <5s to perform the 10 retries", int(delta.Seconds()))
 	}
 }
+
+func TestClientTasks(t *testing.T) {
+	t.Log("TestClientTasks: Start a server publishing the task on the second call")
+	var paths []string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if len(paths) < 2 {
+			w.Write([]byte(`{"status":"notPublished","pendingTask":true}`))
+			return
+		}
+		w.Write([]byte(`{"status":"published","pendingTask":false}`))
+	}))
+	defer server.Close()
+	c := &client{transport: newTestTransport(server)}
+
+	t.Log("TestClientTasks: Check the task status and the wait")
+	{
+		res, err := c.GetTask("products", 42)
+		require.Nil(t, err)
+		require.Equal(t, "notPublished", res.Status)
+		require.Equal(t, "/1/indexes/products/task/42", paths[0])
+
+		require.Nil(t, c.WaitTask("products", 42))
+		require.Len(t, paths, 2)
+	}
+}
+
+func TestMultipleQueriesPreSearchHook(t *testing.T) {
+	t.Log("TestMultipleQueriesPreSearchHook: Start a server recording the queries")
+	var body map[string][]map[string]string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"results":[]}`))
+	}))
+	defer server.Close()
+
+	var hookQuery string
+	c := &client{transport: newTestTransport(server)}
+	c.SetPreSearchHook(func(indexName, query string, params Map) (Map, error) {
+		hookQuery = query
+		return Map{"ruleContexts": []string{"hooked"}}, nil
+	})
+
+	t.Log("TestMultipleQueriesPreSearchHook: Check that the query is kept along the hook parameters")
+	{
+		_, err := c.MultipleQueries([]IndexedQuery{
+			{IndexName: "products", Query: "phone", Params: Map{"hitsPerPage": 5}},
+		}, StrategyNone)
+		require.Nil(t, err)
+		require.Equal(t, "phone", hookQuery)
+
+		params, err := url.ParseQuery(body["requests"][0]["params"])
+		require.Nil(t, err)
+		require.Equal(t, "phone", params.Get("query"))
+		require.Equal(t, "5", params.Get("hitsPerPage"))
+		require.NotEmpty(t, params.Get("ruleContexts"))
+	}
+}
+
+func TestScopedClientConfiguration(t *testing.T) {
+	t.Log("TestScopedClientConfiguration: Start a server generating scoped keys")
+	var mutex sync.Mutex
+	var keys []string
+	var headers http.Header
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/1/keys/":
+			var body Map
+			json.NewDecoder(r.Body).Decode(&body)
+			keys = append(keys, encodeMap(body))
+			w.Write([]byte(`{"key":"scoped"}`))
+		case r.Method == "GET" && r.URL.Path == "/1/keys/scoped":
+			w.Write([]byte(`{"value":"scoped"}`))
+		default:
+			headers = r.Header
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+	c := &client{indexPrefix: "staging_", transport: newTestTransport(server)}
+	c.transport.setExtraHeader("X-Forwarded-For", "10.0.0.1")
+	c.transport.setTimeout(time.Second, 3*time.Second)
+
+	t.Log("TestScopedClientConfiguration: Check the generated key")
+	scoped, err := c.ScopedClient(KeyParams{ACL: []ACL{ACLSearch}, Validity: 90 * time.Second})
+	require.Nil(t, err)
+	require.Len(t, keys, 1)
+	require.Contains(t, keys[0], "validity=90")
+
+	t.Log("TestScopedClientConfiguration: Check that the configuration of the client is kept")
+	{
+		sc := scoped.(*client)
+		require.Equal(t, "staging_", sc.indexPrefix)
+		require.Equal(t, 3*time.Second, sc.transport.readTimeout)
+		require.False(t, c.transport.httpClient == sc.transport.httpClient, "should copy the HTTP client")
+		require.Equal(t, 3*time.Second, sc.transport.httpClient.Transport.(*http.Transport).ResponseHeaderTimeout)
+
+		sc.transport.setTimeout(time.Second, 5*time.Second)
+		require.Equal(t, 3*time.Second, c.transport.httpClient.Transport.(*http.Transport).ResponseHeaderTimeout, "should not modify the parent client")
+
+		_, err := scoped.InitIndex("products").GetSettings()
+		require.Nil(t, err)
+		require.Equal(t, "scoped", headers.Get("X-Algolia-API-Key"))
+		require.Equal(t, "10.0.0.1", headers.Get("X-Forwarded-For"))
+		require.Equal(t, "apikey", c.transport.headers["X-Algolia-API-Key"], "should not modify the parent client")
+	}
+
+	t.Log("TestScopedClientConfiguration: Check that the scoped client is cached")
+	{
+		cached, err := c.ScopedClient(KeyParams{ACL: []ACL{ACLSearch}, Validity: 90 * time.Second})
+		require.Nil(t, err)
+		require.True(t, scoped == cached, "should reuse the scoped client")
+		require.Len(t, keys, 1)
+
+		_, err = c.ScopedClient(KeyParams{ACL: []ACL{ACLSearch}, Validity: -time.Second})
+		require.NotNil(t, err, "should reject a negative validity")
+	}
+}
+
+func TestWaitAppTask(t *testing.T) {
+	t.Log("TestWaitAppTask: Start a server publishing the application task after two polls")
+	var paths []string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if len(paths) < 3 {
+			w.Write([]byte(`{"status":"notPublished"}`))
+			return
+		}
+		w.Write([]byte(`{"status":"published"}`))
+	}))
+	defer server.Close()
+	c := &client{transport: newTestTransport(server)}
+
+	t.Log("TestWaitAppTask: Check that the application task is polled until published")
+	{
+		err := c.WaitAppTaskWithRequestOptions(42, &RequestOptions{WaitSchedule: &WaitScheduleAggressive})
+		require.Nil(t, err)
+		require.Equal(t, []string{"/1/task/42", "/1/task/42", "/1/task/42"}, paths)
+	}
+}
@@ -0,0 +1,111 @@
+package algoliasearch
+
+import (
+	"fmt"
+	"sync"
+)
+
+// CoordinatedWrite describes the batch `Operations` to apply on the index
+// named `IndexName` as part of a `Client.CoordinatedBatch` call. The
+// `Compensations` operations are applied on the same index if the write
+// succeeded but the coordinated batch failed on another index. They are
+// typically used to restore the previous state of the records.
+type CoordinatedWrite struct {
+	IndexName     string
+	Operations    []BatchOperation
+	Compensations []BatchOperation
+}
+
+// CoordinatedWriteRes reports the outcome of a CoordinatedWrite. `Err` is
+// non-nil if the write could not be applied (or waited for) and
+// `Compensated` is `true` if the compensation operations have been applied
+// successfully. If they could not be applied, `CompensationErr` is non-nil.
+type CoordinatedWriteRes struct {
+	IndexName       string
+	TaskID          int
+	Err             error
+	Compensated     bool
+	CompensationErr error
+}
+
+func (c *client) CoordinatedBatch(writes []CoordinatedWrite) (res []CoordinatedWriteRes, err error) {
+	return c.CoordinatedBatchWithRequestOptions(writes, nil)
+}
+
+func (c *client) CoordinatedBatchWithRequestOptions(writes []CoordinatedWrite, opts *RequestOptions) (res []CoordinatedWriteRes, err error) {
+	res = make([]CoordinatedWriteRes, len(writes))
+	sent := make([]bool, len(writes))
+	failed := false
+
+	// Send the writes one after the other and stop at the first failure
+	for i, w := range writes {
+		res[i].IndexName = w.IndexName
+
+		if failed {
+			res[i].Err = NotAppliedErr
+			continue
+		}
+
+		batchRes, e := c.InitIndex(w.IndexName).BatchWithRequestOptions(w.Operations, opts)
+		if e != nil {
+			res[i].Err = e
+			failed = true
+			continue
+		}
+
+		res[i].TaskID = batchRes.TaskID
+		sent[i] = true
+	}
+
+	// Wait for all the sent writes to be applied
+	var wg sync.WaitGroup
+	for i := range writes {
+		if !sent[i] {
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			res[i].Err = c.InitIndex(writes[i].IndexName).WaitTaskWithRequestOptions(res[i].TaskID, opts)
+		}(i)
+	}
+	wg.Wait()
+
+	// The writes which were not sent because of a previous failure are not
+	// counted as failures
+	nbFailures, nbNotApplied := 0, 0
+	for i := range res {
+		switch res[i].Err {
+		case nil:
+		case NotAppliedErr:
+			nbNotApplied++
+		default:
+			nbFailures++
+		}
+	}
+
+	if nbFailures == 0 {
+		return
+	}
+
+	// Compensate all the writes which have been sent, even if waiting for
+	// them failed, as they may have been applied anyway
+	for i, w := range writes {
+		if !sent[i] || len(w.Compensations) == 0 {
+			continue
+		}
+
+		index := c.InitIndex(w.IndexName)
+		batchRes, e := index.BatchWithRequestOptions(w.Compensations, opts)
+		if e == nil {
+			e = index.WaitTaskWithRequestOptions(batchRes.TaskID, opts)
+		}
+
+		res[i].Compensated = e == nil
+		res[i].CompensationErr = e
+	}
+
+	err = fmt.Errorf("Coordinated batch failed on %d out of %d indices (%d writes not applied)", nbFailures, len(writes), nbNotApplied)
+	return
+}
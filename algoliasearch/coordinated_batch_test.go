@@ -0,0 +1,80 @@
+package algoliasearch
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoordinatedBatch(t *testing.T) {
+	t.Log("TestCoordinatedBatch: Start a server failing the writes to the `broken` index")
+	var mutex sync.Mutex
+	batches := make(map[string]int)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/batch") {
+			mutex.Lock()
+			batches[r.URL.Path]++
+			mutex.Unlock()
+
+			if strings.Contains(r.URL.Path, "/broken/") {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"message":"Invalid batch","status":400}`))
+				return
+			}
+			w.Write([]byte(`{"taskID":42,"objectIDs":["one"]}`))
+			return
+		}
+		w.Write([]byte(`{"status":"published","pendingTask":false}`))
+	}))
+	defer server.Close()
+	c := &client{transport: newTestTransport(server)}
+
+	operations := []BatchOperation{{Action: ActionUpdateObject, Body: Map{"objectID": "one"}}}
+	compensations := []BatchOperation{{Action: ActionDeleteObject, Body: Map{"objectID": "one"}}}
+
+	t.Log("TestCoordinatedBatch: Check that the writes are applied and waited for")
+	{
+		res, err := c.CoordinatedBatch([]CoordinatedWrite{
+			{IndexName: "products", Operations: operations, Compensations: compensations},
+			{IndexName: "stocks", Operations: operations, Compensations: compensations},
+		})
+		require.Nil(t, err)
+		require.Len(t, res, 2)
+		for _, r := range res {
+			require.Nil(t, r.Err)
+			require.Equal(t, 42, r.TaskID)
+			require.False(t, r.Compensated, "should not compensate successful batches")
+		}
+		require.Equal(t, 1, batches["/1/indexes/products/batch"])
+	}
+
+	t.Log("TestCoordinatedBatch: Check that the sent writes are compensated after a failure")
+	{
+		batches = make(map[string]int)
+		res, err := c.CoordinatedBatch([]CoordinatedWrite{
+			{IndexName: "products", Operations: operations, Compensations: compensations},
+			{IndexName: "broken", Operations: operations, Compensations: compensations},
+			{IndexName: "stocks", Operations: operations, Compensations: compensations},
+		})
+		require.NotNil(t, err)
+		require.Equal(t, "Coordinated batch failed on 1 out of 3 indices (1 writes not applied)", err.Error())
+		require.Len(t, res, 3)
+
+		require.Nil(t, res[0].Err)
+		require.True(t, res[0].Compensated)
+		require.Nil(t, res[0].CompensationErr)
+		require.Equal(t, 2, batches["/1/indexes/products/batch"], "should send the write and its compensation")
+
+		require.NotNil(t, res[1].Err)
+		require.NotEqual(t, NotAppliedErr, res[1].Err)
+		require.False(t, res[1].Compensated, "should not compensate the failed write")
+
+		require.Equal(t, NotAppliedErr, res[2].Err)
+		require.False(t, res[2].Compensated)
+		require.Equal(t, 0, batches["/1/indexes/stocks/batch"], "should not send the writes following the failure")
+	}
+}
@@ -0,0 +1,214 @@
+package algoliasearch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// debugBodyLimit is the maximum number of bytes of the request and response
+// bodies written by the debug logger.
+const debugBodyLimit = 2048
+
+// redacted replaces the API keys in the debug dumps.
+const redacted = "<redacted>"
+
+// debugLogger writes the dumps of the requests sent and of the responses
+// received by a Transport when the debug mode is enabled (see
+// `Client.SetDebug`). Each dump is written with a single call to the
+// underlying writer, under a lock, so that the dumps of concurrent requests
+// are not interleaved.
+type debugLogger struct {
+	sync.Mutex
+	w io.Writer
+}
+
+func (l *debugLogger) write(buf *bytes.Buffer) {
+	l.Lock()
+	l.w.Write(buf.Bytes())
+	l.Unlock()
+}
+
+// logRequest dumps the request line, the headers and the (truncated) body of
+// the given `attempt` to send the request.
+func (l *debugLogger) logRequest(req *http.Request, body []byte, attempt int) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "[algolia] --> %s %s (attempt %d)\n", req.Method, debugURL(req), attempt)
+	writeDebugHeaders(&buf, req.Header)
+	writeDebugBody(&buf, body)
+	l.write(&buf)
+}
+
+// logResponse dumps the status line, the headers and the (truncated) body of
+// the response to the given `attempt` to send the request. The API keys
+// returned by the routes managing them are redacted.
+func (l *debugLogger) logResponse(req *http.Request, res *http.Response, body []byte, attempt int, elapsed time.Duration) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "[algolia] <-- %s %s %s (attempt %d, %s)\n", res.Status, req.Method, debugURL(req), attempt, elapsed)
+	writeDebugHeaders(&buf, res.Header)
+	if _, ok := keysRouteKeySegment(req.URL.Path); ok {
+		body = redactKeysBody(body)
+	}
+	writeDebugBody(&buf, body)
+	l.write(&buf)
+}
+
+// logError dumps the error which prevented the given `attempt` to send the
+// request from receiving any response.
+func (l *debugLogger) logError(req *http.Request, err error, attempt int, elapsed time.Duration) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "[algolia] <-- error %s %s (attempt %d, %s): %s\n", req.Method, debugURL(req), attempt, elapsed, err)
+	l.write(&buf)
+}
+
+// debugURL returns the URL of the request whose `x-algolia-api-key` query
+// parameter, if any, is redacted, as well as the API key targeted by the
+// routes managing them.
+func debugURL(req *http.Request) string {
+	u := *req.URL
+	if n, ok := keysRouteKeySegment(u.Path); ok {
+		segments := strings.Split(u.Path, "/")
+		if n < len(segments) && segments[n] != "" {
+			segments[n] = redacted
+			u.Path = strings.Join(segments, "/")
+			u.RawPath = ""
+		}
+	}
+
+	values := u.Query()
+
+	found := false
+	for k := range values {
+		if isSensitiveParam(k) {
+			values.Set(k, redacted)
+			found = true
+		}
+	}
+	if found {
+		u.RawQuery = values.Encode()
+	}
+
+	return u.String()
+}
+
+// writeDebugHeaders writes the given headers, sorted by name, with the
+// values of the sensitive ones redacted.
+func writeDebugHeaders(buf *bytes.Buffer, headers http.Header) {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, value := range headers[name] {
+			if isSensitiveParam(name) || strings.EqualFold(name, "Authorization") {
+				value = redacted
+			}
+			fmt.Fprintf(buf, "%s: %s\n", name, value)
+		}
+	}
+}
+
+// writeDebugBody writes the given body, truncated to `debugBodyLimit`
+// bytes.
+func writeDebugBody(buf *bytes.Buffer, body []byte) {
+	if len(body) == 0 {
+		return
+	}
+
+	buf.WriteByte('\n')
+	if len(body) > debugBodyLimit {
+		buf.Write(body[:debugBodyLimit])
+		fmt.Fprintf(buf, "... (%d bytes truncated)", len(body)-debugBodyLimit)
+	} else {
+		buf.Write(body)
+	}
+	buf.WriteByte('\n')
+}
+
+// keysRouteKeySegment returns, if `path` is a route managing the API keys of
+// the application (`/1/keys...`) or of an index (`/1/indexes/{}/keys...`),
+// the position of the segment holding the targeted key in the `/`-separated
+// `path`.
+func keysRouteKeySegment(path string) (int, bool) {
+	segments := strings.Split(path, "/")
+	switch {
+	case len(segments) > 2 && segments[2] == "keys":
+		return 3, true
+	case len(segments) > 4 && segments[2] == "indexes" && segments[4] == "keys":
+		return 5, true
+	}
+	return 0, false
+}
+
+// redactKeysBody returns the given response body of a route managing the API
+// keys whose `key` and `value` fields are redacted. A body which cannot be
+// decoded is redacted as a whole.
+func redactKeysBody(body []byte) []byte {
+	if len(body) == 0 {
+		return body
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return []byte(redacted)
+	}
+	redactKeys(v)
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return []byte(redacted)
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+}
+
+func redactKeys(v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			if _, ok := child.(string); ok && (k == "key" || k == "value") {
+				v[k] = redacted
+				continue
+			}
+			redactKeys(child)
+		}
+	case []interface{}:
+		for _, child := range v {
+			redactKeys(child)
+		}
+	}
+}
+
+// isSensitiveParam returns `true` if the given header or query parameter
+// carries the API key.
+func isSensitiveParam(name string) bool {
+	return strings.EqualFold(name, "X-Algolia-API-Key")
+}
+
+// readRequestBody reads the whole body of the request, if any, and replaces
+// it by a new reader of the same content so that the request can still be
+// sent.
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
@@ -0,0 +1,189 @@
+package algoliasearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// DictionaryName is the name of one of the dictionaries of an application.
+type DictionaryName string
+
+// Dictionaries of an application.
+const (
+	DictionaryStopwords DictionaryName = "stopwords"
+	DictionaryPlurals   DictionaryName = "plurals"
+	DictionaryCompounds DictionaryName = "compounds"
+)
+
+// DictionarySettings are the settings of the dictionaries of an application
+// (see Client.SetDictionarySettings).
+type DictionarySettings struct {
+	DisableStandardEntries *StandardEntries `json:"disableStandardEntries,omitempty"`
+}
+
+// StandardEntries tells, for each dictionary, which languages (identified by
+// their ISO code, e.g. `fr`) have their standard entries, maintained by
+// Algolia, disabled (`true`) or enabled (`false`). The languages which are
+// not listed keep their current state.
+type StandardEntries struct {
+	Plurals   map[string]bool `json:"plurals,omitempty"`
+	Stopwords map[string]bool `json:"stopwords,omitempty"`
+	Compounds map[string]bool `json:"compounds,omitempty"`
+}
+
+func (c *client) GetDictionarySettings() (settings DictionarySettings, err error) {
+	return c.GetDictionarySettingsWithRequestOptions(nil)
+}
+
+func (c *client) GetDictionarySettingsWithRequestOptions(opts *RequestOptions) (settings DictionarySettings, err error) {
+	err = c.request(&settings, "GET", "/1/dictionaries/*/settings", nil, read, opts)
+	return
+}
+
+func (c *client) SetDictionarySettings(settings DictionarySettings) (res UpdateTaskRes, err error) {
+	return c.SetDictionarySettingsWithRequestOptions(settings, nil)
+}
+
+func (c *client) SetDictionarySettingsWithRequestOptions(settings DictionarySettings, opts *RequestOptions) (res UpdateTaskRes, err error) {
+	if err = checkDictionarySettings(settings); err != nil {
+		return
+	}
+
+	err = c.request(&res, "PUT", "/1/dictionaries/*/settings", settings, write, opts)
+	return
+}
+
+// DictionaryEntry is an entry of a dictionary: a *StopwordEntry, a
+// *PluralEntry or a *CompoundEntry depending on the dictionary.
+type DictionaryEntry interface {
+	// Dictionary returns the name of the dictionary of the entry.
+	Dictionary() DictionaryName
+}
+
+// DictionaryEntryState tells whether a dictionary entry is used.
+type DictionaryEntryState string
+
+// States of the dictionary entries.
+const (
+	DictionaryEntryEnabled  DictionaryEntryState = "enabled"
+	DictionaryEntryDisabled DictionaryEntryState = "disabled"
+)
+
+// DictionaryEntryType tells whether a dictionary entry was added by the user
+// or is a standard entry maintained by Algolia.
+type DictionaryEntryType string
+
+// Types of the dictionary entries.
+const (
+	DictionaryEntryCustom   DictionaryEntryType = "custom"
+	DictionaryEntryStandard DictionaryEntryType = "standard"
+)
+
+// StopwordEntry is an entry of the stopwords dictionary: the `Word` is
+// ignored in the queries of the given `Language` if the entry is enabled.
+type StopwordEntry struct {
+	ObjectID string               `json:"objectID"`
+	Language string               `json:"language"`
+	Word     string               `json:"word"`
+	State    DictionaryEntryState `json:"state,omitempty"`
+	Type     DictionaryEntryType  `json:"type,omitempty"`
+}
+
+// Dictionary returns DictionaryStopwords.
+func (e *StopwordEntry) Dictionary() DictionaryName { return DictionaryStopwords }
+
+// PluralEntry is an entry of the plurals dictionary: all the `Words` are
+// considered as forms of the same word in the given `Language`.
+type PluralEntry struct {
+	ObjectID string              `json:"objectID"`
+	Language string              `json:"language"`
+	Words    []string            `json:"words"`
+	Type     DictionaryEntryType `json:"type,omitempty"`
+}
+
+// Dictionary returns DictionaryPlurals.
+func (e *PluralEntry) Dictionary() DictionaryName { return DictionaryPlurals }
+
+// CompoundEntry is an entry of the compounds dictionary: the `Word` is split
+// into the words of its `Decomposition` in the given `Language`.
+type CompoundEntry struct {
+	ObjectID      string              `json:"objectID"`
+	Language      string              `json:"language"`
+	Word          string              `json:"word"`
+	Decomposition []string            `json:"decomposition"`
+	Type          DictionaryEntryType `json:"type,omitempty"`
+}
+
+// Dictionary returns DictionaryCompounds.
+func (e *CompoundEntry) Dictionary() DictionaryName { return DictionaryCompounds }
+
+// SearchDictionaryEntriesParams are the parameters of
+// Client.SearchDictionaryEntries. The empty `Query` matches all the entries
+// and zero values are not sent, hence the engine defaults apply (first page
+// of 20 entries, whatever their language).
+type SearchDictionaryEntriesParams struct {
+	Query       string `json:"query"`
+	Page        int    `json:"page,omitempty"`
+	HitsPerPage int    `json:"hitsPerPage,omitempty"`
+	Language    string `json:"language,omitempty"`
+}
+
+// SearchDictionaryEntriesRes is the response of
+// Client.SearchDictionaryEntries. The `Hits` are typed according to the
+// searched dictionary.
+type SearchDictionaryEntriesRes struct {
+	Hits    []DictionaryEntry
+	NbHits  int
+	Page    int
+	NbPages int
+}
+
+func (c *client) SearchDictionaryEntries(dictionary DictionaryName, params SearchDictionaryEntriesParams) (res SearchDictionaryEntriesRes, err error) {
+	return c.SearchDictionaryEntriesWithRequestOptions(dictionary, params, nil)
+}
+
+func (c *client) SearchDictionaryEntriesWithRequestOptions(dictionary DictionaryName, params SearchDictionaryEntriesParams, opts *RequestOptions) (res SearchDictionaryEntriesRes, err error) {
+	if err = checkSearchDictionaryEntriesParams(dictionary, params); err != nil {
+		return
+	}
+
+	var raw struct {
+		Hits    []json.RawMessage `json:"hits"`
+		NbHits  int               `json:"nbHits"`
+		Page    int               `json:"page"`
+		NbPages int               `json:"nbPages"`
+	}
+	path := "/1/dictionaries/" + url.QueryEscape(string(dictionary)) + "/search"
+	if err = c.request(&raw, "POST", path, params, read, opts); err != nil {
+		return
+	}
+
+	res.NbHits, res.Page, res.NbPages = raw.NbHits, raw.Page, raw.NbPages
+	res.Hits = make([]DictionaryEntry, len(raw.Hits))
+	for n, hit := range raw.Hits {
+		if res.Hits[n], err = decodeDictionaryEntry(dictionary, hit); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// decodeDictionaryEntry decodes the `data` of an entry of the `dictionary`
+// into the corresponding type.
+func decodeDictionaryEntry(dictionary DictionaryName, data []byte) (DictionaryEntry, error) {
+	var entry DictionaryEntry
+	switch dictionary {
+	case DictionaryStopwords:
+		entry = &StopwordEntry{}
+	case DictionaryPlurals:
+		entry = &PluralEntry{}
+	default:
+		entry = &CompoundEntry{}
+	}
+
+	if err := json.Unmarshal(data, entry); err != nil {
+		return nil, fmt.Errorf("Cannot decode %s dictionary entry: %s", dictionary, err)
+	}
+	return entry, nil
+}
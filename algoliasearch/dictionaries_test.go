@@ -0,0 +1,135 @@
+package algoliasearch
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDictionarySettings(t *testing.T) {
+	t.Log("TestDictionarySettings: Start a server storing the dictionary settings")
+	settings := `{"disableStandardEntries":{"plurals":{"fr":false}}}`
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/1/dictionaries/*/settings", r.URL.Path)
+		if r.Method == "PUT" {
+			body, err := ioutil.ReadAll(r.Body)
+			require.Nil(t, err)
+			settings = string(body)
+			w.Write([]byte(`{"taskID":42,"updatedAt":"2024-01-01T00:00:00Z"}`))
+			return
+		}
+		w.Write([]byte(settings))
+	}))
+	defer server.Close()
+	c := &client{transport: newTestTransport(server)}
+
+	t.Log("TestDictionarySettings: Check that the settings are retrieved")
+	{
+		res, err := c.GetDictionarySettings()
+		require.Nil(t, err)
+		require.Equal(t, DictionarySettings{
+			DisableStandardEntries: &StandardEntries{Plurals: map[string]bool{"fr": false}},
+		}, res)
+	}
+
+	t.Log("TestDictionarySettings: Check that the standard stopwords can be disabled per language")
+	{
+		res, err := c.SetDictionarySettings(DictionarySettings{
+			DisableStandardEntries: &StandardEntries{Stopwords: map[string]bool{"fr": true, "en": false}},
+		})
+		require.Nil(t, err)
+		require.Equal(t, 42, res.TaskID)
+		require.JSONEq(t, `{"disableStandardEntries":{"stopwords":{"fr":true,"en":false}}}`, settings)
+	}
+
+	t.Log("TestDictionarySettings: Check that empty languages are rejected")
+	{
+		_, err := c.SetDictionarySettings(DictionarySettings{
+			DisableStandardEntries: &StandardEntries{Plurals: map[string]bool{"": true}},
+		})
+		require.EqualError(t, err, "Invalid standard entries of the plurals dictionary: the language should not be empty")
+	}
+}
+
+func TestSearchDictionaryEntries(t *testing.T) {
+	t.Log("TestSearchDictionaryEntries: Start a server holding entries of every dictionary")
+	var pages []SearchDictionaryEntriesParams
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var params SearchDictionaryEntriesParams
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&params))
+
+		switch r.URL.Path {
+		case "/1/dictionaries/stopwords/search":
+			pages = append(pages, params)
+			if params.Page == 0 {
+				w.Write([]byte(`{"hits":[{"objectID":"1","language":"fr","word":"le","state":"enabled","type":"custom"}],"nbHits":2,"page":0,"nbPages":2}`))
+			} else if params.Page == 1 {
+				w.Write([]byte(`{"hits":[{"objectID":"2","language":"fr","word":"la","state":"disabled","type":"standard"}],"nbHits":2,"page":1,"nbPages":2}`))
+			} else {
+				w.Write([]byte(`{"hits":[],"nbHits":2,"page":2,"nbPages":2}`))
+			}
+		case "/1/dictionaries/plurals/search":
+			w.Write([]byte(`{"hits":[{"objectID":"3","language":"en","words":["mouse","mice"]}],"nbHits":1}`))
+		case "/1/dictionaries/compounds/search":
+			w.Write([]byte(`{"hits":[{"objectID":"4","language":"de","word":"Kopfschmerz","decomposition":["kopf","schmerz"]}],"nbHits":1}`))
+		}
+	}))
+	defer server.Close()
+	c := &client{transport: newTestTransport(server)}
+
+	t.Log("TestSearchDictionaryEntries: Check that the entries are typed according to their dictionary")
+	{
+		res, err := c.SearchDictionaryEntries(DictionaryPlurals, SearchDictionaryEntriesParams{Query: "mouse"})
+		require.Nil(t, err)
+		require.Equal(t, []DictionaryEntry{
+			&PluralEntry{ObjectID: "3", Language: "en", Words: []string{"mouse", "mice"}},
+		}, res.Hits)
+
+		res, err = c.SearchDictionaryEntries(DictionaryCompounds, SearchDictionaryEntriesParams{})
+		require.Nil(t, err)
+		require.Equal(t, []DictionaryEntry{
+			&CompoundEntry{ObjectID: "4", Language: "de", Word: "Kopfschmerz", Decomposition: []string{"kopf", "schmerz"}},
+		}, res.Hits)
+		require.Equal(t, DictionaryCompounds, res.Hits[0].Dictionary())
+	}
+
+	t.Log("TestSearchDictionaryEntries: Check that unknown dictionaries are rejected")
+	{
+		_, err := c.SearchDictionaryEntries("stopword", SearchDictionaryEntriesParams{})
+		require.NotNil(t, err)
+	}
+
+	t.Log("TestSearchDictionaryEntries: Check that the iterator goes through all the pages")
+	{
+		it := NewDictionaryEntryIterator(c, DictionaryStopwords, SearchDictionaryEntriesParams{Language: "fr"})
+
+		var entries []DictionaryEntry
+		for {
+			entry, err := it.Next()
+			if err == NoMoreDictionaryEntriesErr {
+				break
+			}
+			require.Nil(t, err)
+			entries = append(entries, entry)
+		}
+
+		require.Equal(t, []DictionaryEntry{
+			&StopwordEntry{ObjectID: "1", Language: "fr", Word: "le", State: DictionaryEntryEnabled, Type: DictionaryEntryCustom},
+			&StopwordEntry{ObjectID: "2", Language: "fr", Word: "la", State: DictionaryEntryDisabled, Type: DictionaryEntryStandard},
+		}, entries)
+		require.Len(t, pages, 2)
+		for n, params := range pages {
+			require.Equal(t, SearchDictionaryEntriesParams{Page: n, HitsPerPage: 1000, Language: "fr"}, params)
+		}
+	}
+
+	t.Log("TestSearchDictionaryEntries: Check that read-only clients can search")
+	{
+		require.True(t, isReadOnlyRequest("POST", "/1/dictionaries/stopwords/search"))
+		require.False(t, isReadOnlyRequest("POST", "/1/dictionaries/stopwords/batch"))
+	}
+}
@@ -0,0 +1,78 @@
+package algoliasearch
+
+// DictionaryEntryIterator is the exposed structure to iterate over all the
+// entries of a dictionary matching some search parameters, e.g. to export
+// them.
+type DictionaryEntryIterator struct {
+	client     Client
+	dictionary DictionaryName
+	params     SearchDictionaryEntriesParams
+	entries    []DictionaryEntry
+	nbPages    int
+	page       int
+	pos        int
+}
+
+// NewDictionaryEntryIterator returns a new DictionaryEntryIterator that will
+// iterate over all the entries of the `dictionary` matching the `params`,
+// whose `Page` is ignored. All the entries are retrieved if `params` is
+// zero.
+func NewDictionaryEntryIterator(client Client, dictionary DictionaryName, params SearchDictionaryEntriesParams) *DictionaryEntryIterator {
+	if params.HitsPerPage == 0 {
+		params.HitsPerPage = 1000
+	}
+
+	return &DictionaryEntryIterator{
+		client:     client,
+		dictionary: dictionary,
+		params:     params,
+		entries:    nil,
+		page:       -1,
+		pos:        -1,
+	}
+}
+
+// Next returns the next entry of the dictionary. Every call to Next should
+// yield a different entry with a nil error until the
+// algoliasearch.NoMoreDictionaryEntriesErr is returned which means that all
+// the entries have been retrieved. If the error is of a different type, it
+// means that the iteration could not have been done correctly.
+func (it *DictionaryEntryIterator) Next() (DictionaryEntry, error) {
+	for it.entries == nil || it.pos+1 >= len(it.entries) {
+		if it.entries != nil && it.page+1 >= it.nbPages {
+			return nil, NoMoreDictionaryEntriesErr
+		}
+		if err := it.loadNextPage(); err != nil {
+			it.reset()
+			return nil, err
+		}
+	}
+
+	it.pos++
+	return it.entries[it.pos], nil
+}
+
+func (it *DictionaryEntryIterator) loadNextPage() error {
+	it.pos = -1
+	it.page++
+
+	params := it.params
+	params.Page = it.page
+	res, err := it.client.SearchDictionaryEntries(it.dictionary, params)
+	if err != nil {
+		return err
+	}
+
+	it.entries = res.Hits
+	if it.entries == nil {
+		it.entries = []DictionaryEntry{}
+	}
+	it.nbPages = res.NbPages
+	return nil
+}
+
+func (it *DictionaryEntryIterator) reset() {
+	it.entries = nil
+	it.page = -1
+	it.pos = -1
+}
@@ -0,0 +1,130 @@
+package algoliasearch
+
+import "fmt"
+
+// Destructive operations reported by the dry-run mode.
+const (
+	DryRunDeleteIndex   string = "deleteIndex"
+	DryRunClearIndex    string = "clearIndex"
+	DryRunMoveIndex     string = "moveIndex"
+	DryRunDeleteBy      string = "deleteBy"
+	DryRunClearRules    string = "clearRules"
+	DryRunClearSynonyms string = "clearSynonyms"
+)
+
+// DryRunReport describes a destructive operation which was not performed
+// because of the dry-run mode (see WithDryRun and RequestOptions.DryRun).
+// `Method` and `Path` describe the request which would have been sent and
+// `Params` the search parameters selecting the records to delete, if any.
+// `Affected` is the estimated number of records (or rules, or synonyms for
+// DryRunClearRules and DryRunClearSynonyms) which would have been deleted,
+// or overwritten by the source index for DryRunMoveIndex. It is set to -1 if
+// the estimation failed, `EstimateErr` holding the reason.
+type DryRunReport struct {
+	Operation   string
+	IndexName   string
+	Destination string
+	Method      string
+	Path        string
+	Params      Map
+	Affected    int
+	EstimateErr error
+}
+
+func (r DryRunReport) String() string {
+	target := r.IndexName
+	if r.Destination != "" {
+		target += " to " + r.Destination
+	}
+
+	affected := fmt.Sprintf("%d", r.Affected)
+	if r.EstimateErr != nil {
+		affected = fmt.Sprintf("unknown (%s)", r.EstimateErr)
+	}
+
+	return fmt.Sprintf("%s of %s ([%s] %s), affected: %s", r.Operation, target, r.Method, r.Path, affected)
+}
+
+// DryRunErr is the error returned by the destructive methods (Index.Delete,
+// Index.Clear, Index.Move, Index.DeleteBy, Index.DeleteByQuery,
+// Index.ClearRules and Index.ClearSynonyms, as well as the Client methods
+// relying on them) when called in dry-run mode: nothing was performed and
+// `Report` describes what would have been.
+type DryRunErr struct {
+	Report DryRunReport
+}
+
+func (e *DryRunErr) Error() string {
+	return "Dry run: " + e.Report.String()
+}
+
+// IsDryRun returns `true` if the given error was caused by a destructive
+// operation not being performed because of the dry-run mode.
+func IsDryRun(err error) bool {
+	_, ok := err.(*DryRunErr)
+	return ok
+}
+
+// isDryRun returns `true` if the destructive operations of the index should
+// only be reported, either because the client or the request options enable
+// the dry-run mode.
+func (i *index) isDryRun(opts *RequestOptions) bool {
+	return i.client.dryRun || (opts != nil && opts.DryRun)
+}
+
+// dryRun reports the given operation, whose number of affected items is
+// estimated by the `estimate` function, and returns the matching
+// `*DryRunErr`. The report is also written to the dry-run log of the client,
+// if any.
+func (i *index) dryRun(report DryRunReport, estimate func() (int, error)) error {
+	report.IndexName = i.name
+	if report.Affected, report.EstimateErr = estimate(); report.EstimateErr != nil {
+		report.Affected = -1
+	}
+
+	if w := i.client.dryRunLog; w != nil {
+		fmt.Fprintf(w, "[algolia] dry run: %s\n", report)
+	}
+
+	return &DryRunErr{Report: report}
+}
+
+// countRecords returns the number of records of the index matching the
+// given `query` and search `params`. A missing index has no records.
+func (i *index) countRecords(query string, params Map, opts *RequestOptions) (int, error) {
+	copy := duplicateMap(params)
+	copy["query"] = query
+	copy["hitsPerPage"] = 0
+	copy["analytics"] = false
+
+	req := Map{
+		"params": encodeMap(copy),
+	}
+
+	var res QueryRes
+	err := i.request(&res, "POST", i.route+"/query", req, search, opts)
+	if IsNotFound(err) {
+		return 0, nil
+	}
+	return res.NbHits, err
+}
+
+// countRules returns the number of rules of the index.
+func (i *index) countRules(opts *RequestOptions) (int, error) {
+	var res SearchRulesRes
+	err := i.request(&res, "POST", i.route+"/rules/search", SearchRulesParams{}, read, opts)
+	return res.NbHits, err
+}
+
+// countSynonyms returns the number of synonyms of the index.
+func (i *index) countSynonyms(opts *RequestOptions) (int, error) {
+	var res SearchSynonymsRes
+	err := i.request(&res, "POST", i.route+"/synonyms/search", Map{"query": ""}, search, opts)
+	return res.NbHits, err
+}
+
+// destinationIndex returns the index named `name`, prefixed like `i`, used
+// as the destination of a copy or a move.
+func (i *index) destinationIndex(name string) *index {
+	return NewIndex(i.client.prefixedIndexName(name), i.client).(*index)
+}
@@ -0,0 +1,81 @@
+package algoliasearch
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRun(t *testing.T) {
+	t.Log("TestDryRun: Start a server only answering the estimation requests")
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/rules/search"):
+			w.Write([]byte(`{"hits":[],"nbHits":3}`))
+		case strings.HasSuffix(r.URL.Path, "/synonyms/search"):
+			w.Write([]byte(`{"hits":[],"nbHits":5}`))
+		case r.URL.Path == "/1/indexes/missing/query":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"Index does not exist","status":404}`))
+		case strings.HasSuffix(r.URL.Path, "/query"):
+			w.Write([]byte(`{"hits":[],"nbHits":42}`))
+		default:
+			t.Errorf("unexpected request [%s] %s", r.Method, r.URL.Path)
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	var log bytes.Buffer
+	c := &client{dryRun: true, dryRunLog: &log, transport: newTestTransport(server)}
+	index := c.InitIndex("products")
+
+	t.Log("TestDryRun: Check that the destructive operations are only reported")
+	{
+		_, err := index.Clear()
+		require.True(t, IsDryRun(err))
+		report := err.(*DryRunErr).Report
+		require.Equal(t, DryRunClearIndex, report.Operation)
+		require.Equal(t, "products", report.IndexName)
+		require.Equal(t, "/1/indexes/products/clear", report.Path)
+		require.Equal(t, 42, report.Affected)
+
+		_, err = c.DeleteIndex("products")
+		require.Equal(t, DryRunDeleteIndex, err.(*DryRunErr).Report.Operation)
+
+		_, err = index.DeleteBy(Map{"filters": "brand:apple"})
+		report = err.(*DryRunErr).Report
+		require.Equal(t, DryRunDeleteBy, report.Operation)
+		require.Equal(t, Map{"filters": "brand:apple"}, report.Params)
+		require.Equal(t, 42, report.Affected)
+
+		err = index.DeleteByQuery("iphone", nil)
+		require.Equal(t, "iphone", err.(*DryRunErr).Report.Params["query"])
+
+		_, err = index.ClearRules(false)
+		require.Equal(t, 3, err.(*DryRunErr).Report.Affected)
+
+		_, err = index.ClearSynonyms(false)
+		require.Equal(t, 5, err.(*DryRunErr).Report.Affected)
+
+		_, err = c.MoveIndex("products", "missing")
+		report = err.(*DryRunErr).Report
+		require.Equal(t, DryRunMoveIndex, report.Operation)
+		require.Equal(t, "missing", report.Destination)
+		require.Equal(t, 0, report.Affected, "a missing destination has no records")
+
+		require.Equal(t, 7, strings.Count(log.String(), "[algolia] dry run: "))
+		require.Contains(t, log.String(), "clearIndex of products ([POST] /1/indexes/products/clear), affected: 42")
+	}
+
+	t.Log("TestDryRun: Check that the dry-run mode can be enabled per request")
+	{
+		c := &client{transport: newTestTransport(server)}
+		_, err := c.InitIndex("products").ClearWithRequestOptions(&RequestOptions{DryRun: true})
+		require.True(t, IsDryRun(err))
+	}
+}
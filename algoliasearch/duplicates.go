@@ -0,0 +1,93 @@
+package algoliasearch
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DuplicatesMode controls how Index.AddObjects and Index.UpdateObjects
+// handle the records of a batch sharing the same objectID (see
+// RequestOptions.Duplicates). As the engine applies the operations in order,
+// only the last of those records is eventually indexed.
+type DuplicatesMode int
+
+const (
+	// DuplicatesAllowed sends the records as is. It is the default mode.
+	DuplicatesAllowed DuplicatesMode = iota
+
+	// DuplicatesLastWriteWins only sends the last record of each objectID,
+	// at the position of its first occurrence. The duplicated objectIDs are
+	// reported by BatchRes.Duplicates.
+	DuplicatesLastWriteWins
+
+	// DuplicatesError sends nothing and returns a `*DuplicateObjectIDsErr` if
+	// several records share the same objectID.
+	DuplicatesError
+)
+
+// DuplicateObjectIDsErr is the error returned by Index.AddObjects and
+// Index.UpdateObjects, in DuplicatesError mode, when several records of the
+// batch share the same objectID. `ObjectIDs` lists those objectIDs in the
+// order of their first occurrence.
+type DuplicateObjectIDsErr struct {
+	ObjectIDs []string
+}
+
+func (e *DuplicateObjectIDsErr) Error() string {
+	return fmt.Sprintf("Cannot send batch: duplicate objectIDs %s", strings.Join(e.ObjectIDs, ", "))
+}
+
+// IsDuplicateObjectIDs returns `true` if the given error was caused by
+// several records of a batch sharing the same objectID.
+func IsDuplicateObjectIDs(err error) bool {
+	_, ok := err.(*DuplicateObjectIDsErr)
+	return ok
+}
+
+// duplicatesMode returns the DuplicatesMode set by the request options.
+func duplicatesMode(opts *RequestOptions) DuplicatesMode {
+	if opts == nil {
+		return DuplicatesAllowed
+	}
+	return opts.Duplicates
+}
+
+// dedupObjects applies the `mode` to the `objects` and returns the objects
+// to send along with the duplicated objectIDs, in the order of their first
+// occurrence. The objects without objectID are never considered duplicates.
+func dedupObjects(objects []Object, mode DuplicatesMode) (deduped []Object, duplicates []string, err error) {
+	if mode == DuplicatesAllowed {
+		return objects, nil, nil
+	}
+
+	positions := make(map[string]int, len(objects))
+	counts := make(map[string]int, len(objects))
+	deduped = make([]Object, 0, len(objects))
+
+	for _, o := range objects {
+		objectID, ok := o["objectID"].(string)
+		if !ok {
+			deduped = append(deduped, o)
+			continue
+		}
+
+		counts[objectID]++
+		if counts[objectID] == 2 {
+			duplicates = append(duplicates, objectID)
+		}
+
+		if pos, ok := positions[objectID]; ok {
+			deduped[pos] = o
+			continue
+		}
+
+		positions[objectID] = len(deduped)
+		deduped = append(deduped, o)
+	}
+
+	if len(duplicates) > 0 && mode == DuplicatesError {
+		return nil, duplicates, &DuplicateObjectIDsErr{ObjectIDs: duplicates}
+	}
+
+	return deduped, duplicates, nil
+}
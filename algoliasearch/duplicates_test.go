@@ -0,0 +1,67 @@
+package algoliasearch
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDuplicateObjectIDs(t *testing.T) {
+	objects := []Object{
+		{"objectID": "one", "version": 1},
+		{"objectID": "two", "version": 1},
+		{"name": "no objectID"},
+		{"objectID": "one", "version": 2},
+		{"objectID": "one", "version": 3},
+	}
+
+	t.Log("TestDuplicateObjectIDs: Check that the records are sent as is by default")
+	{
+		deduped, duplicates, err := dedupObjects(objects, DuplicatesAllowed)
+		require.Nil(t, err)
+		require.Nil(t, duplicates)
+		require.Equal(t, objects, deduped)
+	}
+
+	t.Log("TestDuplicateObjectIDs: Check that the last record of each objectID wins")
+	{
+		deduped, duplicates, err := dedupObjects(objects, DuplicatesLastWriteWins)
+		require.Nil(t, err)
+		require.Equal(t, []string{"one"}, duplicates)
+		require.Equal(t, []Object{
+			{"objectID": "one", "version": 3},
+			{"objectID": "two", "version": 1},
+			{"name": "no objectID"},
+		}, deduped)
+	}
+
+	t.Log("TestDuplicateObjectIDs: Check that the duplicates are reported")
+	{
+		var requests [][]BatchOperation
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body struct {
+				Requests []BatchOperation `json:"requests"`
+			}
+			require.Nil(t, json.NewDecoder(r.Body).Decode(&body))
+			requests = append(requests, body.Requests)
+			w.Write([]byte(`{"taskID":1,"objectIDs":["one","two"]}`))
+		}))
+		defer server.Close()
+		index := (&client{transport: newTestTransport(server)}).InitIndex("products")
+		twice := []Object{{"objectID": "one"}, {"objectID": "two"}, {"objectID": "one"}}
+
+		_, err := index.UpdateObjectsWithRequestOptions(twice, &RequestOptions{Duplicates: DuplicatesError})
+		require.True(t, IsDuplicateObjectIDs(err), "should reject the duplicates")
+		require.Equal(t, "Cannot send batch: duplicate objectIDs one", err.Error())
+		require.Len(t, requests, 0)
+
+		res, err := index.AddObjectsWithRequestOptions(twice, &RequestOptions{Duplicates: DuplicatesLastWriteWins})
+		require.Nil(t, err)
+		require.Equal(t, []string{"one"}, res.Duplicates)
+		require.Len(t, requests, 1)
+		require.Len(t, requests[0], 2)
+	}
+}
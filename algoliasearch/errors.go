@@ -1,9 +1,339 @@
 package algoliasearch
 
-import "errors"
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
 
 var (
 	NoMoreHitsErr     error = errors.New("No more hits")
 	NoMoreSynonymsErr error = errors.New("No more synonyms")
 	NoMoreRulesErr    error = errors.New("No more rules")
+	IndexNotFoundErr  error = errors.New("Index not found")
+
+	// NoMoreDictionaryEntriesErr is returned by DictionaryEntryIterator.Next
+	// once all the entries have been retrieved.
+	NoMoreDictionaryEntriesErr error = errors.New("No more dictionary entries")
+
+	// ReplicasModifiedErr is returned by Index.AddReplica and
+	// Index.RemoveReplica when the replicas of the index read once the update
+	// is published differ from the written ones, i.e. when they were
+	// concurrently modified.
+	ReplicasModifiedErr error = errors.New("Replicas modified concurrently")
+
+	// NotAppliedErr is reported by Client.CoordinatedBatch for the writes
+	// which were not sent because a previous write already failed.
+	NotAppliedErr error = errors.New("Write not applied because a previous write failed")
 )
+
+// Kinds of well-known API errors. They are not returned as-is but an
+// `*AlgoliaErr` matching one of them is reported by its `Kind` method and by
+// the corresponding `IsXxx` function (e.g. IsQuotaExceeded).
+var (
+	IndexBlockedErr        error = errors.New("Index blocked")
+	QuotaExceededErr       error = errors.New("Record quota exceeded")
+	FeatureNotAvailableErr error = errors.New("Feature not available with the current plan")
+	TooManyRequestsErr     error = errors.New("Too many requests")
+)
+
+// errorKinds lists, in order of precedence, how API errors are classified:
+// an error is of a given kind if its status code is the expected one (when
+// non-zero) or if its message contains one of the patterns.
+var errorKinds = []struct {
+	kind        error
+	status      int
+	patterns    []string
+	remediation string
+}{
+	{
+		kind:        QuotaExceededErr,
+		patterns:    []string{"quota exceeded", "max records"},
+		remediation: "Delete unused records or upgrade your plan; retrying will not help until then.",
+	},
+	{
+		kind:        IndexBlockedErr,
+		patterns:    []string{"blocked"},
+		remediation: "Indexing is blocked on this index or application; wait for the pending operations to complete or contact Algolia support.",
+	},
+	{
+		kind:        FeatureNotAvailableErr,
+		patterns:    []string{"not available", "not allowed with your plan", "upgrade your plan"},
+		remediation: "This feature is not part of your current plan; upgrade your plan or stop using it.",
+	},
+	{
+		kind:        TooManyRequestsErr,
+		status:      http.StatusTooManyRequests,
+		patterns:    []string{"too many"},
+		remediation: "Slow down the rate of requests or increase the budget set with Client.SetRateLimitRetryBudget.",
+	},
+}
+
+// AlgoliaErr is the error returned when the Algolia API answers with a non-2XX
+// HTTP status code. `Message` is the error message sent by the API and
+// `Status` the HTTP status code of the response.
+type AlgoliaErr struct {
+	Message string `json:"message"`
+	Status  int    `json:"status"`
+}
+
+func (e *AlgoliaErr) Error() string {
+	return fmt.Sprintf("Algolia API error %d: %s", e.Status, e.Message)
+}
+
+// Is reports whether the `target` error is the kind of the error (see Kind) or
+// an `*AlgoliaErr` with the same status code and, if the target's message is
+// non-empty, the same message. It lets `errors.Is(err, &AlgoliaErr{Status:
+// 404})` match any not found error and `errors.Is(err, QuotaExceededErr)` any
+// quota error.
+func (e *AlgoliaErr) Is(target error) bool {
+	if target != nil && target == e.Kind() {
+		return true
+	}
+
+	t, ok := target.(*AlgoliaErr)
+	if !ok {
+		return false
+	}
+
+	return t.Status == e.Status && (t.Message == "" || t.Message == e.Message)
+}
+
+// Kind returns the kind of well-known error (IndexBlockedErr,
+// QuotaExceededErr, FeatureNotAvailableErr or TooManyRequestsErr) the error
+// corresponds to, or `nil` if it is not one of them.
+func (e *AlgoliaErr) Kind() error {
+	message := strings.ToLower(e.Message)
+	for _, k := range errorKinds {
+		if k.status != 0 && k.status == e.Status {
+			return k.kind
+		}
+		for _, pattern := range k.patterns {
+			if strings.Contains(message, pattern) {
+				return k.kind
+			}
+		}
+	}
+	return nil
+}
+
+// Remediation returns a hint describing how to address the error if it is a
+// well-known one (see Kind), or an empty string otherwise.
+func (e *AlgoliaErr) Remediation() string {
+	kind := e.Kind()
+	for _, k := range errorKinds {
+		if k.kind == kind {
+			return k.remediation
+		}
+	}
+	return ""
+}
+
+// HostAttempt describes a failed attempt to perform a request against a given
+// Algolia host.
+type HostAttempt struct {
+	Host     string
+	Err      error
+	Duration time.Duration
+}
+
+// NoMoreHostToTryErr is the error returned when a request failed on all the
+// hosts of the retry strategy. `Attempts` lists every attempted host, in
+// order, along with the error it produced (timeout, DNS failure, non-2XX
+// response, etc.) and the time spent trying it.
+type NoMoreHostToTryErr struct {
+	Attempts []HostAttempt
+}
+
+func (e *NoMoreHostToTryErr) Error() string {
+	var buf bytes.Buffer
+	buf.WriteString("All hosts failed:")
+	for _, a := range e.Attempts {
+		fmt.Fprintf(&buf, " [%s (%s): %s]", a.Host, a.Duration, a.Err)
+	}
+	return buf.String()
+}
+
+// Unwrap returns the error of the last attempt answered by the API, if any,
+// or else the error of the last attempt, so that the answer of the API (e.g.
+// a 404) is not hidden by the network errors of the hosts tried afterwards.
+func (e *NoMoreHostToTryErr) Unwrap() error {
+	for n := len(e.Attempts) - 1; n >= 0; n-- {
+		if _, ok := asAlgoliaErr(e.Attempts[n].Err); ok {
+			return e.Attempts[n].Err
+		}
+	}
+	if len(e.Attempts) == 0 {
+		return nil
+	}
+	return e.Attempts[len(e.Attempts)-1].Err
+}
+
+// TimeoutErr is the error returned when a request could not be completed
+// before the deadline of the call (see Timeouts.Total and
+// RequestOptions.Timeout). `Attempts` lists the hosts tried before the
+// deadline was exceeded.
+type TimeoutErr struct {
+	Timeout  time.Duration
+	Attempts []HostAttempt
+}
+
+func (e *TimeoutErr) Error() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "Request timed out after %s:", e.Timeout)
+	for _, a := range e.Attempts {
+		fmt.Fprintf(&buf, " [%s (%s): %s]", a.Host, a.Duration, a.Err)
+	}
+	return buf.String()
+}
+
+// Unwrap returns the error of the last attempt, if any.
+func (e *TimeoutErr) Unwrap() error {
+	if len(e.Attempts) == 0 {
+		return nil
+	}
+	return e.Attempts[len(e.Attempts)-1].Err
+}
+
+// RateLimitedErr is the error returned when the API kept rejecting a request
+// with a 429 HTTP status code until the rate-limit retry budget of the client
+// (see Client.SetRateLimitRetryBudget) was exhausted. `RetryAfter` is the last
+// delay suggested by the API, `Attempts` the number of times the request was
+// sent and `Waited` the total time spent waiting between those attempts.
+type RateLimitedErr struct {
+	RetryAfter time.Duration
+	Attempts   int
+	Waited     time.Duration
+	Err        *AlgoliaErr
+}
+
+func (e *RateLimitedErr) Error() string {
+	return fmt.Sprintf("Rate limited after %d attempts (waited %s, retry after %s): %s", e.Attempts, e.Waited, e.RetryAfter, e.Err)
+}
+
+// Unwrap returns the underlying `*AlgoliaErr`.
+func (e *RateLimitedErr) Unwrap() error {
+	return e.Err
+}
+
+// ReadOnlyErr is the error returned, without sending any request, by the
+// methods of a read-only client (see WithReadOnly) which would modify the
+// application. `Method` and `Path` describe the rejected request.
+type ReadOnlyErr struct {
+	Method string
+	Path   string
+}
+
+func (e *ReadOnlyErr) Error() string {
+	return fmt.Sprintf("Cannot perform request [%s] %s: the client is read-only", e.Method, e.Path)
+}
+
+// TooManyDeletionsErr is the error returned by QueryDeleter.Run, before any
+// record is deleted, when more than `Max` records match the query.
+type TooManyDeletionsErr struct {
+	Max int
+}
+
+func (e *TooManyDeletionsErr) Error() string {
+	return fmt.Sprintf("Cannot delete by query: more than %d records match", e.Max)
+}
+
+// IsTooManyDeletions returns `true` if the given error was caused by a
+// deletion by query matching more records than allowed.
+func IsTooManyDeletions(err error) bool {
+	_, ok := err.(*TooManyDeletionsErr)
+	return ok
+}
+
+// newAlgoliaErr builds an `*AlgoliaErr` from the `body` of an API response
+// whose status code is `status`. If the body is not a valid JSON error, the
+// raw body is used as the error message.
+func newAlgoliaErr(status int, body []byte) *AlgoliaErr {
+	var e AlgoliaErr
+	if err := json.Unmarshal(body, &e); err != nil || e.Message == "" {
+		e.Message = string(body)
+	}
+	e.Status = status
+	return &e
+}
+
+// asAlgoliaErr walks the chain of wrapped errors, starting at `err`, and
+// returns the first `*AlgoliaErr` found, if any.
+func asAlgoliaErr(err error) (*AlgoliaErr, bool) {
+	for err != nil {
+		if e, ok := err.(*AlgoliaErr); ok {
+			return e, true
+		}
+
+		wrapper, ok := err.(interface {
+			Unwrap() error
+		})
+		if !ok {
+			return nil, false
+		}
+		err = wrapper.Unwrap()
+	}
+
+	return nil, false
+}
+
+// IsNotFound returns `true` if the given error was caused by a resource
+// (index, record, synonym, rule, key, etc.) which does not exist.
+func IsNotFound(err error) bool {
+	if err == IndexNotFoundErr {
+		return true
+	}
+
+	e, ok := asAlgoliaErr(err)
+	return ok && e.Status == http.StatusNotFound
+}
+
+// IsReadOnly returns `true` if the given error was caused by a read-only
+// client refusing to modify the application.
+func IsReadOnly(err error) bool {
+	_, ok := err.(*ReadOnlyErr)
+	return ok
+}
+
+// IsTimeout returns `true` if the given error was caused by a request
+// exceeding the deadline of the call.
+func IsTimeout(err error) bool {
+	_, ok := err.(*TimeoutErr)
+	return ok
+}
+
+// IsRateLimited returns `true` if the given error was caused by the API
+// rejecting the request because too many requests were sent.
+func IsRateLimited(err error) bool {
+	e, ok := asAlgoliaErr(err)
+	return ok && e.Status == http.StatusTooManyRequests
+}
+
+// isKind returns `true` if the given error was caused by an API error of the
+// given `kind`.
+func isKind(err error, kind error) bool {
+	e, ok := asAlgoliaErr(err)
+	return ok && e.Kind() == kind
+}
+
+// IsIndexBlocked returns `true` if the given error was caused by indexing
+// operations being blocked on the index or the application.
+func IsIndexBlocked(err error) bool {
+	return isKind(err, IndexBlockedErr)
+}
+
+// IsQuotaExceeded returns `true` if the given error was caused by the record
+// quota of the application being exceeded.
+func IsQuotaExceeded(err error) bool {
+	return isKind(err, QuotaExceededErr)
+}
+
+// IsFeatureNotAvailable returns `true` if the given error was caused by the
+// use of a feature which is not part of the plan of the application.
+func IsFeatureNotAvailable(err error) bool {
+	return isKind(err, FeatureNotAvailableErr)
+}
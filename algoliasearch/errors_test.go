@@ -0,0 +1,85 @@
+package algoliasearch
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type wrappingErr struct {
+	err error
+}
+
+func (e wrappingErr) Error() string { return fmt.Sprintf("wrapped: %s", e.err) }
+func (e wrappingErr) Unwrap() error { return e.err }
+
+func TestAlgoliaErr(t *testing.T) {
+	t.Log("TestAlgoliaErr: Check that API errors are correctly decoded")
+	{
+		err := newAlgoliaErr(404, []byte("{\"message\":\"ObjectID does not exist\",\"status\":404}\n"))
+		require.Equal(t, "ObjectID does not exist", err.Message)
+		require.Equal(t, 404, err.Status)
+		require.True(t, IsNotFound(err))
+		require.False(t, IsRateLimited(err))
+		require.True(t, err.Is(&AlgoliaErr{Status: 404}))
+		require.False(t, err.Is(&AlgoliaErr{Status: 404, Message: "Index does not exist"}))
+	}
+
+	t.Log("TestAlgoliaErr: Check that non-JSON bodies are kept as messages")
+	{
+		err := newAlgoliaErr(502, []byte("Bad Gateway"))
+		require.Equal(t, "Bad Gateway", err.Message)
+		require.Equal(t, 502, err.Status)
+	}
+
+	t.Log("TestAlgoliaErr: Check that wrapped errors are detected")
+	{
+		err := wrappingErr{newAlgoliaErr(429, []byte("{\"message\":\"Too many requests\"}"))}
+		require.True(t, IsRateLimited(err))
+		require.False(t, IsNotFound(err))
+		require.True(t, IsNotFound(IndexNotFoundErr))
+		require.False(t, IsNotFound(nil))
+	}
+
+	t.Log("TestAlgoliaErr: Check that retry exhaustion errors keep all the attempts")
+	{
+		err := &NoMoreHostToTryErr{Attempts: []HostAttempt{
+			{Host: "APPID-1.algolianet.com", Err: fmt.Errorf("timeout")},
+			{Host: "APPID-2.algolianet.com", Err: newAlgoliaErr(500, []byte("Internal error"))},
+		}}
+		require.Contains(t, err.Error(), "APPID-1.algolianet.com")
+		require.Contains(t, err.Error(), "APPID-2.algolianet.com")
+		require.Equal(t, err.Attempts[1].Err, err.Unwrap())
+
+		err.Attempts = append(err.Attempts, HostAttempt{Host: "APPID-3.algolianet.com", Err: fmt.Errorf("timeout")})
+		require.Equal(t, err.Attempts[1].Err, err.Unwrap(), "should unwrap the answer of the API")
+		require.False(t, isHostFailure(newAlgoliaErr(404, nil)))
+		require.True(t, isHostFailure(newAlgoliaErr(503, nil)))
+		require.True(t, isHostFailure(fmt.Errorf("timeout")))
+	}
+
+	t.Log("TestAlgoliaErr: Check that well-known errors are classified")
+	{
+		quota := newAlgoliaErr(403, []byte(`{"message":"Record quota exceeded, change plan or delete records."}`))
+		require.Equal(t, QuotaExceededErr, quota.Kind())
+		require.True(t, quota.Is(QuotaExceededErr))
+		require.True(t, IsQuotaExceeded(wrappingErr{quota}))
+		require.NotEmpty(t, quota.Remediation())
+
+		blocked := newAlgoliaErr(422, []byte(`{"message":"Indexing is blocked on this index"}`))
+		require.True(t, IsIndexBlocked(blocked))
+		require.False(t, IsQuotaExceeded(blocked))
+
+		feature := newAlgoliaErr(403, []byte(`{"message":"Feature not available with your plan"}`))
+		require.True(t, IsFeatureNotAvailable(feature))
+
+		tooMany := newAlgoliaErr(429, []byte(`{"message":"Slow down"}`))
+		require.Equal(t, TooManyRequestsErr, tooMany.Kind())
+
+		notFound := newAlgoliaErr(404, []byte(`{"message":"Index does not exist"}`))
+		require.Nil(t, notFound.Kind())
+		require.Empty(t, notFound.Remediation())
+		require.False(t, notFound.Is(nil))
+	}
+}
@@ -0,0 +1,319 @@
+package algoliasearch_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/algolia/algoliasearch-client-go/algoliasearch"
+)
+
+// exampleResponses are the canned answers of the fake Algolia API the
+// examples are run against, by method and path. Any other request is answered
+// with a 404.
+var exampleResponses = map[string]string{
+	"POST /1/indexes/products/query":               `{"hits":[{"objectID":"iphone","name":"iPhone","price":999,"_highlightResult":{"name":{"value":"<em>iPhone</em>","matchLevel":"full","matchedWords":["iphone"]}}}],"nbHits":1,"page":0,"nbPages":1,"hitsPerPage":20,"processingTimeMS":1}`,
+	"POST /1/indexes/products/batch":               `{"taskID":1,"objectIDs":["iphone","galaxy"]}`,
+	"POST /1/indexes/products_tmp/batch":           `{"taskID":1,"objectIDs":["iphone"]}`,
+	"POST /1/indexes/products/browse":              `{"hits":[{"objectID":"iphone"},{"objectID":"ipad"}],"nbHits":2,"page":0,"nbPages":1,"hitsPerPage":1000}`,
+	"PUT /1/indexes/products/rules/promote-iphone": `{"taskID":1,"id":"promote-iphone","updatedAt":"2018-01-01T00:00:00.000Z"}`,
+	"POST /1/indexes/*/queries":                    `{"results":[{"index":"products","hits":[],"nbHits":12},{"index":"articles","hits":[],"nbHits":3}]}`,
+	"POST /1/indexes/products_tmp/operation":       `{"taskID":1,"updatedAt":"2018-01-01T00:00:00.000Z"}`,
+	"GET /1/indexes/products/task/1":               `{"status":"published","pendingTask":false}`,
+	"GET /1/indexes/products_tmp/task/1":           `{"status":"published","pendingTask":false}`,
+}
+
+var (
+	exampleServer     *httptest.Server
+	exampleServerOnce sync.Once
+)
+
+// exampleRequester sends all the requests to the fake Algolia API, whatever
+// their host.
+type exampleRequester struct {
+	server *httptest.Server
+}
+
+func (r exampleRequester) Do(req *http.Request) (*http.Response, error) {
+	u, err := url.Parse(r.server.URL)
+	if err != nil {
+		return nil, err
+	}
+	req.URL.Scheme, req.URL.Host = u.Scheme, u.Host
+	return r.server.Client().Do(req)
+}
+
+// newClient returns a client of the fake Algolia API serving the
+// exampleResponses, so that the output of the examples can be checked. Real
+// code uses algoliasearch.NewClient instead.
+func newClient(appID, apiKey string) algoliasearch.Client {
+	exampleServerOnce.Do(func() {
+		exampleServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, ok := exampleResponses[r.Method+" "+r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				body = `{"message":"Not found","status":404}`
+			}
+			fmt.Fprint(w, body)
+		}))
+	})
+
+	return algoliasearch.NewClientWithOptions(appID, apiKey, algoliasearch.WithRequester(exampleRequester{exampleServer}))
+}
+
+func ExampleNewClient() {
+	client := algoliasearch.NewClient(os.Getenv("ALGOLIA_APPLICATION_ID"), os.Getenv("ALGOLIA_API_KEY"))
+
+	// Fail fast instead of waiting for the default timeouts
+	client.SetTimeouts(algoliasearch.Timeouts{
+		Connect: time.Second,
+		Read:    500 * time.Millisecond,
+		Total:   2 * time.Second,
+	})
+
+	if err := client.IsAlive(); err != nil {
+		fmt.Println("Algolia is not reachable:", err)
+	}
+}
+
+func ExampleIndex_Search() {
+	client := newClient("YourApplicationID", "YourSearchOnlyAPIKey")
+	index := client.InitIndex("products")
+
+	res, err := index.Search("iphone", algoliasearch.Map{
+		"attributesToRetrieve":  []string{"name", "price"},
+		"attributesToHighlight": []string{"name"},
+		"facetFilters":          algoliasearch.FilterGroups{{"brand:apple", "brand:samsung"}},
+		"hitsPerPage":           20,
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Printf("%d products found in %dms\n", res.NbHits, res.ProcessingTimeMS)
+	for _, hit := range res.Hits {
+		name, _ := algoliasearch.HighlightedValue(hit, "name")
+		fmt.Println(name, hit["price"])
+	}
+	// Output:
+	// 1 products found in 1ms
+	// <em>iPhone</em> 999
+}
+
+func ExampleIndex_AddObjects() {
+	client := newClient("YourApplicationID", "YourAdminAPIKey")
+	index := client.InitIndex("products")
+
+	res, err := index.AddObjects([]algoliasearch.Object{
+		{"objectID": "iphone", "name": "iPhone", "brand": "apple"},
+		{"objectID": "galaxy", "name": "Galaxy", "brand": "samsung"},
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	// Indexing is asynchronous: wait for the records to be searchable
+	if err = index.WaitTask(res.TaskID); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Println("Indexed:", res.ObjectIDs)
+	// Output: Indexed: [iphone galaxy]
+}
+
+func ExampleIndex_PartialUpdateMany() {
+	client := newClient("YourApplicationID", "YourAdminAPIKey")
+	index := client.InitIndex("products")
+
+	res, err := index.PartialUpdateMany(map[string]algoliasearch.Map{
+		"iphone": {"stock": algoliasearch.IncrementOp(10)},
+		"galaxy": {"price": 799},
+	}, false)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	if err = index.WaitTasks(res.TaskIDs()); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Println("Stock and price updated")
+	// Output: Stock and price updated
+}
+
+func ExampleIndex_BrowseAll() {
+	client := newClient("YourApplicationID", "YourAdminAPIKey")
+	index := client.InitIndex("products")
+
+	it, err := index.BrowseAll(algoliasearch.Map{"filters": "brand:apple"})
+	if err != nil && err != algoliasearch.NoMoreHitsErr {
+		fmt.Println(err)
+		return
+	}
+
+	for {
+		hit, err := it.Next()
+		if err == algoliasearch.NoMoreHitsErr {
+			break
+		} else if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Println(hit["objectID"])
+	}
+	// Output:
+	// iphone
+	// ipad
+}
+
+func ExampleIndex_SaveRule() {
+	client := newClient("YourApplicationID", "YourAdminAPIKey")
+	index := client.InitIndex("products")
+
+	rule := algoliasearch.Rule{
+		ObjectID:  "promote-iphone",
+		Condition: algoliasearch.NewSimpleRuleCondition(algoliasearch.Contains, "phone"),
+		Consequence: algoliasearch.RuleConsequence{
+			Params: algoliasearch.Map{
+				"query": algoliasearch.QueryIncrementalEdit{
+					Edits: []algoliasearch.Edit{algoliasearch.NewRemoveEdit("cheap")},
+				},
+			},
+			Promote: []algoliasearch.PromotedObject{{ObjectID: "iphone", Position: 0}},
+		},
+	}
+
+	res, err := index.SaveRule(rule, true)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	if err = index.WaitTask(res.TaskID); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Println("Rule saved")
+	// Output: Rule saved
+}
+
+func ExampleClient_MultipleQueries() {
+	client := newClient("YourApplicationID", "YourSearchOnlyAPIKey")
+
+	res, err := client.MultipleQueries([]algoliasearch.IndexedQuery{
+		{IndexName: "products", Query: "iphone"},
+		{IndexName: "articles", Query: "iphone", Params: algoliasearch.Map{"hitsPerPage": 3}},
+	}, algoliasearch.StrategyNone)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	for _, r := range res {
+		fmt.Printf("%s: %d hits\n", r.Index, r.NbHits)
+	}
+	// Output:
+	// products: 12 hits
+	// articles: 3 hits
+}
+
+func ExampleClient_MoveIndex() {
+	client := newClient("YourApplicationID", "YourAdminAPIKey")
+
+	// Reindex all the records in a temporary index, then atomically replace
+	// the production index with it.
+	tmp := client.InitIndex("products_tmp")
+	batch, err := tmp.AddObjects([]algoliasearch.Object{
+		{"objectID": "iphone", "name": "iPhone"},
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	if err = tmp.WaitTask(batch.TaskID); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	res, err := client.MoveIndex("products_tmp", "products")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	if err = client.InitIndex("products").WaitTask(res.TaskID); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Println("products replaced")
+	// Output: products replaced
+}
+
+func ExampleIsNotFound() {
+	client := newClient("YourApplicationID", "YourSearchOnlyAPIKey")
+	index := client.InitIndex("products")
+
+	object, err := index.GetObject("unknown", nil)
+	switch {
+	case algoliasearch.IsNotFound(err):
+		fmt.Println("No such product")
+	case algoliasearch.IsRateLimited(err):
+		fmt.Println("Slow down!")
+	case err != nil:
+		fmt.Println(err)
+	default:
+		fmt.Println(object["name"])
+	}
+	// Output: No such product
+}
+
+func ExampleGenerateSecuredAPIKey() {
+	key, err := algoliasearch.GenerateSecuredAPIKey("YourSearchOnlyAPIKey", algoliasearch.Map{
+		"filters": "visible_by:group/42",
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Println(key)
+	// Output: NTM1NzNlNDY0YTA1N2Q1NjkwYThkOGVjNjU3ZDJhMzAzZjk1MDliMWJiZWVjMWZhMGFhNDhlYzU0MmJhYmNhMGZpbHRlcnM9dmlzaWJsZV9ieSUzQWdyb3VwJTJGNDI=
+}
+
+func ExampleNewObjectID() {
+	fmt.Println(algoliasearch.NewObjectID("tenant-42", "sku-1337"))
+
+	objectID, err := algoliasearch.NewPrefixedObjectID("product_", "tenant-42", "sku-1337")
+	fmt.Println(objectID, err)
+	// Output:
+	// eba74bad03fda60fb09fac034bb39072a285a6eb
+	// product_eba74bad03fda60fb09fac034bb39072a285a6eb <nil>
+}
+
+func ExampleNewFilterGroups() {
+	filters, err := algoliasearch.NewFilterGroups(
+		[]string{"brand:apple", "brand:samsung"},
+		"category:phone",
+	)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	data, _ := json.Marshal(filters)
+	fmt.Println(string(data))
+	// Output: [["brand:apple","brand:samsung"],"category:phone"]
+}
@@ -0,0 +1,121 @@
+package algoliasearch
+
+import (
+	"fmt"
+	"sort"
+)
+
+// FederatedQuery is one of the named queries of a federated search (see
+// Client.FederatedSearch). The `Name` identifies the origin of the hits in
+// the merged results, hence it should be unique. The `Params` are the
+// search parameters of the query on `IndexName`. The `Weight` (defaults to
+// 1 if zero) controls how the hits of the query are ranked compared to the
+// other queries: with a weight of 2, the second hit of the query ranks like
+// the first hits of the queries of weight 1.
+type FederatedQuery struct {
+	Name      string
+	IndexName string
+	Params    Map
+	Weight    float64
+}
+
+// FederatedHit is a hit of the merged results of a federated search.
+// `Source` is the name of the FederatedQuery the hit comes from and
+// `Position` its position (starting at 0) in the results of that query.
+type FederatedHit struct {
+	Source   string
+	Index    string
+	Position int
+	Hit      Map
+}
+
+// FederatedRes holds the results of a federated search: the interleaved
+// `Hits` of all the queries and the individual `Results` of each query,
+// indexed by name.
+type FederatedRes struct {
+	Hits    []FederatedHit
+	Results map[string]MultipleQueryRes
+}
+
+func (c *client) FederatedSearch(query string, queries []FederatedQuery) (res FederatedRes, err error) {
+	return c.FederatedSearchWithRequestOptions(query, queries, nil)
+}
+
+func (c *client) FederatedSearchWithRequestOptions(query string, queries []FederatedQuery, opts *RequestOptions) (res FederatedRes, err error) {
+	indexedQueries := make([]IndexedQuery, len(queries))
+	names := make(map[string]bool, len(queries))
+	for i, q := range queries {
+		if q.Name == "" || names[q.Name] {
+			err = fmt.Errorf("Federated queries should have unique non-empty names, got %q", q.Name)
+			return
+		}
+		if q.Weight < 0 {
+			err = fmt.Errorf("Federated query %s should have a positive weight, got %f", q.Name, q.Weight)
+			return
+		}
+		names[q.Name] = true
+
+		indexedQueries[i] = IndexedQuery{
+			IndexName: q.IndexName,
+			Query:     query,
+			Params:    q.Params,
+		}
+	}
+
+	results, err := c.MultipleQueriesWithRequestOptions(indexedQueries, StrategyNone, opts)
+	if err != nil {
+		return
+	}
+
+	if len(results) != len(queries) {
+		err = fmt.Errorf("Federated search expected %d results, got %d", len(queries), len(results))
+		return
+	}
+
+	res = mergeFederatedResults(queries, results)
+	return
+}
+
+// mergeFederatedResults interleaves the hits of the `results` of the
+// `queries`: hits are ranked by decreasing `weight / (position + 1)`, ties
+// being broken by the order of the queries.
+func mergeFederatedResults(queries []FederatedQuery, results []MultipleQueryRes) FederatedRes {
+	res := FederatedRes{Results: make(map[string]MultipleQueryRes, len(queries))}
+
+	var scores []float64
+	for i, q := range queries {
+		res.Results[q.Name] = results[i]
+
+		weight := q.Weight
+		if weight == 0 {
+			weight = 1
+		}
+
+		for position, hit := range results[i].Hits {
+			res.Hits = append(res.Hits, FederatedHit{
+				Source:   q.Name,
+				Index:    q.IndexName,
+				Position: position,
+				Hit:      hit,
+			})
+			scores = append(scores, weight/float64(position+1))
+		}
+	}
+
+	sort.Stable(federatedHitsByScore{hits: res.Hits, scores: scores})
+	return res
+}
+
+type federatedHitsByScore struct {
+	hits   []FederatedHit
+	scores []float64
+}
+
+func (s federatedHitsByScore) Len() int { return len(s.hits) }
+
+func (s federatedHitsByScore) Less(i, j int) bool { return s.scores[i] > s.scores[j] }
+
+func (s federatedHitsByScore) Swap(i, j int) {
+	s.hits[i], s.hits[j] = s.hits[j], s.hits[i]
+	s.scores[i], s.scores[j] = s.scores[j], s.scores[i]
+}
@@ -0,0 +1,70 @@
+package algoliasearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFederatedSearch(t *testing.T) {
+	hits := func(objectIDs ...string) []Map {
+		res := make([]Map, len(objectIDs))
+		for i, objectID := range objectIDs {
+			res[i] = Map{"objectID": objectID}
+		}
+		return res
+	}
+
+	results := []MultipleQueryRes{
+		{Index: "products", QueryRes: QueryRes{Hits: hits("p1", "p2", "p3")}},
+		{Index: "articles", QueryRes: QueryRes{Hits: hits("a1", "a2")}},
+		{Index: "faq", QueryRes: QueryRes{Hits: hits("f1")}},
+	}
+
+	sources := func(res FederatedRes) (objectIDs []string) {
+		for _, h := range res.Hits {
+			objectIDs = append(objectIDs, h.Source+":"+h.Hit["objectID"].(string))
+		}
+		return
+	}
+
+	t.Log("TestFederatedSearch: Check the interleaving of the hits")
+	{
+		res := mergeFederatedResults([]FederatedQuery{
+			{Name: "products", IndexName: "products"},
+			{Name: "articles", IndexName: "articles"},
+			{Name: "faq", IndexName: "faq"},
+		}, results)
+		require.Equal(t, []string{
+			"products:p1", "articles:a1", "faq:f1",
+			"products:p2", "articles:a2",
+			"products:p3",
+		}, sources(res))
+		require.Equal(t, "articles", res.Hits[1].Index)
+		require.Equal(t, 1, res.Hits[3].Position)
+		require.Len(t, res.Results["products"].Hits, 3)
+	}
+
+	t.Log("TestFederatedSearch: Check the weighting of the queries")
+	{
+		res := mergeFederatedResults([]FederatedQuery{
+			{Name: "products", IndexName: "products", Weight: 2},
+			{Name: "articles", IndexName: "articles"},
+			{Name: "faq", IndexName: "faq", Weight: 0.5},
+		}, results)
+		require.Equal(t, []string{
+			"products:p1", "products:p2", "articles:a1",
+			"products:p3", "articles:a2", "faq:f1",
+		}, sources(res))
+	}
+
+	t.Log("TestFederatedSearch: Check the validation of the queries")
+	{
+		c := &client{}
+		_, err := c.FederatedSearch("phone", []FederatedQuery{{Name: "products"}, {Name: "products"}})
+		require.NotNil(t, err, "should reject duplicate names")
+
+		_, err = c.FederatedSearch("phone", []FederatedQuery{{Name: "products", Weight: -1}})
+		require.NotNil(t, err, "should reject negative weights")
+	}
+}
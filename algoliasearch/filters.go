@@ -0,0 +1,127 @@
+package algoliasearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FilterGroup is a group of filters (e.g. `company:tesla`) combined with a
+// logical OR.
+type FilterGroup []string
+
+func (g FilterGroup) MarshalJSON() ([]byte, error) {
+	if len(g) == 1 {
+		return json.Marshal(g[0])
+	}
+	return json.Marshal([]string(g))
+}
+
+// FilterGroups is a list of filter groups combined with a logical AND, to be
+// used as the value of the `facetFilters`, `optionalFilters`, `tagFilters` or
+// `numericFilters` search parameters. For instance, the following filters
+// match the records whose company is either Tesla or SpaceX and whose
+// category is car:
+//
+//	FilterGroups{
+//		{"company:tesla", "company:spacex"},
+//		{"category:car"},
+//	}
+type FilterGroups []FilterGroup
+
+// NewFilterGroups returns FilterGroups combining the given `filters` with a
+// logical AND. Each filter is either a single filter (`string`) or a group of
+// filters combined with a logical OR (`[]string` or `FilterGroup`).
+func NewFilterGroups(filters ...interface{}) (FilterGroups, error) {
+	groups := make(FilterGroups, len(filters))
+	for i, f := range filters {
+		switch f := f.(type) {
+		case string:
+			groups[i] = FilterGroup{f}
+		case []string:
+			groups[i] = FilterGroup(f)
+		case FilterGroup:
+			groups[i] = f
+		default:
+			return nil, fmt.Errorf("Invalid filter %#v: should be a string, a []string or a FilterGroup", f)
+		}
+	}
+	return groups, nil
+}
+
+// checkFilterGroups checks that the value `v` of the `k` filters parameter
+// (`facetFilters`, `optionalFilters`, `reRankingApplyFilter`, `tagFilters` or
+// `numericFilters`) has a valid type and, for nested filters, that no filter
+// nor group is empty.
+func checkFilterGroups(k string, v interface{}) error {
+	var groups [][]string
+
+	switch v := v.(type) {
+	case string, []interface{}:
+		return nil
+	case []string:
+		for _, f := range v {
+			groups = append(groups, []string{f})
+		}
+	case [][]string:
+		groups = v
+	case FilterGroups:
+		for _, g := range v {
+			groups = append(groups, g)
+		}
+	default:
+		return invalidType(k, "string, []string, [][]string, []interface{} or FilterGroups")
+	}
+
+	for _, group := range groups {
+		if len(group) == 0 {
+			return fmt.Errorf("`%s` should not contain empty groups of filters", k)
+		}
+		for _, f := range group {
+			if strings.TrimSpace(f) == "" {
+				return fmt.Errorf("`%s` should not contain empty filters", k)
+			}
+			if (k == "facetFilters" || k == "optionalFilters" || k == "reRankingApplyFilter") && !strings.Contains(f, ":") {
+				return fmt.Errorf("`%s` should only contain `facet:value` filters, got %q", k, f)
+			}
+			if k == "optionalFilters" {
+				if err := checkOptionalFilterScore(f); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// OptionalFilter returns the optional filter `facet:value` with the given
+// `score`, i.e. `facet:value<score=N>`. The score is omitted if it is 1, the
+// default score.
+func OptionalFilter(facet, value string, score int) string {
+	if score == 1 {
+		return facet + ":" + value
+	}
+	return fmt.Sprintf("%s:%s<score=%d>", facet, value, score)
+}
+
+// checkOptionalFilterScore checks that the score of the optional filter `f`,
+// if any, is a valid `<score=N>` suffix with a non-negative integer N.
+func checkOptionalFilterScore(f string) error {
+	n := strings.Index(f, "<score=")
+	if n == -1 {
+		return nil
+	}
+
+	score := f[n+len("<score="):]
+	if !strings.HasSuffix(score, ">") {
+		return fmt.Errorf("Invalid optional filter %q: score should be of the form `<score=N>`", f)
+	}
+
+	if s, err := strconv.Atoi(strings.TrimSuffix(score, ">")); err != nil || s < 0 {
+		return fmt.Errorf("Invalid optional filter %q: score should be a non-negative integer", f)
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package algoliasearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterGroups(t *testing.T) {
+	t.Log("TestFilterGroups: Check the encoding of filter groups")
+	{
+		groups, err := NewFilterGroups([]string{"company:tesla", "company:spacex"}, "category:car")
+		require.Nil(t, err)
+		require.Equal(t, FilterGroups{{"company:tesla", "company:spacex"}, {"category:car"}}, groups)
+		require.Equal(t,
+			"facetFilters=%5B%5B%22company%3Atesla%22%2C%22company%3Aspacex%22%5D%2C%22category%3Acar%22%5D",
+			encodeMap(Map{"facetFilters": groups}),
+		)
+
+		_, err = NewFilterGroups(42)
+		require.NotNil(t, err)
+	}
+
+	t.Log("TestFilterGroups: Check the accepted forms")
+	for _, params := range []Map{
+		{"facetFilters": "company:tesla"},
+		{"facetFilters": []string{"company:tesla", "category:car"}},
+		{"facetFilters": [][]string{{"company:tesla", "company:spacex"}, {"category:car"}}},
+		{"facetFilters": []interface{}{[]string{"company:tesla", "company:spacex"}, "category:car"}},
+		{"tagFilters": FilterGroups{{"promo", "new"}, {"-discontinued"}}},
+		{"numericFilters": [][]string{{"price<10", "price>100"}}},
+	} {
+		require.Nil(t, checkQuery(params), "should accept %v", params)
+	}
+
+	t.Log("TestFilterGroups: Check the rejected forms")
+	for _, params := range []Map{
+		{"facetFilters": 42},
+		{"facetFilters": [][]string{{}}},
+		{"facetFilters": [][]string{{"company:tesla", " "}}},
+		{"facetFilters": []string{"tesla"}},
+		{"tagFilters": FilterGroups{{""}}},
+	} {
+		require.NotNil(t, checkQuery(params), "should reject %v", params)
+	}
+}
+
+func TestOptionalFilters(t *testing.T) {
+	t.Log("TestOptionalFilters: Check the scored optional filters")
+	require.Equal(t, "brand:apple", OptionalFilter("brand", "apple", 1))
+	require.Equal(t, "brand:apple<score=3>", OptionalFilter("brand", "apple", 3))
+
+	t.Log("TestOptionalFilters: Check the accepted forms")
+	for _, params := range []Map{
+		{"optionalFilters": "brand:apple"},
+		{"optionalFilters": []string{"brand:apple<score=2>", "category:phone"}},
+		{"optionalFilters": [][]string{{"brand:apple<score=3>", "brand:samsung<score=2>"}, {"category:phone"}}},
+		{"optionalFilters": FilterGroups{{OptionalFilter("brand", "apple", 0)}}},
+	} {
+		require.Nil(t, checkQuery(params), "should accept %v", params)
+	}
+
+	t.Log("TestOptionalFilters: Check the rejected forms")
+	for _, params := range []Map{
+		{"optionalFilters": 42},
+		{"optionalFilters": []string{"apple"}},
+		{"optionalFilters": []string{"brand:apple<score=high>"}},
+		{"optionalFilters": []string{"brand:apple<score=-1>"}},
+		{"optionalFilters": []string{"brand:apple<score=2"}},
+	} {
+		require.NotNil(t, checkQuery(params), "should reject %v", params)
+	}
+}
@@ -0,0 +1,192 @@
+package algoliasearch
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Metrics reported by the FreshnessProbe.
+const (
+	FreshnessMetric       = "freshness_ms"
+	FreshnessErrorsMetric = "freshness_errors"
+)
+
+// FreshnessProbe periodically measures the time it takes for a record to
+// become searchable once written to an index. Each measurement writes a
+// canary record, waits for its task to be published, searches for it until
+// it is found and finally deletes it. The latency, in milliseconds, is
+// reported to the MetricsHook as the `freshness_ms` metric while failed
+// measurements are reported as the `freshness_errors` metric. Both are tagged
+// with the index name.
+type FreshnessProbe struct {
+	client    Client
+	indexName string
+	hook      MetricsHook
+	interval  time.Duration
+
+	// Timeout is the maximum time a single measurement may take before being
+	// considered as failed. Defaults to 1 minute.
+	Timeout time.Duration
+
+	mu   sync.Mutex
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewFreshnessProbe instantiates a new FreshnessProbe measuring the
+// freshness of the `indexName` index every `interval` once started. As
+// canary records are written to the index, the API key of the `client`
+// needs the `addObject`, `deleteObject` and `search` ACLs. A non-nil error is
+// returned if the `hook` is nil or if the `interval` is not positive.
+func NewFreshnessProbe(client Client, indexName string, hook MetricsHook, interval time.Duration) (*FreshnessProbe, error) {
+	if hook == nil {
+		return nil, fmt.Errorf("Cannot create freshness probe: the metrics hook should not be nil")
+	}
+
+	if interval <= 0 {
+		return nil, fmt.Errorf("Cannot create freshness probe: the interval should be positive, got %s", interval)
+	}
+
+	return &FreshnessProbe{
+		client:    client,
+		indexName: indexName,
+		hook:      hook,
+		interval:  interval,
+		Timeout:   time.Minute,
+	}, nil
+}
+
+// Start starts measuring the freshness of the index in the background, once
+// immediately and then at every interval, until Stop is called. Start and
+// Stop are safe for concurrent use.
+func (p *FreshnessProbe) Start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.stop != nil {
+		return
+	}
+
+	p.stop = make(chan struct{})
+	p.wg.Add(1)
+
+	go func(stop chan struct{}) {
+		defer p.wg.Done()
+
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			p.Measure()
+
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}(p.stop)
+}
+
+// Stop stops the measurements started by Start and waits for the ongoing
+// one, if any, to complete.
+func (p *FreshnessProbe) Stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.stop == nil {
+		return
+	}
+
+	close(p.stop)
+	p.wg.Wait()
+	p.stop = nil
+}
+
+// Measure performs a single measurement and reports it to the MetricsHook.
+// The measured latency is also returned, along with a non-nil error if the
+// measurement failed.
+func (p *FreshnessProbe) Measure() (latency time.Duration, err error) {
+	tags := map[string]string{"index": p.indexName}
+
+	if latency, err = p.measure(); err != nil {
+		p.hook.Observe(FreshnessErrorsMetric, 1, tags)
+		return
+	}
+
+	p.hook.Observe(FreshnessMetric, float64(latency)/float64(time.Millisecond), tags)
+	return
+}
+
+func (p *FreshnessProbe) measure() (latency time.Duration, err error) {
+	index := p.client.InitIndex(p.indexName)
+	objectID := "freshness-probe-" + NewObjectID(strconv.FormatInt(time.Now().UnixNano(), 10))
+	start := time.Now()
+
+	res, err := index.AddObject(Object{
+		"objectID":       objectID,
+		"freshnessProbe": start.Unix(),
+	})
+	if err != nil {
+		return
+	}
+
+	// The canary record is deleted whatever the outcome of the measurement.
+	// Waiting for the deletion ensures measurements never overlap.
+	defer func() {
+		if res, e := index.DeleteObject(objectID); e == nil {
+			p.poll(time.Now(), taskPublished(index, res.TaskID))
+		}
+	}()
+
+	if err = p.poll(start, taskPublished(index, res.TaskID)); err != nil {
+		err = fmt.Errorf("Task %d of canary record %s: %s", res.TaskID, objectID, err)
+		return
+	}
+
+	params := Map{
+		"filters":              fmt.Sprintf("objectID:%q", objectID),
+		"attributesToRetrieve": []string{"objectID"},
+		"hitsPerPage":          1,
+		"analytics":            false,
+	}
+
+	err = p.poll(start, func() (bool, error) {
+		queryRes, err := index.Search("", params)
+		return queryRes.NbHits > 0, err
+	})
+	if err != nil {
+		err = fmt.Errorf("Canary record %s not searchable: %s", objectID, err)
+		return
+	}
+
+	latency = time.Since(start)
+	return
+}
+
+// poll calls `done` every 100ms until it returns `true` or a non-nil error,
+// failing once the Timeout of the measurement started at `start` elapsed.
+func (p *FreshnessProbe) poll(start time.Time, done func() (bool, error)) error {
+	for {
+		if ok, err := done(); ok || err != nil {
+			return err
+		}
+
+		if time.Since(start) > p.Timeout {
+			return fmt.Errorf("timed out after %s", p.Timeout)
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// taskPublished returns a function reporting whether the task `taskID` of the
+// `index` is published, to be used with FreshnessProbe.poll.
+func taskPublished(index Index, taskID int) func() (bool, error) {
+	return func() (bool, error) {
+		res, err := index.GetStatus(taskID)
+		return res.Status == "published", err
+	}
+}
@@ -0,0 +1,144 @@
+package algoliasearch
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFreshnessProbe(t *testing.T) {
+	t.Parallel()
+	c, i := initClientAndIndex(t, "TestFreshnessProbe")
+	addOneObject(t, i)
+
+	var mutex sync.Mutex
+	metrics := make(map[string]float64)
+	hook := MetricsHookFunc(func(metric string, value float64, tags map[string]string) {
+		require.Equal(t, "TestFreshnessProbe", tags["index"])
+		mutex.Lock()
+		metrics[metric] += value
+		mutex.Unlock()
+	})
+
+	t.Log("TestFreshnessProbe: Measure the freshness of the index")
+	{
+		probe, err := NewFreshnessProbe(c, "TestFreshnessProbe", hook, time.Hour)
+		require.Nil(t, err)
+		latency, err := probe.Measure()
+		require.Nil(t, err, "should measure the freshness without error")
+		require.True(t, latency > 0)
+		require.Contains(t, metrics, FreshnessMetric)
+		require.NotContains(t, metrics, FreshnessErrorsMetric)
+	}
+
+	t.Log("TestFreshnessProbe: Check that the canary record is deleted")
+	{
+		res, err := i.Search("", nil)
+		require.Nil(t, err)
+		require.Equal(t, 1, res.NbHits, "should only contain the initial record")
+	}
+}
+
+func TestFreshnessProbeTimeout(t *testing.T) {
+	t.Log("TestFreshnessProbeTimeout: Start a server never publishing the tasks")
+	var deleted int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.Contains(r.URL.Path, "/task/"):
+			w.Write([]byte(`{"status":"notPublished","pendingTask":true}`))
+		case r.Method == "DELETE":
+			atomic.AddInt32(&deleted, 1)
+			w.Write([]byte(`{"taskID":2}`))
+		default:
+			w.Write([]byte(`{"taskID":1,"objectID":"canary"}`))
+		}
+	}))
+	defer server.Close()
+	c := &client{transport: newTestTransport(server)}
+
+	var failures int32
+	hook := MetricsHookFunc(func(metric string, value float64, tags map[string]string) {
+		if metric == FreshnessErrorsMetric {
+			atomic.AddInt32(&failures, 1)
+		}
+	})
+
+	t.Log("TestFreshnessProbeTimeout: Check that the interval is validated")
+	{
+		_, err := NewFreshnessProbe(c, "products", hook, 0)
+		require.NotNil(t, err, "should reject a zero interval")
+
+		_, err = NewFreshnessProbe(c, "products", nil, time.Hour)
+		require.NotNil(t, err, "should reject a nil hook")
+	}
+
+	t.Log("TestFreshnessProbeTimeout: Check that the wait for the task is bounded by the timeout")
+	{
+		probe, err := NewFreshnessProbe(c, "products", hook, time.Hour)
+		require.Nil(t, err)
+		probe.Timeout = 200 * time.Millisecond
+
+		start := time.Now()
+		_, err = probe.Measure()
+		require.NotNil(t, err, "should time out")
+		require.True(t, time.Since(start) < 2*time.Second, "should not wait for the task forever")
+		require.Equal(t, int32(1), atomic.LoadInt32(&failures))
+		require.Equal(t, int32(1), atomic.LoadInt32(&deleted), "should delete the canary record")
+	}
+}
+
+func TestFreshnessProbeStartStop(t *testing.T) {
+	t.Log("TestFreshnessProbeStartStop: Start a server publishing the tasks and finding the canary records")
+	var added int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.Contains(r.URL.Path, "/task/"):
+			w.Write([]byte(`{"status":"published","pendingTask":false}`))
+		case strings.HasSuffix(r.URL.Path, "/query"):
+			w.Write([]byte(`{"hits":[],"nbHits":1}`))
+		case r.Method == "DELETE":
+			w.Write([]byte(`{"taskID":2}`))
+		default:
+			atomic.AddInt32(&added, 1)
+			w.Write([]byte(`{"taskID":1,"objectID":"canary"}`))
+		}
+	}))
+	defer server.Close()
+	c := &client{transport: newTestTransport(server)}
+
+	hook := MetricsHookFunc(func(metric string, value float64, tags map[string]string) {})
+	probe, err := NewFreshnessProbe(c, "products", hook, time.Hour)
+	require.Nil(t, err)
+
+	t.Log("TestFreshnessProbeStartStop: Start and stop the probe concurrently")
+	{
+		var wg sync.WaitGroup
+		for n := 0; n < 10; n++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				probe.Start()
+			}()
+			go func() {
+				defer wg.Done()
+				probe.Stop()
+			}()
+		}
+		wg.Wait()
+		probe.Stop()
+	}
+
+	t.Log("TestFreshnessProbeStartStop: Check that the probe can be restarted once stopped")
+	{
+		before := atomic.LoadInt32(&added)
+		probe.Start()
+		probe.Stop()
+		require.Equal(t, before+1, atomic.LoadInt32(&added), "should measure once when started")
+	}
+}
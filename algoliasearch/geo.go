@@ -0,0 +1,244 @@
+package algoliasearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// LatLng is a geo location, to be used as the value of the `aroundLatLng`
+// search parameter.
+type LatLng struct {
+	Lat float64
+	Lng float64
+}
+
+// Validate returns a non-nil error if the latitude is not within [-90, 90]
+// or if the longitude is not within [-180, 180].
+func (l LatLng) Validate() error {
+	if l.Lat < -90 || l.Lat > 90 {
+		return fmt.Errorf("Invalid latitude %v: should be between -90 and 90", l.Lat)
+	}
+	if l.Lng < -180 || l.Lng > 180 {
+		return fmt.Errorf("Invalid longitude %v: should be between -180 and 180", l.Lng)
+	}
+	return nil
+}
+
+func (l LatLng) encodeParam() string {
+	return formatCoordinates(l.Lat, l.Lng)
+}
+
+func (l LatLng) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.encodeParam())
+}
+
+// BoundingBox is a rectangular area delimited by two of its opposite
+// corners, to be used as the value of the `insideBoundingBox` search
+// parameter. Several bounding boxes can be given at once with a
+// `[]BoundingBox`.
+type BoundingBox struct {
+	P1 LatLng
+	P2 LatLng
+}
+
+// Validate returns a non-nil error if one of the corners is invalid.
+func (b BoundingBox) Validate() error {
+	if err := b.P1.Validate(); err != nil {
+		return fmt.Errorf("Invalid bounding box: %s", err)
+	}
+	if err := b.P2.Validate(); err != nil {
+		return fmt.Errorf("Invalid bounding box: %s", err)
+	}
+	return nil
+}
+
+func (b BoundingBox) encodeParam() string {
+	return formatCoordinates(b.P1.Lat, b.P1.Lng, b.P2.Lat, b.P2.Lng)
+}
+
+func (b BoundingBox) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]float64{b.P1.Lat, b.P1.Lng, b.P2.Lat, b.P2.Lng})
+}
+
+// Polygon is an area delimited by at least 3 points, to be used as the value
+// of the `insidePolygon` search parameter. Several polygons can be given at
+// once with a `[]Polygon`.
+type Polygon []LatLng
+
+// Validate returns a non-nil error if the polygon has less than 3 points or
+// if one of them is invalid.
+func (p Polygon) Validate() error {
+	if len(p) < 3 {
+		return fmt.Errorf("Invalid polygon: should have at least 3 points (got %d)", len(p))
+	}
+	for _, point := range p {
+		if err := point.Validate(); err != nil {
+			return fmt.Errorf("Invalid polygon: %s", err)
+		}
+	}
+	return nil
+}
+
+func (p Polygon) coordinates() []float64 {
+	coordinates := make([]float64, 0, 2*len(p))
+	for _, point := range p {
+		coordinates = append(coordinates, point.Lat, point.Lng)
+	}
+	return coordinates
+}
+
+func (p Polygon) encodeParam() string {
+	return formatCoordinates(p.coordinates()...)
+}
+
+func (p Polygon) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.coordinates())
+}
+
+// formatCoordinates returns the given coordinates as a comma-separated
+// string.
+func formatCoordinates(coordinates ...float64) string {
+	s := make([]string, len(coordinates))
+	for i, c := range coordinates {
+		s[i] = strconv.FormatFloat(c, 'f', -1, 64)
+	}
+	return strings.Join(s, ",")
+}
+
+// checkGeoParam checks that the value `v` of the `k` geo search parameter
+// (`aroundLatLng`, `insideBoundingBox` or `insidePolygon`) has a valid type
+// and that the location, bounding boxes or polygons it describes are valid,
+// whether they are typed or given as raw coordinates.
+func checkGeoParam(k string, v interface{}) error {
+	switch v := v.(type) {
+	case LatLng:
+		if k == "aroundLatLng" {
+			return v.Validate()
+		}
+	case BoundingBox:
+		if k == "insideBoundingBox" {
+			return v.Validate()
+		}
+	case []BoundingBox:
+		if k == "insideBoundingBox" {
+			for _, b := range v {
+				if err := b.Validate(); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	case Polygon:
+		if k == "insidePolygon" {
+			return v.Validate()
+		}
+	case []Polygon:
+		if k == "insidePolygon" {
+			for _, p := range v {
+				if err := p.Validate(); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	case string:
+		if k == "aroundLatLng" {
+			return nil
+		}
+		groups, err := parseCoordinates(k, v)
+		if err != nil {
+			return err
+		}
+		return checkCoordinates(k, groups)
+	case [][]float64:
+		if k != "aroundLatLng" {
+			return checkCoordinates(k, v)
+		}
+	}
+
+	switch k {
+	case "aroundLatLng":
+		return invalidType(k, "string or LatLng")
+	case "insideBoundingBox":
+		return invalidType(k, "string, [][]float64, BoundingBox or []BoundingBox")
+	default:
+		return invalidType(k, "string, [][]float64, Polygon or []Polygon")
+	}
+}
+
+// parseCoordinates parses the string value `v` of the `k` geo search
+// parameter (`insideBoundingBox` or `insidePolygon`), given either as a
+// comma-separated list of coordinates or as a JSON array of such lists, into
+// groups of coordinates, one per bounding box or polygon.
+func parseCoordinates(k, v string) ([][]float64, error) {
+	v = strings.TrimSpace(v)
+
+	if strings.HasPrefix(v, "[") {
+		var groups [][]float64
+		if err := json.Unmarshal([]byte(v), &groups); err != nil {
+			return nil, fmt.Errorf("`%s` should be a JSON array of arrays of coordinates, got %q", k, v)
+		}
+		return groups, nil
+	}
+
+	var coordinates []float64
+	for _, c := range strings.Split(v, ",") {
+		f, err := strconv.ParseFloat(strings.TrimSpace(c), 64)
+		if err != nil {
+			return nil, fmt.Errorf("`%s` should be a comma-separated list of coordinates, got %q", k, v)
+		}
+		coordinates = append(coordinates, f)
+	}
+
+	return splitCoordinates(k, coordinates), nil
+}
+
+// splitCoordinates splits the flat list of `coordinates` of the `k` geo
+// search parameter into groups of coordinates: several bounding boxes can
+// be given as a single list of coordinates but only one polygon.
+func splitCoordinates(k string, coordinates []float64) [][]float64 {
+	if k != "insideBoundingBox" || len(coordinates)%4 != 0 {
+		return [][]float64{coordinates}
+	}
+
+	var groups [][]float64
+	for start := 0; start < len(coordinates); start += 4 {
+		groups = append(groups, coordinates[start:start+4])
+	}
+	return groups
+}
+
+// checkCoordinates checks that each group of coordinates of the `k` geo
+// search parameter is a valid bounding box or polygon.
+func checkCoordinates(k string, groups [][]float64) error {
+	if len(groups) == 0 {
+		return fmt.Errorf("`%s` should not be empty", k)
+	}
+
+	for _, c := range groups {
+		if k == "insideBoundingBox" {
+			if len(c) != 4 {
+				return fmt.Errorf("Invalid bounding box: should have 4 coordinates (got %d)", len(c))
+			}
+			if err := (BoundingBox{LatLng{c[0], c[1]}, LatLng{c[2], c[3]}}).Validate(); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if len(c)%2 != 0 {
+			return fmt.Errorf("Invalid polygon: should have an even number of coordinates (got %d)", len(c))
+		}
+		polygon := make(Polygon, 0, len(c)/2)
+		for n := 0; n < len(c); n += 2 {
+			polygon = append(polygon, LatLng{c[n], c[n+1]})
+		}
+		if err := polygon.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package algoliasearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeoTypes(t *testing.T) {
+	t.Log("TestGeoTypes: Check the encoding of the geo types")
+	{
+		require.Equal(t, "aroundLatLng=48.85%2C2.35", encodeMap(Map{"aroundLatLng": LatLng{48.85, 2.35}}))
+		require.Equal(t, "insideBoundingBox=1%2C2%2C3%2C4", encodeMap(Map{
+			"insideBoundingBox": BoundingBox{LatLng{1, 2}, LatLng{3, 4}},
+		}))
+		require.Equal(t, "insideBoundingBox=%5B%5B1%2C2%2C3%2C4%5D%2C%5B5%2C6%2C7%2C8%5D%5D", encodeMap(Map{
+			"insideBoundingBox": []BoundingBox{{LatLng{1, 2}, LatLng{3, 4}}, {LatLng{5, 6}, LatLng{7, 8}}},
+		}))
+		require.Equal(t, "insidePolygon=1%2C2%2C3%2C4%2C5%2C6", encodeMap(Map{
+			"insidePolygon": Polygon{{1, 2}, {3, 4}, {5, 6}},
+		}))
+		require.Equal(t, "insidePolygon=%5B%5B1%2C2%2C3%2C4%2C5%2C6%5D%5D", encodeMap(Map{
+			"insidePolygon": []Polygon{{{1, 2}, {3, 4}, {5, 6}}},
+		}))
+	}
+
+	t.Log("TestGeoTypes: Check the validation of the geo types")
+	{
+		require.Nil(t, LatLng{-90, 180}.Validate())
+		require.NotNil(t, LatLng{-91, 0}.Validate())
+		require.NotNil(t, LatLng{0, 181}.Validate())
+		require.NotNil(t, BoundingBox{LatLng{1, 2}, LatLng{100, 4}}.Validate())
+		require.NotNil(t, Polygon{{1, 2}, {3, 4}}.Validate())
+		require.NotNil(t, checkQuery(Map{"aroundLatLng": BoundingBox{}}))
+		require.NotNil(t, checkQuery(Map{"insidePolygon": []BoundingBox{}}))
+	}
+}
+
+func TestGeoCoordinates(t *testing.T) {
+	t.Log("TestGeoCoordinates: Check the valid coordinates")
+	{
+		for _, params := range []Map{
+			{"insideBoundingBox": "1.0,2.0,3.0,4.0"},
+			{"insideBoundingBox": "1.0,2.0,3.0,4.0,5.0,6.0,7.0,8.0"},
+			{"insideBoundingBox": [][]float64{{1, 2, 3, 4}, {5, 6, 7, 8}}},
+			{"insidePolygon": "1.0, 2.0, 3.0, 4.0, 5.0, 6.0"},
+			{"insidePolygon": "[[1.0,2.0,3.0,4.0,5.0,6.0],[1.0,2.0,3.0,4.0,5.0,6.0,7.0,8.0]]"},
+			{"insidePolygon": [][]float64{{1, 2, 3, 4, 5, 6}, {1, 2, 3, 4, 5, 6, 7, 8}}},
+		} {
+			require.Nil(t, checkQuery(params), "%#v should be valid", params)
+		}
+	}
+
+	t.Log("TestGeoCoordinates: Check the invalid coordinates")
+	{
+		for _, params := range []Map{
+			{"insideBoundingBox": "1.0,2.0,3.0"},
+			{"insideBoundingBox": "1.0,2.0,3.0,north"},
+			{"insideBoundingBox": [][]float64{{1, 2, 3, 4}, {5, 6, 7}}},
+			{"insideBoundingBox": [][]float64{{1, 2, 300, 4}}},
+			{"insideBoundingBox": [][]float64{}},
+			{"insidePolygon": "1.0,2.0,3.0,4.0"},
+			{"insidePolygon": "1.0,2.0,3.0,4.0,5.0,6.0,7.0"},
+			{"insidePolygon": "[[1.0,2.0,3.0,4.0,5.0,6.0]"},
+			{"insidePolygon": [][]float64{{1, 2, 3, 4, 5, 6}, {1, 2, 3, 4}}},
+		} {
+			require.NotNil(t, checkQuery(params), "%#v should be invalid", params)
+		}
+
+		err := checkQuery(Map{"insidePolygon": [][]float64{{1, 2, 3, 4, 5}}})
+		require.Equal(t, "Invalid polygon: should have an even number of coordinates (got 5)", err.Error())
+	}
+}
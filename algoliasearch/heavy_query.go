@@ -0,0 +1,121 @@
+package algoliasearch
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// heavyQueryHitsPerPage is the number of hits per page beyond which
+// IsHeavyQuery considers a query as heavy.
+const heavyQueryHitsPerPage = 100
+
+// HeavyQueryPredicate reports whether the search query `query` with the
+// given `params` is expensive enough to be routed to a replica (see
+// Index.RouteHeavyQueriesTo).
+type HeavyQueryPredicate func(query string, params Map) bool
+
+// IsHeavyQuery is the default HeavyQueryPredicate. It considers as heavy the
+// queries retrieving all the facets (`facets` set to `*`) or more than 100
+// hits at once (`hitsPerPage` or `length`), typically used by exports and
+// analytical tools.
+func IsHeavyQuery(query string, params Map) bool {
+	switch facets := params["facets"].(type) {
+	case string:
+		if facets == "*" {
+			return true
+		}
+	case []string:
+		for _, f := range facets {
+			if f == "*" {
+				return true
+			}
+		}
+	}
+
+	for _, k := range []string{"hitsPerPage", "length"} {
+		if n, ok := params[k].(int); ok && n > heavyQueryHitsPerPage {
+			return true
+		}
+	}
+
+	return false
+}
+
+// heavyQueryRouting describes the replica the heavy queries of an index are
+// routed to.
+type heavyQueryRouting struct {
+	replica string
+	route   string
+	isHeavy HeavyQueryPredicate
+}
+
+// searchRoute returns the route of the index to send the given search
+// query to: the replica one if the query is heavy, `i.route` otherwise.
+func (i *index) searchRoute(query string, params Map) string {
+	i.heavyQueryMu.Lock()
+	r := i.heavyQueryRouting
+	i.heavyQueryMu.Unlock()
+
+	if r == nil || !r.isHeavy(query, params) {
+		return i.route
+	}
+	return r.route
+}
+
+func (i *index) RouteHeavyQueriesTo(replica string, isHeavy HeavyQueryPredicate) error {
+	return i.RouteHeavyQueriesToWithRequestOptions(replica, isHeavy, nil)
+}
+
+func (i *index) RouteHeavyQueriesToWithRequestOptions(replica string, isHeavy HeavyQueryPredicate, opts *RequestOptions) error {
+	if replica == "" {
+		i.setHeavyQueryRouting(nil)
+		return nil
+	}
+
+	indexes, err := i.client.ListAllIndexesWithRequestOptions(opts)
+	if err != nil {
+		return err
+	}
+
+	// The names of the listed indexes are not prefixed
+	name := strings.TrimPrefix(i.name, i.client.indexPrefix)
+
+	var primaryRes, replicaRes *IndexRes
+	for n := range indexes {
+		switch indexes[n].Name {
+		case name:
+			primaryRes = &indexes[n]
+		case replica:
+			replicaRes = &indexes[n]
+		}
+	}
+
+	switch {
+	case primaryRes == nil:
+		return IndexNotFoundErr
+	case replicaRes == nil:
+		return fmt.Errorf("Cannot route heavy queries to %s: %s", replica, IndexNotFoundErr)
+	case replicaRes.Primary != name:
+		return fmt.Errorf("Cannot route heavy queries to %s: not a replica of %s", replica, name)
+	}
+
+	if isHeavy == nil {
+		isHeavy = IsHeavyQuery
+	}
+
+	i.setHeavyQueryRouting(&heavyQueryRouting{
+		replica: replica,
+		route:   "/1/indexes/" + url.QueryEscape(i.client.prefixedIndexName(replica)),
+		isHeavy: isHeavy,
+	})
+	return nil
+}
+
+// setHeavyQueryRouting replaces the routing of the heavy queries, which may
+// be read concurrently by the searches.
+func (i *index) setHeavyQueryRouting(r *heavyQueryRouting) {
+	i.heavyQueryMu.Lock()
+	i.heavyQueryRouting = r
+	i.heavyQueryMu.Unlock()
+}
@@ -0,0 +1,83 @@
+package algoliasearch
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeavyQueryRouting(t *testing.T) {
+	t.Log("TestHeavyQueryRouting: Check the default heavy query predicate")
+	{
+		require.True(t, IsHeavyQuery("", Map{"facets": "*"}))
+		require.True(t, IsHeavyQuery("", Map{"facets": []string{"brand", "*"}}))
+		require.True(t, IsHeavyQuery("", Map{"hitsPerPage": 1000}))
+		require.True(t, IsHeavyQuery("", Map{"length": 500}))
+		require.False(t, IsHeavyQuery("phone", Map{"facets": []string{"brand"}, "hitsPerPage": 20}))
+		require.False(t, IsHeavyQuery("phone", nil))
+	}
+
+	t.Log("TestHeavyQueryRouting: Check the routing of the queries")
+	{
+		i := NewIndex("products", nil).(*index)
+		require.Equal(t, "/1/indexes/products", i.searchRoute("", Map{"facets": "*"}))
+
+		i.setHeavyQueryRouting(&heavyQueryRouting{
+			replica: "products_export",
+			route:   "/1/indexes/products_export",
+			isHeavy: IsHeavyQuery,
+		})
+		require.Equal(t, "/1/indexes/products_export", i.searchRoute("", Map{"facets": "*"}))
+		require.Equal(t, "/1/indexes/products", i.searchRoute("phone", nil))
+
+		require.Nil(t, i.RouteHeavyQueriesTo("", nil))
+		require.Equal(t, "/1/indexes/products", i.searchRoute("", Map{"facets": "*"}))
+	}
+}
+
+func TestRouteHeavyQueriesTo(t *testing.T) {
+	t.Log("TestRouteHeavyQueriesTo: Start a server listing a replica still being synchronized")
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/1/indexes" {
+			w.Write([]byte(`{"items":[
+				{"name":"products","entries":1000},
+				{"name":"products_export","primary":"products","entries":998},
+				{"name":"articles","entries":1000}
+			],"nbPages":1}`))
+			return
+		}
+		w.Write([]byte(`{"hits":[]}`))
+	}))
+	defer server.Close()
+	i := (&client{transport: newTestTransport(server)}).InitIndex("products").(*index)
+
+	t.Log("TestRouteHeavyQueriesTo: Check the routing decisions")
+	{
+		require.Nil(t, i.RouteHeavyQueriesTo("products_export", nil))
+		require.Equal(t, "/1/indexes/products_export", i.searchRoute("", Map{"facets": "*"}))
+		require.Equal(t, "/1/indexes/products", i.searchRoute("phone", nil))
+
+		require.NotNil(t, i.RouteHeavyQueriesTo("articles", nil), "should reject an index which is not a replica")
+		require.NotNil(t, i.RouteHeavyQueriesTo("unknown", nil), "should reject an unknown index")
+		require.Equal(t, "/1/indexes/products_export", i.searchRoute("", Map{"facets": "*"}), "should keep the previous routing")
+	}
+
+	t.Log("TestRouteHeavyQueriesTo: Check that the routing can change while searching")
+	{
+		var wg sync.WaitGroup
+		for n := 0; n < 4; n++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := i.Search("", Map{"facets": "*"})
+				require.Nil(t, err)
+			}()
+		}
+		require.Nil(t, i.RouteHeavyQueriesTo("", nil))
+		wg.Wait()
+		require.Equal(t, "/1/indexes/products", i.searchRoute("", Map{"facets": "*"}))
+	}
+}
@@ -0,0 +1,197 @@
+package algoliasearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SnippetAttribute is an attribute to snippet, to be used as an element of
+// the `attributesToSnippet` parameter. `Words` is the maximum number of words
+// of the snippet; the engine default (10) is used if it is zero.
+type SnippetAttribute struct {
+	Attribute string
+	Words     int
+}
+
+// String returns the `attribute:words` form of the snippet attribute, as
+// expected by the API.
+func (a SnippetAttribute) String() string {
+	if a.Words == 0 {
+		return a.Attribute
+	}
+	return a.Attribute + ":" + strconv.Itoa(a.Words)
+}
+
+func (a SnippetAttribute) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.String())
+}
+
+// checkHighlightAttributes checks that the value `v` of the
+// `attributesToHighlight` or `attributesToSnippet` (depending on `k`)
+// parameter is either a comma-separated string, a `[]string` or, for
+// `attributesToSnippet` only, a `[]SnippetAttribute`, and that each listed
+// attribute is valid. The `*` wildcard is accepted as well.
+func checkHighlightAttributes(k string, v interface{}) error {
+	var attributes []string
+
+	switch v := v.(type) {
+	case string:
+		if v == "" {
+			return nil
+		}
+		attributes = strings.Split(v, ",")
+	case []string:
+		attributes = v
+	case []SnippetAttribute:
+		if k != "attributesToSnippet" {
+			return invalidType(k, "string or []string")
+		}
+		for _, a := range v {
+			attributes = append(attributes, a.String())
+		}
+	default:
+		if k == "attributesToSnippet" {
+			return invalidType(k, "string, []string or []SnippetAttribute")
+		}
+		return invalidType(k, "string or []string")
+	}
+
+	for _, attr := range attributes {
+		attr = strings.TrimSpace(attr)
+		if attr == "" {
+			return fmt.Errorf("`%s` should not contain empty attributes", k)
+		}
+
+		if k != "attributesToSnippet" {
+			continue
+		}
+
+		if n := strings.LastIndex(attr, ":"); n != -1 {
+			words, err := strconv.Atoi(attr[n+1:])
+			if err != nil || words <= 0 || n == 0 {
+				return fmt.Errorf("`%s` should only contain `attribute` or `attribute:words` elements with a positive number of words, got %q", k, attr)
+			}
+		}
+	}
+
+	return nil
+}
+
+// HighlightedValue returns the highlighted value of the given `attribute` of
+// the `hit`, as found in its `_highlightResult`. If the attribute was not
+// highlighted (highlighting disabled or attribute not listed in
+// `attributesToHighlight`), its raw value is returned instead so that callers
+// do not have to handle each case. `ok` is `false` if the attribute is not a
+// string or is not present at all.
+func HighlightedValue(hit Map, attribute string) (value string, ok bool) {
+	return resultValue(hit, "_highlightResult", attribute)
+}
+
+// SnippetedValue is the same as HighlightedValue but for the snippeted value
+// of the attribute, as found in the `_snippetResult` of the `hit`.
+func SnippetedValue(hit Map, attribute string) (value string, ok bool) {
+	return resultValue(hit, "_snippetResult", attribute)
+}
+
+func resultValue(hit Map, key, attribute string) (value string, ok bool) {
+	if res, isResult := toHighlightedResult(lookupResult(hit, key, attribute)); isResult {
+		return res.Value, true
+	}
+
+	value, ok = hit[attribute].(string)
+	return
+}
+
+// HighlightedResult is the highlighting (or snippeting) result of a single
+// attribute value of a hit, as found in its `_highlightResult` (or
+// `_snippetResult`).
+type HighlightedResult struct {
+	Value            string   `json:"value"`
+	MatchLevel       string   `json:"matchLevel"`
+	MatchedWords     []string `json:"matchedWords"`
+	FullyHighlighted bool     `json:"fullyHighlighted"`
+}
+
+// GetHighlightResult returns the highlighting result of the given `attribute`
+// of the `hit`. Nested attributes are specified using the dot notation (e.g.
+// `author.name`). `ok` is `false` if the attribute was not highlighted or if
+// it is an array, in which case GetHighlightResults should be used.
+func GetHighlightResult(hit Map, attribute string) (res HighlightedResult, ok bool) {
+	return toHighlightedResult(lookupResult(hit, "_highlightResult", attribute))
+}
+
+// GetHighlightResults is the same as GetHighlightResult but for attributes
+// whose value is an array: one result is returned per element of the array.
+func GetHighlightResults(hit Map, attribute string) (res []HighlightedResult, ok bool) {
+	return toHighlightedResults(lookupResult(hit, "_highlightResult", attribute))
+}
+
+// GetSnippetResult is the same as GetHighlightResult but for the snippeting
+// result of the attribute, as found in the `_snippetResult` of the `hit`.
+func GetSnippetResult(hit Map, attribute string) (res HighlightedResult, ok bool) {
+	return toHighlightedResult(lookupResult(hit, "_snippetResult", attribute))
+}
+
+// GetSnippetResults is the same as GetHighlightResults but for the
+// snippeting results of the attribute.
+func GetSnippetResults(hit Map, attribute string) (res []HighlightedResult, ok bool) {
+	return toHighlightedResults(lookupResult(hit, "_snippetResult", attribute))
+}
+
+// lookupResult returns the raw highlighting or snippeting result (depending
+// on `key`) of the `attribute` of the `hit`, walking through nested objects.
+func lookupResult(hit Map, key, attribute string) interface{} {
+	v := hit[key]
+	for _, k := range strings.Split(attribute, ".") {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = m[k]
+	}
+	return v
+}
+
+func toHighlightedResult(v interface{}) (res HighlightedResult, ok bool) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	if res.Value, ok = m["value"].(string); !ok {
+		return
+	}
+	res.MatchLevel, _ = m["matchLevel"].(string)
+	res.FullyHighlighted, _ = m["fullyHighlighted"].(bool)
+
+	switch words := m["matchedWords"].(type) {
+	case []string:
+		res.MatchedWords = words
+	case []interface{}:
+		for _, w := range words {
+			if s, isString := w.(string); isString {
+				res.MatchedWords = append(res.MatchedWords, s)
+			}
+		}
+	}
+
+	return
+}
+
+func toHighlightedResults(v interface{}) (res []HighlightedResult, ok bool) {
+	values, ok := v.([]interface{})
+	if !ok {
+		return
+	}
+
+	res = make([]HighlightedResult, len(values))
+	for i, value := range values {
+		if res[i], ok = toHighlightedResult(value); !ok {
+			return nil, false
+		}
+	}
+
+	return
+}
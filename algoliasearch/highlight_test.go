@@ -0,0 +1,146 @@
+package algoliasearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHighlightParameters(t *testing.T) {
+	t.Log("TestHighlightParameters: Check the accepted forms")
+	for _, params := range []Map{
+		{"attributesToHighlight": "*"},
+		{"attributesToHighlight": "title, description"},
+		{"attributesToHighlight": []string{"title", "description"}},
+		{"attributesToSnippet": "title:10,description"},
+		{"attributesToSnippet": []string{"title:10", "description"}},
+		{"attributesToSnippet": []SnippetAttribute{{"title", 10}, {"description", 0}}},
+	} {
+		require.Nil(t, checkQuery(params), "should accept %v", params)
+	}
+
+	t.Log("TestHighlightParameters: Check the rejected forms")
+	for _, params := range []Map{
+		{"attributesToHighlight": 42},
+		{"attributesToHighlight": []SnippetAttribute{{"title", 10}}},
+		{"attributesToHighlight": "title,,description"},
+		{"attributesToSnippet": "title:ten"},
+		{"attributesToSnippet": []string{"title:0"}},
+		{"attributesToSnippet": []string{":10"}},
+	} {
+		require.NotNil(t, checkQuery(params), "should reject %v", params)
+	}
+
+	t.Log("TestHighlightParameters: Check the encoding of typed snippet attributes")
+	{
+		params := Map{"attributesToSnippet": []SnippetAttribute{{"title", 10}, {"description", 0}}}
+		require.Equal(t, "attributesToSnippet=%5B%22title%3A10%22%2C%22description%22%5D", encodeMap(params))
+	}
+}
+
+func TestHighlightedValue(t *testing.T) {
+	hit := Map{
+		"title":       "Algolia search",
+		"description": "Hosted search API",
+		"price":       42,
+		"_highlightResult": map[string]interface{}{
+			"title": map[string]interface{}{
+				"value":      "<em>Algolia</em> search",
+				"matchLevel": "full",
+			},
+		},
+		"_snippetResult": map[string]interface{}{
+			"description": map[string]interface{}{
+				"value":      "Hosted <em>search</em>…",
+				"matchLevel": "partial",
+			},
+		},
+	}
+
+	t.Log("TestHighlightedValue: Check highlighted and snippeted attributes")
+	{
+		value, ok := HighlightedValue(hit, "title")
+		require.True(t, ok)
+		require.Equal(t, "<em>Algolia</em> search", value)
+
+		value, ok = SnippetedValue(hit, "description")
+		require.True(t, ok)
+		require.Equal(t, "Hosted <em>search</em>…", value)
+	}
+
+	t.Log("TestHighlightedValue: Check the fallback on raw values")
+	{
+		value, ok := HighlightedValue(hit, "description")
+		require.True(t, ok)
+		require.Equal(t, "Hosted search API", value)
+
+		value, ok = SnippetedValue(Map{"title": "Algolia search"}, "title")
+		require.True(t, ok)
+		require.Equal(t, "Algolia search", value)
+
+		_, ok = HighlightedValue(hit, "price")
+		require.False(t, ok)
+
+		_, ok = HighlightedValue(hit, "missing")
+		require.False(t, ok)
+	}
+}
+
+func TestHighlightedResult(t *testing.T) {
+	hit := Map{
+		"_highlightResult": map[string]interface{}{
+			"title": map[string]interface{}{
+				"value":            "<em>Algolia</em> search",
+				"matchLevel":       "partial",
+				"matchedWords":     []interface{}{"algolia"},
+				"fullyHighlighted": false,
+			},
+			"author": map[string]interface{}{
+				"name": map[string]interface{}{
+					"value":        "<em>Jane</em>",
+					"matchLevel":   "full",
+					"matchedWords": []interface{}{"jane"},
+				},
+			},
+			"tags": []interface{}{
+				map[string]interface{}{"value": "<em>search</em>", "matchLevel": "full"},
+				map[string]interface{}{"value": "api", "matchLevel": "none"},
+			},
+		},
+	}
+
+	t.Log("TestHighlightedResult: Check typed highlighting results")
+	{
+		res, ok := GetHighlightResult(hit, "title")
+		require.True(t, ok)
+		require.Equal(t, HighlightedResult{
+			Value:        "<em>Algolia</em> search",
+			MatchLevel:   "partial",
+			MatchedWords: []string{"algolia"},
+		}, res)
+
+		res, ok = GetHighlightResult(hit, "author.name")
+		require.True(t, ok)
+		require.Equal(t, "<em>Jane</em>", res.Value)
+
+		results, ok := GetHighlightResults(hit, "tags")
+		require.True(t, ok)
+		require.Len(t, results, 2)
+		require.Equal(t, "none", results[1].MatchLevel)
+	}
+
+	t.Log("TestHighlightedResult: Check missing results")
+	{
+		_, ok := GetHighlightResult(hit, "tags")
+		require.False(t, ok, "should not return arrays as a single result")
+
+		_, ok = GetHighlightResult(hit, "missing")
+		require.False(t, ok)
+
+		_, ok = GetSnippetResult(hit, "title")
+		require.False(t, ok, "should handle hits without _snippetResult")
+
+		_, ok = GetSnippetResults(Map{}, "tags")
+		require.False(t, ok)
+	}
+}
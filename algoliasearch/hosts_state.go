@@ -0,0 +1,147 @@
+package algoliasearch
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultDialTimeout is the initial timeout of the dialer, increased by
+	// one second each time a host fails and reset once a host answers.
+	defaultDialTimeout = time.Second
+
+	// hostDownCooldown is the time during which a host which failed is only
+	// tried after the other ones.
+	hostDownCooldown = time.Minute
+)
+
+// hostsState is the state of the retry strategy (last active hosts, hosts
+// which recently failed, dialer timeout) along with the connection pool. It
+// is shared by all the transports connecting to the same hosts of the same
+// application, so that a host found dead by one client is not probed again
+// by all the others.
+type hostsState struct {
+	mu               sync.Mutex
+	activeReadHost   string
+	activeReadSince  time.Time
+	activeWriteHost  string
+	activeWriteSince time.Time
+	dialTimeout      time.Duration
+	downSince        map[string]time.Time
+
+	// httpClient is the HTTP client, hence the connection pool, shared by
+	// the transports which were not given their own.
+	httpClient *http.Client
+}
+
+// hostsStates is the registry of the states shared by the transports.
+var hostsStates = struct {
+	sync.Mutex
+	m map[string]*hostsState
+}{m: make(map[string]*hostsState)}
+
+// sharedHostsState returns the state shared by the transports connecting to
+// the `hosts` of the `appID` application, creating it if needed.
+func sharedHostsState(appID string, hosts []string, onlyProvidedHosts bool) *hostsState {
+	key := appID + "|" + strings.Join(hosts, ",")
+	if onlyProvidedHosts {
+		key += "|only"
+	}
+
+	hostsStates.Lock()
+	defer hostsStates.Unlock()
+
+	s, ok := hostsStates.m[key]
+	if !ok {
+		s = newHostsState()
+		hostsStates.m[key] = s
+	}
+	return s
+}
+
+// newHostsState instantiates a new hostsState whose HTTP client dials the
+// hosts according to the dialer timeout of the state.
+func newHostsState() *hostsState {
+	s := &hostsState{
+		dialTimeout: defaultDialTimeout,
+		downSince:   make(map[string]time.Time),
+	}
+
+	transport := defaultTransport(defaultDialTimeout)
+	transport.DialContext = s.dialContext
+	s.httpClient = &http.Client{
+		Timeout:   time.Second * 30,
+		Transport: transport,
+	}
+
+	return s
+}
+
+// dialContext connects to the given address with the current dialer timeout.
+func (s *hostsState) dialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	s.mu.Lock()
+	dialTimeout := s.dialTimeout
+	s.mu.Unlock()
+	return defaultDial(dialTimeout).DialContext(ctx, network, addr)
+}
+
+// activeHost returns the last host which answered a request of the given
+// type if it did so in the last `keepAlive` duration.
+func (s *hostsState) activeHost(typeCall int, keepAlive time.Duration) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	host, since := s.activeReadHost, s.activeReadSince
+	if typeCall == write {
+		host, since = s.activeWriteHost, s.activeWriteSince
+	}
+
+	if host == "" || time.Since(since) > keepAlive {
+		return ""
+	}
+	return host
+}
+
+// isDown returns `true` if the `host` failed in the last `hostDownCooldown`
+// duration.
+func (s *hostsState) isDown(host string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	since, ok := s.downSince[host]
+	return ok && time.Since(since) <= hostDownCooldown
+}
+
+// markUp records that the `host` answered a request of the given type.
+func (s *hostsState) markUp(host string, typeCall int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if typeCall == write {
+		s.activeWriteHost, s.activeWriteSince = host, time.Now()
+	} else {
+		s.activeReadHost, s.activeReadSince = host, time.Now()
+	}
+	delete(s.downSince, host)
+	s.dialTimeout = defaultDialTimeout
+}
+
+// markDown records that the `host` failed and gives more time to the next
+// hosts to accept the connection.
+func (s *hostsState) markDown(host string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.activeReadHost == host {
+		s.activeReadHost = ""
+	}
+	if s.activeWriteHost == host {
+		s.activeWriteHost = ""
+	}
+	s.downSince[host] = time.Now()
+	s.dialTimeout += time.Second
+}
@@ -1,17 +1,29 @@
 package algoliasearch
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 )
 
+// batchChunkSize is the maximum number of operations sent in a single batch
+// request by the methods which automatically split their operations into
+// several batches.
+const batchChunkSize = 1000
+
 type index struct {
-	client *client
-	name   string
-	route  string
+	client            *client
+	headers           map[string]string
+	heavyQueryMu      sync.Mutex
+	heavyQueryRouting *heavyQueryRouting
+	name              string
+	route             string
+	scrubber          *scrubber
 }
 
 // NewIndex instantiates a new `Index`. The `name` parameter corresponds to the
@@ -24,13 +36,56 @@ func NewIndex(name string, client *client) Index {
 	}
 }
 
+func (i *index) SetScrubbedAttributes(attributes []string) {
+	i.scrubber = newScrubber(attributes)
+}
+
+func (i *index) SetExtraHeader(key, value string) {
+	if i.headers == nil {
+		i.headers = make(map[string]string)
+	}
+	i.headers[key] = value
+}
+
+// request performs the request through the client, along with the extra
+// headers of the index. The headers given in `opts`, if any, take precedence.
+func (i *index) request(res interface{}, method, path string, body interface{}, typeCall int, opts *RequestOptions) error {
+	if len(i.headers) > 0 {
+		var o RequestOptions
+		if opts != nil {
+			o = *opts
+		}
+
+		o.ExtraHeaders = make(map[string]string)
+		for k, v := range i.headers {
+			o.ExtraHeaders[k] = v
+		}
+		if opts != nil {
+			for k, v := range opts.ExtraHeaders {
+				o.ExtraHeaders[k] = v
+			}
+		}
+
+		opts = &o
+	}
+
+	return i.client.request(res, method, path, body, typeCall, opts)
+}
+
 func (i *index) Delete() (res DeleteTaskRes, err error) {
 	return i.DeleteWithRequestOptions(nil)
 }
 
 func (i *index) DeleteWithRequestOptions(opts *RequestOptions) (res DeleteTaskRes, err error) {
 	path := i.route
-	err = i.client.request(&res, "DELETE", path, nil, write, opts)
+	if i.isDryRun(opts) {
+		err = i.dryRun(DryRunReport{Operation: DryRunDeleteIndex, Method: "DELETE", Path: path}, func() (int, error) {
+			return i.countRecords("", nil, opts)
+		})
+		return
+	}
+
+	err = i.request(&res, "DELETE", path, nil, write, opts)
 	return
 }
 
@@ -40,7 +95,14 @@ func (i *index) Clear() (res UpdateTaskRes, err error) {
 
 func (i *index) ClearWithRequestOptions(opts *RequestOptions) (res UpdateTaskRes, err error) {
 	path := i.route + "/clear"
-	err = i.client.request(&res, "POST", path, nil, write, opts)
+	if i.isDryRun(opts) {
+		err = i.dryRun(DryRunReport{Operation: DryRunClearIndex, Method: "POST", Path: path}, func() (int, error) {
+			return i.countRecords("", nil, opts)
+		})
+		return
+	}
+
+	err = i.request(&res, "POST", path, nil, write, opts)
 	return
 }
 
@@ -62,11 +124,48 @@ func (i *index) GetObjectWithRequestOptions(objectID string, attributes []string
 	}
 
 	path := i.route + "/" + url.QueryEscape(objectID) + "?" + encodeMap(params)
-	err = i.client.request(&object, "GET", path, nil, read, opts)
+	err = i.request(&object, "GET", path, nil, read, opts)
+	i.scrubber.scrub(object)
 	return
 }
 
-func (i *index) getObjects(objectIDs, attributesToRetrieve []string, opts *RequestOptions) (objs []Object, err error) {
+// getObjects retrieves the objects identified by the `objectIDs`, in order,
+// along with the objectIDs of the ones which do not exist, whose position
+// holds a nil Object.
+func (i *index) getObjects(objectIDs, attributesToRetrieve []string, opts *RequestOptions) (objs []Object, missing []string, err error) {
+	objs = make([]Object, 0, len(objectIDs))
+
+	// The API retrieves at most `batchChunkSize` objects per call: larger
+	// requests are split and their results merged in order.
+	for start := 0; start < len(objectIDs); start += batchChunkSize {
+		end := start + batchChunkSize
+		if end > len(objectIDs) {
+			end = len(objectIDs)
+		}
+
+		var chunk []Object
+		if chunk, err = i.getObjectsChunk(objectIDs[start:end], attributesToRetrieve, opts); err != nil {
+			return
+		}
+		i.scrubber.scrubObjects(chunk)
+
+		for j, obj := range chunk {
+			// Missing objects are reported either as `null` or as an error
+			// message instead of the object.
+			if _, ok := obj["objectID"]; !ok && start+j < end {
+				obj = nil
+				missing = append(missing, objectIDs[start+j])
+			}
+			objs = append(objs, obj)
+		}
+	}
+
+	return
+}
+
+// getObjectsChunk retrieves, in a single call, the objects identified by the
+// `objectIDs`.
+func (i *index) getObjectsChunk(objectIDs, attributesToRetrieve []string, opts *RequestOptions) (objs []Object, err error) {
 	attrs := strings.Join(attributesToRetrieve, ",")
 
 	requests := make([]map[string]string, len(objectIDs))
@@ -86,7 +185,7 @@ func (i *index) getObjects(objectIDs, attributesToRetrieve []string, opts *Reque
 
 	var res objects
 	path := "/1/indexes/*/objects"
-	err = i.client.request(&res, "POST", path, body, read, opts)
+	err = i.request(&res, "POST", path, body, read, opts)
 	objs = res.Results
 	return
 }
@@ -96,6 +195,15 @@ func (i *index) GetObjects(objectIDs []string) (objs []Object, err error) {
 }
 
 func (i *index) GetObjectsWithRequestOptions(objectIDs []string, opts *RequestOptions) (objs []Object, err error) {
+	objs, _, err = i.getObjects(objectIDs, nil, opts)
+	return
+}
+
+func (i *index) GetObjectsWithMissing(objectIDs []string) (objs []Object, missing []string, err error) {
+	return i.GetObjectsWithMissingWithRequestOptions(objectIDs, nil)
+}
+
+func (i *index) GetObjectsWithMissingWithRequestOptions(objectIDs []string, opts *RequestOptions) (objs []Object, missing []string, err error) {
 	return i.getObjects(objectIDs, nil, opts)
 }
 
@@ -104,7 +212,8 @@ func (i *index) GetObjectsAttrs(objectIDs, attrs []string) (objs []Object, err e
 }
 
 func (i *index) GetObjectsAttrsWithRequestOptions(objectIDs, attrs []string, opts *RequestOptions) (objs []Object, err error) {
-	return i.getObjects(objectIDs, attrs, opts)
+	objs, _, err = i.getObjects(objectIDs, attrs, opts)
+	return
 }
 
 func (i *index) DeleteObject(objectID string) (res DeleteTaskRes, err error) {
@@ -113,7 +222,7 @@ func (i *index) DeleteObject(objectID string) (res DeleteTaskRes, err error) {
 
 func (i *index) DeleteObjectWithRequestOptions(objectID string, opts *RequestOptions) (res DeleteTaskRes, err error) {
 	path := i.route + "/" + url.QueryEscape(objectID)
-	err = i.client.request(&res, "DELETE", path, nil, write, opts)
+	err = i.request(&res, "DELETE", path, nil, write, opts)
 	return
 }
 
@@ -123,8 +232,10 @@ func (i *index) GetSettings() (settings Settings, err error) {
 
 func (i *index) GetSettingsWithRequestOptions(opts *RequestOptions) (settings Settings, err error) {
 	path := i.route + "/settings?getVersion=2"
-	err = i.client.request(&settings, "GET", path, nil, read, opts)
+	err = i.request(&settings, "GET", path, nil, read, opts)
 	settings.clean()
+	settings.Replicas = i.client.unprefixReplicas(settings.Replicas)
+	settings.Slaves = i.client.unprefixReplicas(settings.Slaves)
 	return
 }
 
@@ -137,6 +248,17 @@ func (i *index) SetSettingsWithRequestOptions(settings Map, opts *RequestOptions
 		return
 	}
 
+	// The names of the replicas are given without the index prefix. The
+	// settings are copied so that they can be sent again as is.
+	if i.client.indexPrefix != "" {
+		settings = duplicateMap(settings)
+		for _, k := range []string{"replicas", "slaves"} {
+			if replicas, ok := settings[k].([]string); ok {
+				settings[k] = i.client.prefixReplicas(replicas)
+			}
+		}
+	}
+
 	// Handle forwardToReplicas separately
 	forwardToReplicas, ok := settings["forwardToReplicas"]
 	if !ok {
@@ -145,7 +267,44 @@ func (i *index) SetSettingsWithRequestOptions(settings Map, opts *RequestOptions
 	delete(settings, "forwardToReplicas")
 
 	path := i.route + "/settings?forwardToReplicas=" + fmt.Sprintf("%t", forwardToReplicas)
-	err = i.client.request(&res, "PUT", path, settings, write, opts)
+	err = i.request(&res, "PUT", path, settings, write, opts)
+	return
+}
+
+func (i *index) GetStats() (stats IndexRes, err error) {
+	return i.GetStatsWithRequestOptions(nil)
+}
+
+func (i *index) GetStatsWithRequestOptions(opts *RequestOptions) (stats IndexRes, err error) {
+	var indexes []IndexRes
+	if indexes, err = i.client.ListAllIndexesWithRequestOptions(opts); err != nil {
+		return
+	}
+
+	// The names of the listed indexes are not prefixed
+	name := strings.TrimPrefix(i.name, i.client.indexPrefix)
+	for _, res := range indexes {
+		if res.Name == name {
+			stats = res
+			return
+		}
+	}
+
+	err = IndexNotFoundErr
+	return
+}
+
+func (i *index) PendingTaskCount() (count int, err error) {
+	return i.PendingTaskCountWithRequestOptions(nil)
+}
+
+func (i *index) PendingTaskCountWithRequestOptions(opts *RequestOptions) (count int, err error) {
+	var stats IndexRes
+	if stats, err = i.GetStatsWithRequestOptions(opts); err != nil {
+		return
+	}
+
+	count = stats.NumberOfPendingTask
 	return
 }
 
@@ -154,30 +313,47 @@ func (i *index) WaitTask(taskID int) error {
 }
 
 func (i *index) WaitTaskWithRequestOptions(taskID int, opts *RequestOptions) error {
-	var res TaskStatusRes
-	var err error
+	return i.client.pollTask(func() (TaskStatusRes, error) {
+		return i.GetStatusWithRequestOptions(taskID, opts)
+	}, opts)
+}
 
-	var maxDuration = time.Second
-	var sleepDuration time.Duration
+func (i *index) WaitTaskAsync(ctx context.Context, taskID int) <-chan error {
+	return i.WaitTaskAsyncWithRequestOptions(ctx, taskID, nil)
+}
 
-	for {
-		if res, err = i.GetStatusWithRequestOptions(taskID, opts); err != nil {
-			return err
-		}
+func (i *index) WaitTaskAsyncWithRequestOptions(ctx context.Context, taskID int, opts *RequestOptions) <-chan error {
+	// The channel is buffered so that the goroutine terminates even if the
+	// result is never received.
+	done := make(chan error, 1)
+	go func() {
+		done <- i.client.pollTaskContext(ctx, func() (TaskStatusRes, error) {
+			return i.GetStatusWithRequestOptions(taskID, opts)
+		}, opts)
+		close(done)
+	}()
+	return done
+}
 
-		if res.Status == "published" {
-			return nil
-		}
+func (i *index) WaitTasks(taskIDs []int) error {
+	return i.WaitTasksWithRequestOptions(taskIDs, nil)
+}
 
-		sleepDuration = randDuration(maxDuration)
-		time.Sleep(sleepDuration)
+func (i *index) WaitTasksWithRequestOptions(taskIDs []int, opts *RequestOptions) error {
+	if len(taskIDs) == 0 {
+		return nil
+	}
 
-		// Increase the upper boundary used to generate the sleep
-		// duration
-		if maxDuration < 10*time.Minute {
-			maxDuration *= 2
+	// Tasks of a given index are processed sequentially so waiting for the
+	// most recent one is enough to ensure all the others are finished too.
+	latest := taskIDs[0]
+	for _, taskID := range taskIDs[1:] {
+		if taskID > latest {
+			latest = taskID
 		}
 	}
+
+	return i.WaitTaskWithRequestOptions(latest, opts)
 }
 
 func (i *index) ListKeys() (keys []Key, err error) {
@@ -188,7 +364,7 @@ func (i *index) ListKeysWithRequestOptions(opts *RequestOptions) (keys []Key, er
 	var res listKeysRes
 
 	path := i.route + "/keys"
-	err = i.client.request(&res, "GET", path, nil, read, opts)
+	err = i.request(&res, "GET", path, nil, read, opts)
 	keys = res.Keys
 	return
 }
@@ -210,7 +386,7 @@ func (i *index) AddAPIKeyWithRequestOptions(ACL []string, params Map, opts *Requ
 	}
 
 	path := i.route + "/keys"
-	err = i.client.request(&res, "POST", path, req, read, opts)
+	err = i.request(&res, "POST", path, encodeKeyParams(req), read, opts)
 	return
 }
 
@@ -228,7 +404,7 @@ func (i *index) UpdateAPIKeyWithRequestOptions(key string, params Map, opts *Req
 	}
 
 	path := i.route + "/keys/" + url.QueryEscape(key)
-	err = i.client.request(&res, "PUT", path, params, read, opts)
+	err = i.request(&res, "PUT", path, encodeKeyParams(params), read, opts)
 	return
 }
 
@@ -242,7 +418,7 @@ func (i *index) GetAPIKey(value string) (key Key, err error) {
 
 func (i *index) GetAPIKeyWithRequestOptions(value string, opts *RequestOptions) (key Key, err error) {
 	path := i.route + "/keys/" + url.QueryEscape(value)
-	err = i.client.request(&key, "GET", path, nil, read, opts)
+	err = i.request(&key, "GET", path, nil, read, opts)
 	return
 }
 
@@ -256,7 +432,7 @@ func (i *index) DeleteAPIKey(value string) (res DeleteRes, err error) {
 
 func (i *index) DeleteAPIKeyWithRequestOptions(value string, opts *RequestOptions) (res DeleteRes, err error) {
 	path := i.route + "/keys/" + value
-	err = i.client.request(&res, "DELETE", path, nil, write, opts)
+	err = i.request(&res, "DELETE", path, nil, write, opts)
 	return
 }
 
@@ -266,7 +442,7 @@ func (i *index) AddObject(object Object) (res CreateObjectRes, err error) {
 
 func (i *index) AddObjectWithRequestOptions(object Object, opts *RequestOptions) (res CreateObjectRes, err error) {
 	path := i.route
-	err = i.client.request(&res, "POST", path, object, write, opts)
+	err = i.request(&res, "POST", path, object, write, opts)
 	return
 }
 
@@ -281,7 +457,7 @@ func (i *index) UpdateObjectWithRequestOptions(object Object, opts *RequestOptio
 	}
 
 	path := i.route + "/" + url.QueryEscape(objectID)
-	err = i.client.request(&res, "PUT", path, object, write, opts)
+	err = i.request(&res, "PUT", path, object, write, opts)
 	return
 }
 
@@ -295,7 +471,7 @@ func (i *index) partialUpdateObject(object Object, createIfNotExists bool, opts
 	if !createIfNotExists {
 		path += "?createIfNotExists=false"
 	}
-	err = i.client.request(&res, "POST", path, object, write, opts)
+	err = i.request(&res, "POST", path, object, write, opts)
 	return
 }
 
@@ -320,13 +496,7 @@ func (i *index) AddObjects(objects []Object) (res BatchRes, err error) {
 }
 
 func (i *index) AddObjectsWithRequestOptions(objects []Object, opts *RequestOptions) (res BatchRes, err error) {
-	var operations []BatchOperation
-
-	if operations, err = newBatchOperations(objects, "addObject"); err == nil {
-		res, err = i.BatchWithRequestOptions(operations, opts)
-	}
-
-	return
+	return i.sendObjects(objects, ActionAddObject, opts)
 }
 
 func (i *index) UpdateObjects(objects []Object) (res BatchRes, err error) {
@@ -334,12 +504,23 @@ func (i *index) UpdateObjects(objects []Object) (res BatchRes, err error) {
 }
 
 func (i *index) UpdateObjectsWithRequestOptions(objects []Object, opts *RequestOptions) (res BatchRes, err error) {
-	var operations []BatchOperation
+	return i.sendObjects(objects, ActionUpdateObject, opts)
+}
+
+// sendObjects sends the `objects` in a single batch of `action` operations,
+// after handling their duplicated objectIDs according to the request options.
+func (i *index) sendObjects(objects []Object, action string, opts *RequestOptions) (res BatchRes, err error) {
+	var duplicates []string
+	if objects, duplicates, err = dedupObjects(objects, duplicatesMode(opts)); err != nil {
+		return
+	}
 
-	if operations, err = newBatchOperations(objects, "updateObject"); err == nil {
+	var operations []BatchOperation
+	if operations, err = newBatchOperations(objects, action); err == nil {
 		res, err = i.BatchWithRequestOptions(operations, opts)
 	}
 
+	res.Duplicates = duplicates
 	return
 }
 
@@ -358,7 +539,7 @@ func (i *index) PartialUpdateObjects(objects []Object) (res BatchRes, err error)
 }
 
 func (i *index) PartialUpdateObjectsWithRequestOptions(objects []Object, opts *RequestOptions) (res BatchRes, err error) {
-	return i.partialUpdateObjects(objects, "partialUpdateObject", opts)
+	return i.partialUpdateObjects(objects, ActionPartialUpdateObject, opts)
 }
 
 func (i *index) PartialUpdateObjectsNoCreate(objects []Object) (res BatchRes, err error) {
@@ -366,14 +547,61 @@ func (i *index) PartialUpdateObjectsNoCreate(objects []Object) (res BatchRes, er
 }
 
 func (i *index) PartialUpdateObjectsNoCreateWithRequestOptions(objects []Object, opts *RequestOptions) (res BatchRes, err error) {
-	return i.partialUpdateObjects(objects, "partialUpdateObjectNoCreate", opts)
+	return i.partialUpdateObjects(objects, ActionPartialUpdateObjectNoCreate, opts)
+}
+
+func (i *index) PartialUpdateMany(changes map[string]Map, createIfNotExists bool) (res BatchesRes, err error) {
+	return i.PartialUpdateManyWithRequestOptions(changes, createIfNotExists, nil)
+}
+
+func (i *index) PartialUpdateManyWithRequestOptions(changes map[string]Map, createIfNotExists bool, opts *RequestOptions) (res BatchesRes, err error) {
+	action := ActionPartialUpdateObject
+	if !createIfNotExists {
+		action = ActionPartialUpdateObjectNoCreate
+	}
+
+	// Sort the objectIDs to generate the batches in a deterministic order
+	objectIDs := make([]string, 0, len(changes))
+	for objectID := range changes {
+		objectIDs = append(objectIDs, objectID)
+	}
+	sort.Strings(objectIDs)
+
+	objects := make([]Object, len(objectIDs))
+	for j, objectID := range objectIDs {
+		object := Object(duplicateMap(changes[objectID]))
+		if id, ok := object["objectID"]; ok && id != objectID {
+			err = fmt.Errorf("Cannot generate partial updates: `objectID` field %v does not match its key %q", id, objectID)
+			return
+		}
+		object["objectID"] = objectID
+		objects[j] = object
+	}
+
+	for start := 0; start < len(objects); start += batchChunkSize {
+		end := start + batchChunkSize
+		if end > len(objects) {
+			end = len(objects)
+		}
+
+		// The response of a failed chunk is kept for its failed operations
+		// to be retried but the following chunks are not sent.
+		var chunkRes BatchRes
+		chunkRes, err = i.partialUpdateObjects(objects[start:end], action, opts)
+		res = append(res, chunkRes)
+		if err != nil {
+			return
+		}
+	}
+
+	return
 }
 
-func (i *index) DeleteObjects(objectIDs []string) (res BatchRes, err error) {
+func (i *index) DeleteObjects(objectIDs []string) (res BatchesRes, err error) {
 	return i.DeleteObjectsWithRequestOptions(objectIDs, nil)
 }
 
-func (i *index) DeleteObjectsWithRequestOptions(objectIDs []string, opts *RequestOptions) (res BatchRes, err error) {
+func (i *index) DeleteObjectsWithRequestOptions(objectIDs []string, opts *RequestOptions) (res BatchesRes, err error) {
 	objects := make([]Object, len(objectIDs))
 
 	for j, id := range objectIDs {
@@ -383,8 +611,24 @@ func (i *index) DeleteObjectsWithRequestOptions(objectIDs []string, opts *Reques
 	}
 
 	var operations []BatchOperation
-	if operations, err = newBatchOperations(objects, "deleteObject"); err == nil {
-		res, err = i.BatchWithRequestOptions(operations, opts)
+	if operations, err = newBatchOperations(objects, ActionDeleteObject); err != nil {
+		return
+	}
+
+	for start := 0; start < len(operations); start += batchChunkSize {
+		end := start + batchChunkSize
+		if end > len(operations) {
+			end = len(operations)
+		}
+
+		// As for PartialUpdateMany, the response of a failed chunk is kept
+		// but the following chunks are not sent.
+		var chunkRes BatchRes
+		chunkRes, err = i.BatchWithRequestOptions(operations[start:end], opts)
+		res = append(res, chunkRes)
+		if err != nil {
+			return
+		}
 	}
 
 	return
@@ -395,12 +639,17 @@ func (i *index) Batch(operations []BatchOperation) (res BatchRes, err error) {
 }
 
 func (i *index) BatchWithRequestOptions(operations []BatchOperation, opts *RequestOptions) (res BatchRes, err error) {
+	if err = checkBatchOperations(operations); err != nil {
+		return
+	}
+
 	body := map[string][]BatchOperation{
 		"requests": operations,
 	}
 
 	path := i.route + "/batch"
-	err = i.client.request(&res, "POST", path, body, write, opts)
+	err = i.request(&res, "POST", path, body, write, opts)
+	res.Operations = newBatchOperationsRes(operations, res.ObjectIDs, err)
 	return
 }
 
@@ -417,17 +666,30 @@ func (i *index) Move(name string) (UpdateTaskRes, error) {
 }
 
 func (i *index) MoveWithRequestOptions(name string, opts *RequestOptions) (UpdateTaskRes, error) {
+	if i.isDryRun(opts) {
+		dst := i.destinationIndex(name)
+		report := DryRunReport{
+			Operation:   DryRunMoveIndex,
+			Destination: dst.name,
+			Method:      "POST",
+			Path:        i.route + "/operation",
+		}
+		return UpdateTaskRes{}, i.dryRun(report, func() (int, error) {
+			return dst.countRecords("", nil, opts)
+		})
+	}
+
 	return i.operation(name, "move", opts)
 }
 
 func (i *index) operation(dst, op string, opts *RequestOptions) (res UpdateTaskRes, err error) {
 	o := IndexOperation{
-		Destination: dst,
+		Destination: i.client.prefixedIndexName(dst),
 		Operation:   op,
 	}
 
 	path := i.route + "/operation"
-	err = i.client.request(&res, "POST", path, o, write, opts)
+	err = i.request(&res, "POST", path, o, write, opts)
 	return
 }
 
@@ -437,7 +699,7 @@ func (i *index) GetStatus(taskID int) (res TaskStatusRes, err error) {
 
 func (i *index) GetStatusWithRequestOptions(taskID int, opts *RequestOptions) (res TaskStatusRes, err error) {
 	path := i.route + fmt.Sprintf("/task/%d", taskID)
-	err = i.client.request(&res, "GET", path, nil, read, opts)
+	err = i.request(&res, "GET", path, nil, read, opts)
 	return
 }
 
@@ -455,7 +717,7 @@ func (i *index) SearchSynonymsWithRequestOptions(query string, types []string, p
 
 	path := i.route + "/synonyms/search"
 	var res SearchSynonymsRes
-	err = i.client.request(&res, "POST", path, body, search, opts)
+	err = i.request(&res, "POST", path, body, search, opts)
 
 	if err == nil {
 		synonyms = res.Hits
@@ -470,7 +732,7 @@ func (i *index) GetSynonym(objectID string) (s Synonym, err error) {
 
 func (i *index) GetSynonymWithRequestOptions(objectID string, opts *RequestOptions) (s Synonym, err error) {
 	path := i.route + "/synonyms/" + url.QueryEscape(objectID)
-	err = i.client.request(&s, "GET", path, nil, read, opts)
+	err = i.request(&s, "GET", path, nil, read, opts)
 	return
 }
 
@@ -478,12 +740,16 @@ func (i *index) AddSynonym(synonym Synonym, forwardToReplicas bool) (res UpdateT
 	return i.AddSynonymWithRequestOptions(synonym, forwardToReplicas, nil)
 }
 func (i *index) AddSynonymWithRequestOptions(synonym Synonym, forwardToReplicas bool, opts *RequestOptions) (res UpdateTaskRes, err error) {
+	if err = synonym.Validate(); err != nil {
+		return
+	}
+
 	params := Map{
 		"forwardToReplicas": forwardToReplicas,
 	}
 
 	path := i.route + "/synonyms/" + url.QueryEscape(synonym.ObjectID) + "?" + encodeMap(params)
-	err = i.client.request(&res, "PUT", path, synonym, write, opts)
+	err = i.request(&res, "PUT", path, synonym, write, opts)
 	return
 }
 
@@ -497,7 +763,7 @@ func (i *index) DeleteSynonymWithRequestOptions(objectID string, forwardToReplic
 	}
 
 	path := i.route + "/synonyms/" + url.QueryEscape(objectID) + "?" + encodeMap(params)
-	err = i.client.request(&res, "DELETE", path, nil, write, opts)
+	err = i.request(&res, "DELETE", path, nil, write, opts)
 	return
 }
 
@@ -511,7 +777,14 @@ func (i *index) ClearSynonymsWithRequestOptions(forwardToReplicas bool, opts *Re
 	}
 
 	path := i.route + "/synonyms/clear?" + encodeMap(params)
-	err = i.client.request(&res, "POST", path, nil, write, opts)
+	if i.isDryRun(opts) {
+		err = i.dryRun(DryRunReport{Operation: DryRunClearSynonyms, Method: "POST", Path: path}, func() (int, error) {
+			return i.countSynonyms(opts)
+		})
+		return
+	}
+
+	err = i.request(&res, "POST", path, nil, write, opts)
 	return
 }
 
@@ -520,13 +793,19 @@ func (i *index) BatchSynonyms(synonyms []Synonym, replaceExistingSynonyms, forwa
 }
 
 func (i *index) BatchSynonymsWithRequestOptions(synonyms []Synonym, replaceExistingSynonyms, forwardToReplicas bool, opts *RequestOptions) (res UpdateTaskRes, err error) {
+	for _, s := range synonyms {
+		if err = s.Validate(); err != nil {
+			return
+		}
+	}
+
 	params := Map{
 		"replaceExistingSynonyms": replaceExistingSynonyms,
 		"forwardToReplicas":       forwardToReplicas,
 	}
 
 	path := i.route + "/synonyms/batch?" + encodeMap(params)
-	err = i.client.request(&res, "POST", path, synonyms, write, opts)
+	err = i.request(&res, "POST", path, synonyms, write, opts)
 	return
 }
 
@@ -549,7 +828,8 @@ func (i *index) BrowseWithRequestOptions(params Map, cursor string, opts *Reques
 	}
 
 	path := i.route + "/browse"
-	err = i.client.request(&res, "POST", path, req, read, opts)
+	err = i.request(&res, "POST", path, req, read, opts)
+	i.scrubber.scrubHits(res.Hits)
 	return
 }
 
@@ -558,11 +838,19 @@ func (i *index) BrowseAll(params Map) (it IndexIterator, err error) {
 }
 
 func (i *index) BrowseAllWithRequestOptions(params Map, opts *RequestOptions) (it IndexIterator, err error) {
+	return i.BrowseAllFromWithRequestOptions(params, "", opts)
+}
+
+func (i *index) BrowseAllFrom(params Map, cursor string) (it IndexIterator, err error) {
+	return i.BrowseAllFromWithRequestOptions(params, cursor, nil)
+}
+
+func (i *index) BrowseAllFromWithRequestOptions(params Map, cursor string, opts *RequestOptions) (it IndexIterator, err error) {
 	if err = checkQuery(params); err != nil {
 		return
 	}
 
-	it, err = newIndexIterator(i, params, opts)
+	it, err = newIndexIterator(i, params, cursor, opts)
 	return
 }
 
@@ -571,9 +859,33 @@ func (i *index) Search(query string, params Map) (res QueryRes, err error) {
 }
 
 func (i *index) SearchWithRequestOptions(query string, params Map, opts *RequestOptions) (res QueryRes, err error) {
+	res.PreSearchHookDuration, err = i.search(&res, query, params, opts)
+	i.scrubber.scrubHits(res.Hits)
+	return
+}
+
+func (i *index) SearchRaw(query string, params Map) (res RawQueryRes, err error) {
+	return i.SearchRawWithRequestOptions(query, params, nil)
+}
+
+func (i *index) SearchRawWithRequestOptions(query string, params Map, opts *RequestOptions) (res RawQueryRes, err error) {
+	if res.PreSearchHookDuration, err = i.search(&res, query, params, opts); err != nil {
+		return
+	}
+	err = i.scrubber.scrubRawHits(res.Hits)
+	return
+}
+
+// search performs the search query and decodes the response into `res`. The
+// time spent in the pre-search hook, if any, is returned.
+func (i *index) search(res interface{}, query string, params Map, opts *RequestOptions) (hookDuration time.Duration, err error) {
 	copy := duplicateMap(params)
 	copy["query"] = query
 
+	if copy, hookDuration, err = runPreSearchHook(i.client.preSearchHook, i.unprefixedName(), query, copy); err != nil {
+		return
+	}
+
 	if err = checkQuery(copy); err != nil {
 		return
 	}
@@ -582,11 +894,23 @@ func (i *index) SearchWithRequestOptions(query string, params Map, opts *Request
 		"params": encodeMap(copy),
 	}
 
-	path := i.route + "/query"
-	err = i.client.request(&res, "POST", path, req, search, opts)
+	path := i.searchRoute(query, copy) + "/query"
+	err = i.request(res, "POST", path, req, search, opts)
 	return
 }
 
+func (i *index) SearchAll(query string, params Map) (it SearchIterator, err error) {
+	return i.SearchAllWithRequestOptions(query, params, nil)
+}
+
+func (i *index) SearchAllWithRequestOptions(query string, params Map, opts *RequestOptions) (SearchIterator, error) {
+	it, err := newSearchIterator(i, query, params, opts)
+	if err != nil {
+		return nil, err
+	}
+	return it, nil
+}
+
 func (i *index) DeleteBy(params Map) (res DeleteTaskRes, err error) {
 	return i.DeleteByWithRequestOptions(params, nil)
 }
@@ -601,7 +925,15 @@ func (i *index) DeleteByWithRequestOptions(params Map, opts *RequestOptions) (re
 	}
 
 	path := i.route + "/deleteByQuery"
-	err = i.client.request(&res, "POST", path, req, write, opts)
+	if i.isDryRun(opts) {
+		report := DryRunReport{Operation: DryRunDeleteBy, Method: "POST", Path: path, Params: params}
+		err = i.dryRun(report, func() (int, error) {
+			return i.countRecords("", params, opts)
+		})
+		return
+	}
+
+	err = i.request(&res, "POST", path, req, write, opts)
 	return
 }
 
@@ -610,44 +942,16 @@ func (i *index) DeleteByQuery(query string, params Map) (err error) {
 }
 
 func (i *index) DeleteByQueryWithRequestOptions(query string, params Map, opts *RequestOptions) (err error) {
-	copy := duplicateMap(params)
-	copy["attributesToRetrieve"] = []string{"objectID"}
-	copy["hitsPerPage"] = 1000
-	copy["query"] = query
-	copy["distinct"] = 0
-
-	var browseRes BrowseRes
-	var batchRes BatchRes
-	var objectIDs []string
-	var cursor string
-
-	for {
-		// Start browsing the content by cursor
-		if browseRes, err = i.BrowseWithRequestOptions(copy, cursor, opts); err != nil {
-			return
-		}
-
-		// Collect all objectIDs
-		for _, hit := range browseRes.Hits {
-			objectIDs = append(objectIDs, hit["objectID"].(string))
-		}
-
-		// Set the new cursor from response
-		cursor = browseRes.Cursor
-
-		// Break if there's no more matching records
-		if cursor == "" {
-			break
-		}
-	}
-
-	// Delete all the objects
-	if batchRes, err = i.DeleteObjectsWithRequestOptions(objectIDs, opts); err != nil {
-		return
+	if i.isDryRun(opts) {
+		copy := duplicateMap(params)
+		copy["query"] = query
+		report := DryRunReport{Operation: DryRunDeleteBy, Method: "POST", Path: i.route + "/batch", Params: copy}
+		return i.dryRun(report, func() (int, error) {
+			return i.countRecords(query, params, opts)
+		})
 	}
 
-	// Wait until DeleteObjects completion
-	err = i.WaitTaskWithRequestOptions(batchRes.TaskID, opts)
+	_, err = NewQueryDeleter(i).RunWithRequestOptions(context.Background(), query, params, opts)
 	return
 }
 
@@ -660,19 +964,28 @@ func (i *index) SearchForFacetValues(facet, query string, params Map) (res Searc
 }
 
 func (i *index) SearchForFacetValuesWithRequestOptions(facet, query string, params Map, opts *RequestOptions) (res SearchFacetRes, err error) {
-	copy := duplicateMap(params)
+	return i.SearchForFacetValuesWithParamsWithRequestOptions(facet, SearchForFacetValuesParams{
+		FacetQuery:   query,
+		SearchParams: params,
+	}, opts)
+}
+
+func (i *index) SearchForFacetValuesWithParams(facet string, params SearchForFacetValuesParams) (res SearchFacetRes, err error) {
+	return i.SearchForFacetValuesWithParamsWithRequestOptions(facet, params, nil)
+}
+
+func (i *index) SearchForFacetValuesWithParamsWithRequestOptions(facet string, params SearchForFacetValuesParams, opts *RequestOptions) (res SearchFacetRes, err error) {
+	copy := params.toMap()
 	if err = checkQuery(copy); err != nil {
 		return
 	}
 
-	copy["facetQuery"] = query
-
 	req := Map{
 		"params": encodeMap(copy),
 	}
 
 	path := i.route + "/facets/" + facet + "/query"
-	err = i.client.request(&res, "POST", path, req, search, opts)
+	err = i.request(&res, "POST", path, req, search, opts)
 	return
 }
 
@@ -687,7 +1000,7 @@ func (i *index) SaveRuleWithRequestOptions(rule Rule, forwardToReplicas bool, op
 
 	params := Map{"forwardToReplicas": forwardToReplicas}
 	path := i.route + "/rules/" + rule.ObjectID + "?" + encodeMap(params)
-	err = i.client.request(&res, "PUT", path, rule, write, opts)
+	err = i.request(&res, "PUT", path, rule, write, opts)
 	return
 }
 
@@ -705,7 +1018,7 @@ func (i *index) BatchRulesWithRequestOptions(rules []Rule, forwardToReplicas, cl
 		"clearExistingRules": clearExistingRules,
 	}
 	path := i.route + "/rules/batch?" + encodeMap(params)
-	err = i.client.request(&res, "POST", path, rules, write, opts)
+	err = i.request(&res, "POST", path, rules, write, opts)
 	return
 }
 
@@ -715,7 +1028,7 @@ func (i *index) GetRule(objectID string) (rule *Rule, err error) {
 
 func (i *index) GetRuleWithRequestOptions(objectID string, opts *RequestOptions) (rule *Rule, err error) {
 	path := i.route + "/rules/" + objectID
-	err = i.client.request(&rule, "GET", path, nil, read, opts)
+	err = i.request(&rule, "GET", path, nil, read, opts)
 	return
 }
 
@@ -726,7 +1039,7 @@ func (i *index) DeleteRule(objectID string, forwardToReplicas bool) (res DeleteR
 func (i *index) DeleteRuleWithRequestOptions(objectID string, forwardToReplicas bool, opts *RequestOptions) (res DeleteRuleRes, err error) {
 	params := Map{"forwardToReplicas": forwardToReplicas}
 	path := i.route + "/rules/" + objectID + "?" + encodeMap(params)
-	err = i.client.request(&res, "DELETE", path, nil, write, opts)
+	err = i.request(&res, "DELETE", path, nil, write, opts)
 	return
 }
 
@@ -737,20 +1050,27 @@ func (i *index) ClearRules(forwardToReplicas bool) (res ClearRulesRes, err error
 func (i *index) ClearRulesWithRequestOptions(forwardToReplicas bool, opts *RequestOptions) (res ClearRulesRes, err error) {
 	params := Map{"forwardToReplicas": forwardToReplicas}
 	path := i.route + "/rules/clear?" + encodeMap(params)
-	err = i.client.request(&res, "POST", path, nil, write, opts)
+	if i.isDryRun(opts) {
+		err = i.dryRun(DryRunReport{Operation: DryRunClearRules, Method: "POST", Path: path}, func() (int, error) {
+			return i.countRules(opts)
+		})
+		return
+	}
+
+	err = i.request(&res, "POST", path, nil, write, opts)
 	return
 }
 
-func (i *index) SearchRules(params Map) (res SearchRulesRes, err error) {
+func (i *index) SearchRules(params SearchRulesParams) (res SearchRulesRes, err error) {
 	return i.SearchRulesWithRequestOptions(params, nil)
 }
 
-func (i *index) SearchRulesWithRequestOptions(params Map, opts *RequestOptions) (res SearchRulesRes, err error) {
+func (i *index) SearchRulesWithRequestOptions(params SearchRulesParams, opts *RequestOptions) (res SearchRulesRes, err error) {
 	if err = checkSearchRulesParams(params); err != nil {
 		return
 	}
 
 	path := i.route + "/rules/search"
-	err = i.client.request(&res, "POST", path, params, read, opts)
+	err = i.request(&res, "POST", path, params, read, opts)
 	return
 }
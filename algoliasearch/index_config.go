@@ -0,0 +1,180 @@
+package algoliasearch
+
+import "sort"
+
+// indexConfigPageSize is the number of synonyms or rules retrieved per
+// request by Index.ExportConfig.
+const indexConfigPageSize = 1000
+
+// indexConfigExcludedSettings are the settings which are not part of the
+// configuration of an index: the replicas, as they usually differ between
+// environments, and the read-only ones.
+var indexConfigExcludedSettings = []string{"replicas", "slaves", "primary", "version"}
+
+// IndexConfig is the whole configuration of an index: its settings, its
+// synonyms and its rules. Its JSON representation is stable (the settings
+// are sorted by name, the synonyms and the rules by objectID) so that it can
+// be versioned and applied to other indexes with Index.ImportConfig. The
+// settings are kept as returned by the API, so that the ones not modeled by
+// Settings are not lost. The replicas of the index are not part of its
+// configuration, as they usually differ between environments.
+type IndexConfig struct {
+	Settings Map       `json:"settings"`
+	Synonyms []Synonym `json:"synonyms"`
+	Rules    []Rule    `json:"rules"`
+}
+
+// ImportConfigRes holds the IDs of the tasks started by Index.ImportConfig.
+// The IDs of the synonyms and rules tasks are 0 if no request was needed.
+type ImportConfigRes struct {
+	SettingsTaskID int
+	SynonymsTaskID int
+	RulesTaskID    int
+}
+
+// TaskIDs returns the IDs of all the tasks started by Index.ImportConfig, to
+// be waited for with Index.WaitTasks.
+func (r ImportConfigRes) TaskIDs() []int {
+	var taskIDs []int
+	for _, taskID := range []int{r.SettingsTaskID, r.SynonymsTaskID, r.RulesTaskID} {
+		if taskID != 0 {
+			taskIDs = append(taskIDs, taskID)
+		}
+	}
+	return taskIDs
+}
+
+func (i *index) ExportConfig() (IndexConfig, error) {
+	return i.ExportConfigWithRequestOptions(nil)
+}
+
+func (i *index) ExportConfigWithRequestOptions(opts *RequestOptions) (cfg IndexConfig, err error) {
+	if cfg.Settings, err = i.rawSettings(opts); err != nil {
+		return
+	}
+	for _, k := range indexConfigExcludedSettings {
+		delete(cfg.Settings, k)
+	}
+
+	cfg.Synonyms = []Synonym{}
+	for page := 0; ; page++ {
+		var synonyms []Synonym
+		if synonyms, err = i.SearchSynonymsWithRequestOptions("", nil, page, indexConfigPageSize, opts); err != nil {
+			return
+		}
+
+		for _, s := range synonyms {
+			s.HighlightResult = nil
+			cfg.Synonyms = append(cfg.Synonyms, s)
+		}
+
+		if len(synonyms) < indexConfigPageSize {
+			break
+		}
+	}
+
+	cfg.Rules = []Rule{}
+	for page := 0; ; page++ {
+		var res SearchRulesRes
+		params := SearchRulesParams{Page: page, HitsPerPage: indexConfigPageSize}
+		if res, err = i.SearchRulesWithRequestOptions(params, opts); err != nil {
+			return
+		}
+
+		for _, r := range res.Hits {
+			r.HighlightResult = nil
+			cfg.Rules = append(cfg.Rules, r)
+		}
+
+		if len(res.Hits) == 0 || page+1 >= res.NbPages {
+			break
+		}
+	}
+
+	sort.SliceStable(cfg.Synonyms, func(a, b int) bool { return cfg.Synonyms[a].ObjectID < cfg.Synonyms[b].ObjectID })
+	sort.SliceStable(cfg.Rules, func(a, b int) bool { return cfg.Rules[a].ObjectID < cfg.Rules[b].ObjectID })
+	return
+}
+
+// rawSettings returns the settings of the index as returned by the API,
+// including the ones not modeled by Settings.
+func (i *index) rawSettings(opts *RequestOptions) (settings Map, err error) {
+	err = i.request(&settings, "GET", i.route+"/settings?getVersion=2", nil, read, opts)
+	return
+}
+
+func (i *index) ImportConfig(cfg IndexConfig, clearExisting bool) (ImportConfigRes, error) {
+	return i.ImportConfigWithRequestOptions(cfg, clearExisting, nil)
+}
+
+func (i *index) ImportConfigWithRequestOptions(cfg IndexConfig, clearExisting bool, opts *RequestOptions) (res ImportConfigRes, err error) {
+	for _, s := range cfg.Synonyms {
+		if err = s.Validate(); err != nil {
+			return
+		}
+	}
+
+	settings := duplicateMap(cfg.Settings)
+	if clearExisting {
+		// The settings which are not part of the configuration are reset
+		// to their default value.
+		var current Map
+		if current, err = i.rawSettings(opts); err != nil {
+			return
+		}
+		for k := range current {
+			if _, ok := settings[k]; !ok {
+				settings[k] = nil
+			}
+		}
+	}
+	for _, k := range indexConfigExcludedSettings {
+		delete(settings, k)
+	}
+
+	// As for the rules below, the settings are sent as is, without the
+	// client-side checks of SetSettings: once decoded from JSON, they no
+	// longer have the Go types those checks expect.
+	var settingsRes UpdateTaskRes
+	if err = i.request(&settingsRes, "PUT", i.route+"/settings?forwardToReplicas=false", settings, write, opts); err != nil {
+		return
+	}
+	res.SettingsTaskID = settingsRes.TaskID
+
+	if len(cfg.Synonyms) > 0 || clearExisting {
+		synonyms := cfg.Synonyms
+		if synonyms == nil {
+			synonyms = []Synonym{}
+		}
+
+		var synonymsRes UpdateTaskRes
+		if synonymsRes, err = i.BatchSynonymsWithRequestOptions(synonyms, clearExisting, false, opts); err != nil {
+			return
+		}
+		res.SynonymsTaskID = synonymsRes.TaskID
+	}
+
+	if len(cfg.Rules) > 0 || clearExisting {
+		// The rules are sent as is, without the client-side checks of
+		// BatchRules: once decoded from JSON, the parameters of their
+		// consequences no longer have the Go types those checks expect.
+		params := Map{
+			"forwardToReplicas":  false,
+			"clearExistingRules": clearExisting,
+		}
+
+		rules := cfg.Rules
+		if rules == nil {
+			rules = []Rule{}
+		}
+
+		var rulesRes BatchRulesRes
+		path := i.route + "/rules/batch?" + encodeMap(params)
+		if err = i.request(&rulesRes, "POST", path, rules, write, opts); err != nil {
+			return
+		}
+		res.RulesTaskID = rulesRes.TaskID
+	}
+
+	return
+}
@@ -0,0 +1,113 @@
+package algoliasearch
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexConfig(t *testing.T) {
+	t.Log("TestIndexConfig: Start a server serving the configuration of an index")
+	requests := map[string]string{}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := ioutil.ReadAll(r.Body)
+		requests[r.Method+" "+r.URL.Path] = string(data)
+
+		switch {
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/settings"):
+			w.Write([]byte(`{"hitsPerPage":10,"searchableAttributes":["name"],"replicas":["products_asc"],"distinct":false,"ignorePlurals":false,"removeStopWords":false,"typoTolerance":"true","unmodeledSetting":"kept"}`))
+		case strings.HasSuffix(r.URL.Path, "/synonyms/search"):
+			w.Write([]byte(`{"hits":[
+				{"objectID":"tv","type":"synonym","synonyms":["tv","television"],"_highlightResult":{}},
+				{"objectID":"phone","type":"oneWaySynonym","input":"phone","synonyms":["iphone"]}
+			],"nbHits":2}`))
+		case strings.HasSuffix(r.URL.Path, "/rules/search"):
+			w.Write([]byte(`{"hits":[
+				{"objectID":"promo","condition":{"anchoring":"contains","pattern":"phone"},"consequence":{"params":{"automaticFacetFilters":["brand"]}},"_highlightResult":{}}
+			],"nbHits":1,"page":0,"nbPages":1}`))
+		default:
+			w.Write([]byte(`{"taskID":42}`))
+		}
+	}))
+	defer server.Close()
+	c := &client{transport: newTestTransport(server)}
+
+	t.Log("TestIndexConfig: Check the export of the configuration")
+	cfg, err := c.InitIndex("products").ExportConfig()
+	require.Nil(t, err)
+	require.NotContains(t, cfg.Settings, "replicas", "should not export the replicas")
+	require.Equal(t, 10.0, cfg.Settings["hitsPerPage"])
+	require.Equal(t, "kept", cfg.Settings["unmodeledSetting"], "should export the settings not modeled by Settings")
+	require.Len(t, cfg.Synonyms, 2)
+	require.Equal(t, "phone", cfg.Synonyms[0].ObjectID, "should sort the synonyms")
+	require.Nil(t, cfg.Synonyms[1].HighlightResult)
+	require.Len(t, cfg.Rules, 1)
+	require.Nil(t, cfg.Rules[0].HighlightResult)
+
+	t.Log("TestIndexConfig: Check the import of the JSON encoded configuration")
+	{
+		data, err := json.Marshal(cfg)
+		require.Nil(t, err)
+
+		var decoded IndexConfig
+		require.Nil(t, json.Unmarshal(data, &decoded))
+
+		res, err := c.InitIndex("products_staging").ImportConfig(decoded, true)
+		require.Nil(t, err)
+		require.Equal(t, []int{42, 42, 42}, res.TaskIDs())
+
+		settings := requests["PUT /1/indexes/products_staging/settings"]
+		require.Contains(t, settings, `"hitsPerPage":10`)
+		require.Contains(t, settings, `"unmodeledSetting":"kept"`)
+		require.NotContains(t, settings, `replicas`)
+		require.NotContains(t, settings, `null`, "should not reset any setting")
+
+		require.Contains(t, requests["POST /1/indexes/products_staging/synonyms/batch"], `"objectID":"phone"`)
+		require.Contains(t, requests["POST /1/indexes/products_staging/rules/batch"], `"automaticFacetFilters":["brand"]`)
+	}
+
+	t.Log("TestIndexConfig: Check the import of synonyms with the lowercase types returned by the API")
+	{
+		var decoded IndexConfig
+		require.Nil(t, json.Unmarshal([]byte(`{
+			"settings":{"hitsPerPage":10},
+			"synonyms":[
+				{"objectID":"phone","type":"onewaysynonym","input":"phone","synonyms":["iphone"]},
+				{"objectID":"tv","type":"altcorrection1","word":"tv","corrections":["tvs"]}
+			]
+		}`), &decoded))
+
+		_, err := c.InitIndex("products_staging").ImportConfig(decoded, false)
+		require.Nil(t, err)
+		require.Contains(t, requests["POST /1/indexes/products_staging/synonyms/batch"], `"type":"onewaysynonym"`)
+	}
+
+	t.Log("TestIndexConfig: Check that the settings absent from the configuration are reset")
+	{
+		_, err := c.InitIndex("products_staging").ImportConfig(IndexConfig{Settings: Map{"hitsPerPage": 20}}, true)
+		require.Nil(t, err)
+
+		var settings Map
+		require.Nil(t, json.Unmarshal([]byte(requests["PUT /1/indexes/products_staging/settings"]), &settings))
+		require.Equal(t, 20.0, settings["hitsPerPage"])
+		require.Contains(t, settings, "searchableAttributes")
+		require.Nil(t, settings["searchableAttributes"])
+		require.Contains(t, settings, "unmodeledSetting")
+		require.Nil(t, settings["unmodeledSetting"])
+		require.NotContains(t, settings, "replicas", "should not reset the replicas")
+	}
+
+	t.Log("TestIndexConfig: Check that nothing is cleared unless asked")
+	{
+		requests = map[string]string{}
+		res, err := c.InitIndex("products_staging").ImportConfig(IndexConfig{Settings: cfg.Settings}, false)
+		require.Nil(t, err)
+		require.Equal(t, []int{42}, res.TaskIDs())
+		require.Len(t, requests, 1)
+	}
+}
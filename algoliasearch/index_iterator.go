@@ -1,20 +1,22 @@
 package algoliasearch
 
 type indexIterator struct {
-	cursor string
-	index  Index
-	opts   *RequestOptions
-	page   BrowseRes
-	params Map
-	pos    int
+	cursor     string
+	index      Index
+	opts       *RequestOptions
+	page       BrowseRes
+	pageCursor string
+	params     Map
+	pos        int
 }
 
 // newIndexIterator instantiates a IndexIterator on the `index` and according
-// to the given `params`. It is also trying to load the first page of results
-// and return an error if something goes wrong.
-func newIndexIterator(index Index, params Map, opts *RequestOptions) (it *indexIterator, err error) {
+// to the given `params`, starting at the given `cursor`. It is also trying to
+// load the first page of results and return an error if something goes
+// wrong.
+func newIndexIterator(index Index, params Map, cursor string, opts *RequestOptions) (it *indexIterator, err error) {
 	it = &indexIterator{
-		cursor: "",
+		cursor: cursor,
 		index:  index,
 		opts:   opts,
 		params: duplicateMap(params),
@@ -53,9 +55,19 @@ func (it *indexIterator) Next() (res Map, err error) {
 	return
 }
 
+func (it *indexIterator) Cursor() (cursor string, done bool) {
+	// While the current page is not fully returned, the iteration resumes
+	// from it.
+	if it.pos < len(it.page.Hits) {
+		return it.pageCursor, false
+	}
+	return it.cursor, it.cursor == ""
+}
+
 // loadNextPage is used internally to load the next page of results, using the
 // underlying Browse cursor.
 func (it *indexIterator) loadNextPage() (err error) {
+	it.pageCursor = it.cursor
 	if it.page, err = it.index.BrowseWithRequestOptions(it.params, it.cursor, it.opts); err != nil {
 		return
 	}
@@ -0,0 +1,74 @@
+package algoliasearch
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexIteratorCursor(t *testing.T) {
+	t.Log("TestIndexIteratorCursor: Start a server browsing two pages of two records")
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Params string `json:"params"`
+		}
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&body))
+		params, err := url.ParseQuery(body.Params)
+		require.Nil(t, err)
+
+		if params.Get("cursor") == "page2" {
+			w.Write([]byte(`{"hits":[{"objectID":"3"},{"objectID":"4"}]}`))
+			return
+		}
+		w.Write([]byte(`{"hits":[{"objectID":"1"},{"objectID":"2"}],"cursor":"page2"}`))
+	}))
+	defer server.Close()
+	index := (&client{transport: newTestTransport(server)}).InitIndex("products")
+
+	next := func(it IndexIterator) string {
+		hit, err := it.Next()
+		require.Nil(t, err)
+		return hit["objectID"].(string)
+	}
+
+	checkCursor := func(it IndexIterator, cursor string, done bool, msgAndArgs ...interface{}) {
+		c, d := it.Cursor()
+		require.Equal(t, cursor, c, msgAndArgs...)
+		require.Equal(t, done, d, msgAndArgs...)
+	}
+
+	t.Log("TestIndexIteratorCursor: Check the cursor while iterating")
+	{
+		it, err := index.BrowseAll(nil)
+		require.Nil(t, err)
+		checkCursor(it, "", false)
+
+		require.Equal(t, "1", next(it))
+		checkCursor(it, "", false, "should resume from the current page")
+		require.Equal(t, "2", next(it))
+		checkCursor(it, "page2", false)
+		require.Equal(t, "3", next(it))
+		checkCursor(it, "page2", false)
+		require.Equal(t, "4", next(it))
+		checkCursor(it, "", true, "should be done once the last record is returned")
+
+		_, err = it.Next()
+		require.Equal(t, NoMoreHitsErr, err)
+	}
+
+	t.Log("TestIndexIteratorCursor: Check that an iteration can be resumed")
+	{
+		it, err := index.BrowseAllFrom(nil, "page2")
+		require.Nil(t, err)
+		require.Equal(t, "3", next(it))
+		require.Equal(t, "4", next(it))
+		checkCursor(it, "", true)
+
+		_, err = it.Next()
+		require.Equal(t, NoMoreHitsErr, err)
+	}
+}
@@ -1,7 +1,12 @@
 package algoliasearch
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"sort"
+	"strconv"
 	"sync"
 	"testing"
 	"time"
@@ -15,6 +20,21 @@ func TestIndexOperations(t *testing.T) {
 
 	objectID := addOneObject(t, i)
 
+	t.Log("TestIndexOperations: Test GetStats")
+	{
+		stats, err := i.GetStats()
+		require.Nil(t, err, "should get the index statistics without error")
+		require.Equal(t, "TestIndexOperations", stats.Name)
+		require.Equal(t, 1, stats.Entries)
+
+		_, err = c.InitIndex("TestIndexOperations_missing").GetStats()
+		require.Equal(t, IndexNotFoundErr, err)
+
+		count, err := i.PendingTaskCount()
+		require.Nil(t, err, "should get the number of pending tasks without error")
+		require.Equal(t, 0, count, "should not have pending tasks once waited for")
+	}
+
 	t.Log("TestIndexOperations: Test Copy")
 	{
 		res, err := i.Copy("TestIndexOperations_copy")
@@ -47,7 +67,7 @@ func TestIndexOperations(t *testing.T) {
 		waitTask(t, i, res.TaskID)
 
 		_, err = i.GetObject(objectID, nil)
-		if err == nil || err.Error() != "{\"message\":\"ObjectID does not exist\",\"status\":404}\n" {
+		if !IsNotFound(err) {
 			t.Fatalf("TestIndexOperations: Object %s should be deleted after clear: %s", objectID, err)
 		}
 	}
@@ -692,7 +712,7 @@ func TestIndexingAndSearch(t *testing.T) {
 		waitTask(t, i, res.TaskID)
 
 		_, err = i.GetObject("jeff bezos", nil)
-		if err == nil || err.Error() != "{\"message\":\"ObjectID does not exist\",\"status\":404}\n" {
+		if !IsNotFound(err) {
 			t.Fatalf("TestIndexingAndSearch: 'jeff bezos' record hasn't been deleted properly: %s", err)
 		}
 	}
@@ -714,10 +734,12 @@ func TestIndexingAndSearch(t *testing.T) {
 			t.Fatalf("TestIndexingAndSearch: Cannot delete 'google' records: %s", err)
 		}
 
-		waitTask(t, i, res.TaskID)
+		if err = res.Wait(i); err != nil {
+			t.Fatalf("TestIndexingAndSearch: Cannot wait for the deletion of 'google' records: %s", err)
+		}
 
 		_, err = i.GetObject("google", nil)
-		if err == nil || err.Error() != "{\"message\":\"ObjectID does not exist\",\"status\":404}\n" {
+		if !IsNotFound(err) {
 			t.Fatalf("TestIndexingAndSearch: 'jeff bezos' record hasn't been deleted properly: %s", err)
 		}
 	}
@@ -797,7 +819,7 @@ func TestSynonym(t *testing.T) {
 		waitTask(t, i, res.TaskID)
 
 		_, err = i.GetSynonym(synonyms[0].ObjectID)
-		if err == nil || err.Error() != "{\"message\":\"Synonym set does not exist\",\"status\":404}" {
+		if !IsNotFound(err) {
 			t.Fatalf("TestSynonym: First synonym hasn't been deleted properly: %s", err)
 		}
 	}
@@ -1139,6 +1161,11 @@ func TestGeoSearchParameters(t *testing.T) {
 			Map{"insideBoundingBox": "1.0,2.0,3.0,4.0,5.0,6.0,7.0,8.0"},
 			Map{"insidePolygon": "1.0,2.0,3.0,4.0,5.0,6.0"},
 			Map{"insidePolygon": "[[1.0,2.0,3.0,4.0,5.0,6.0],[1.0,2.0,3.0,4.0,5.0,6.0]]"},
+			Map{"aroundLatLng": LatLng{Lat: 1.0, Lng: 2.0}},
+			Map{"insideBoundingBox": BoundingBox{LatLng{1.0, 2.0}, LatLng{3.0, 4.0}}},
+			Map{"insideBoundingBox": []BoundingBox{{LatLng{1.0, 2.0}, LatLng{3.0, 4.0}}, {LatLng{5.0, 6.0}, LatLng{7.0, 8.0}}}},
+			Map{"insidePolygon": Polygon{{1.0, 2.0}, {3.0, 4.0}, {5.0, 6.0}}},
+			Map{"insidePolygon": []Polygon{{{1.0, 2.0}, {3.0, 4.0}, {5.0, 6.0}}, {{1.0, 2.0}, {3.0, 4.0}, {5.0, 6.0}}}},
 		}
 
 		for _, params := range validParams {
@@ -1157,8 +1184,10 @@ func TestGeoSearchParameters(t *testing.T) {
 			params      Map
 			expectedErr error
 		}{
-			{Map{"insideBoundingBox": []string{"1.0,2.0,3.0,4.0"}}, invalidType("insideBoundingBox", "string or [][]float64")},
-			{Map{"insidePolygon": []string{"1.0,2.0,3.0,4.0"}}, invalidType("insidePolygon", "string or [][]float64")},
+			{Map{"insideBoundingBox": []string{"1.0,2.0,3.0,4.0"}}, invalidType("insideBoundingBox", "string, [][]float64, BoundingBox or []BoundingBox")},
+			{Map{"insidePolygon": []string{"1.0,2.0,3.0,4.0"}}, invalidType("insidePolygon", "string, [][]float64, Polygon or []Polygon")},
+			{Map{"aroundLatLng": LatLng{Lat: 91, Lng: 0}}, LatLng{Lat: 91, Lng: 0}.Validate()},
+			{Map{"insidePolygon": Polygon{{1, 2}, {3, 4}}}, Polygon{{1, 2}, {3, 4}}.Validate()},
 		}
 
 		for _, c := range cases {
@@ -1412,10 +1441,7 @@ func TestQueryRules(t *testing.T) {
 
 	t.Log("TestQueryRules: Search for a query rule with SearchRules")
 	{
-		params := Map{
-			"query": "tea",
-		}
-		res, err := i.SearchRules(params)
+		res, err := i.SearchRules(SearchRulesParams{Query: "tea"})
 		require.Nil(t, err, "should search for rules without error")
 		require.Len(t, res.Hits, 1, "should only find one rule")
 	}
@@ -1475,3 +1501,216 @@ func TestBrowseAll(t *testing.T) {
 		require.Equal(t, 3501, count, "should browse all the records")
 	}
 }
+
+func TestPartialUpdateMany(t *testing.T) {
+	t.Parallel()
+	_, i := initClientAndIndex(t, "TestPartialUpdateMany")
+
+	t.Log("TestPartialUpdateMany: Add the objects that will get partially updated")
+	{
+		res, err := i.AddObjects([]Object{
+			{"objectID": "one", "counter": 1},
+			{"objectID": "two", "counter": 2},
+		})
+		require.Nil(t, err, "should add objects without error")
+		waitTask(t, i, res.TaskID)
+	}
+
+	t.Log("TestPartialUpdateMany: Partially update the objects from a map of changes")
+	{
+		res, err := i.PartialUpdateMany(map[string]Map{
+			"one":   {"counter": IncrementOp(10)},
+			"two":   {"counter": IncrementOp(20)},
+			"three": {"counter": 3},
+		}, false)
+		require.Nil(t, err, "should partially update objects without error")
+		require.Len(t, res, 1, "should only send one batch")
+		require.Equal(t, []string{"one", "three", "two"}, res.ObjectIDs())
+		require.Nil(t, i.WaitTasks(res.TaskIDs()), "should wait for all the tasks without error")
+	}
+
+	t.Log("TestPartialUpdateMany: Check the updated objects")
+	{
+		objects, err := i.GetObjects([]string{"one", "two", "three"})
+		require.Nil(t, err, "should get objects without error")
+		require.Len(t, objects, 3)
+		require.Equal(t, 11.0, objects[0]["counter"])
+		require.Equal(t, 22.0, objects[1]["counter"])
+		require.Nil(t, objects[2], "should not create missing objects")
+	}
+
+	t.Log("TestPartialUpdateMany: Check that mismatching objectIDs are rejected")
+	{
+		_, err := i.PartialUpdateMany(map[string]Map{
+			"one": {"objectID": "two", "counter": 0},
+		}, true)
+		require.NotNil(t, err, "should reject mismatching objectIDs")
+	}
+}
+
+func TestIndexExtraHeader(t *testing.T) {
+	t.Log("TestIndexExtraHeader: Start a server replying with the tenant header")
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"hits":[],"query":"` + r.Header.Get("X-Tenant") + `"}`))
+	}))
+	defer server.Close()
+	c := &client{transport: newTestTransport(server)}
+	a, b := c.InitIndex("tenant_a"), c.InitIndex("tenant_b")
+	a.SetExtraHeader("X-Tenant", "a")
+
+	t.Log("TestIndexExtraHeader: Check that the header is only sent for its index")
+	{
+		res, err := a.Search("", nil)
+		require.Nil(t, err)
+		require.Equal(t, "a", res.Query)
+
+		res, err = b.Search("", nil)
+		require.Nil(t, err)
+		require.Equal(t, "", res.Query)
+	}
+
+	t.Log("TestIndexExtraHeader: Check that the headers of the request take precedence")
+	{
+		opts := &RequestOptions{ExtraHeaders: map[string]string{"X-Tenant": "override"}}
+		res, err := a.SearchWithRequestOptions("", nil, opts)
+		require.Nil(t, err)
+		require.Equal(t, "override", res.Query)
+		require.Equal(t, map[string]string{"X-Tenant": "override"}, opts.ExtraHeaders, "should not modify the options")
+	}
+}
+
+func TestGetObjectsChunks(t *testing.T) {
+	t.Log("TestGetObjectsChunks: Start a server only knowing the even objectIDs")
+	var calls []int
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Requests []struct {
+				ObjectID string `json:"objectID"`
+			} `json:"requests"`
+		}
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&body))
+		calls = append(calls, len(body.Requests))
+
+		var res objects
+		for _, req := range body.Requests {
+			if n, _ := strconv.Atoi(req.ObjectID); n%2 == 0 {
+				res.Results = append(res.Results, Object{"objectID": req.ObjectID})
+			} else if n%3 == 0 {
+				res.Results = append(res.Results, Object{"message": "ObjectID does not exist"})
+			} else {
+				res.Results = append(res.Results, nil)
+			}
+		}
+		json.NewEncoder(w).Encode(res)
+	}))
+	defer server.Close()
+	index := (&client{transport: newTestTransport(server)}).InitIndex("products")
+
+	t.Log("TestGetObjectsChunks: Check that the objects are retrieved in chunks and in order")
+	{
+		objectIDs := make([]string, 2500)
+		for j := range objectIDs {
+			objectIDs[j] = strconv.Itoa(j)
+		}
+
+		objects, missing, err := index.GetObjectsWithMissing(objectIDs)
+		require.Nil(t, err, "should not fail because of the missing objects")
+		require.Equal(t, []int{1000, 1000, 500}, calls)
+		require.Len(t, objects, 2500)
+
+		require.Len(t, missing, 1250)
+		for j, obj := range objects {
+			if j%2 == 0 {
+				require.Equal(t, Object{"objectID": objectIDs[j]}, obj)
+			} else {
+				require.Nil(t, obj)
+				require.Equal(t, objectIDs[j], missing[j/2])
+			}
+		}
+
+		calls = nil
+		objects, err = index.GetObjects(objectIDs[:3])
+		require.Nil(t, err)
+		require.Equal(t, []Object{{"objectID": "0"}, nil, {"objectID": "2"}}, objects)
+	}
+}
+
+func TestGetObjectsScrubbedOnFailure(t *testing.T) {
+	t.Log("TestGetObjectsScrubbedOnFailure: Start a server failing on the second chunk")
+	calls := 0
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Requests []struct {
+				ObjectID string `json:"objectID"`
+			} `json:"requests"`
+		}
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&body))
+		if calls++; calls > 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		var res objects
+		for _, req := range body.Requests {
+			res.Results = append(res.Results, Object{"objectID": req.ObjectID, "email": "secret"})
+		}
+		json.NewEncoder(w).Encode(res)
+	}))
+	defer server.Close()
+	index := (&client{transport: newTestTransport(server)}).InitIndex("products")
+	index.SetScrubbedAttributes([]string{"email"})
+
+	t.Log("TestGetObjectsScrubbedOnFailure: Check that the objects already retrieved are scrubbed")
+	objectIDs := make([]string, 1500)
+	for j := range objectIDs {
+		objectIDs[j] = strconv.Itoa(j)
+	}
+	objects, err := index.GetObjects(objectIDs)
+	require.NotNil(t, err, "should fail on the second chunk")
+	require.Len(t, objects, 1000)
+	for _, obj := range objects {
+		require.NotContains(t, obj, "email")
+	}
+}
+
+func TestDeleteObjectsChunks(t *testing.T) {
+	t.Log("TestDeleteObjectsChunks: Start a server numbering the batches")
+	var batches []int
+	var waited []string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			waited = append(waited, r.URL.Path)
+			w.Write([]byte(`{"status":"published"}`))
+			return
+		}
+
+		var body struct {
+			Requests []BatchOperation `json:"requests"`
+		}
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&body))
+		batches = append(batches, len(body.Requests))
+		fmt.Fprintf(w, `{"taskID":%d,"objectIDs":[]}`, 10*len(batches))
+	}))
+	defer server.Close()
+	index := (&client{transport: newTestTransport(server)}).InitIndex("products")
+
+	t.Log("TestDeleteObjectsChunks: Check that the deletions are sent in chunks")
+	{
+		objectIDs := make([]string, 2500)
+		for j := range objectIDs {
+			objectIDs[j] = strconv.Itoa(j)
+		}
+
+		res, err := index.DeleteObjects(objectIDs)
+		require.Nil(t, err)
+		require.Equal(t, []int{1000, 1000, 500}, batches)
+		require.Equal(t, []int{10, 20, 30}, res.TaskIDs())
+	}
+
+	t.Log("TestDeleteObjectsChunks: Check that waiting for all the chunks waits for the last task")
+	{
+		res := BatchesRes{{TaskID: 10}, {TaskID: 30}, {TaskID: 20}}
+		require.Nil(t, res.Wait(index))
+		require.Equal(t, []string{"/1/indexes/products/task/30"}, waited)
+	}
+}
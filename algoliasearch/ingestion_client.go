@@ -0,0 +1,139 @@
+package algoliasearch
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// IngestionClient gives access to the Algolia Ingestion API, which runs the
+// tasks of the connectors (data sources, transformations, destinations)
+// configured for an application. It is mostly used to monitor the runs of
+// those tasks and to retry the failed ones.
+type IngestionClient interface {
+	// SetExtraHeader allows to set custom headers while reaching out to the
+	// Ingestion API.
+	SetExtraHeader(key, value string)
+
+	// ListRuns lists the runs of the tasks of the application. The `params`
+	// map can contain the following fields to filter and paginate the runs:
+	//   - `taskID` (only list the runs of the given task)
+	//   - `status` (comma-separated statuses, e.g. "started,finished")
+	//   - `page` and `itemsPerPage`
+	//   - `startDate` and `endDate` (RFC 3339 dates)
+	//   - `sort` and `order`
+	ListRuns(params Map) (res ListRunsRes, err error)
+
+	// ListRunsWithRequestOptions is the same as ListRuns but it also accepts
+	// extra RequestOptions.
+	ListRunsWithRequestOptions(params Map, opts *RequestOptions) (res ListRunsRes, err error)
+
+	// GetRun returns the run identified by its `runID`.
+	GetRun(runID string) (run Run, err error)
+
+	// GetRunWithRequestOptions is the same as GetRun but it also accepts
+	// extra RequestOptions.
+	GetRunWithRequestOptions(runID string, opts *RequestOptions) (run Run, err error)
+
+	// ListEvents lists the events of the run identified by its `runID`. The
+	// `params` map accepts the same pagination and date fields as ListRuns,
+	// as well as `status` and `type`.
+	ListEvents(runID string, params Map) (res ListEventsRes, err error)
+
+	// ListEventsWithRequestOptions is the same as ListEvents but it also
+	// accepts extra RequestOptions.
+	ListEventsWithRequestOptions(runID string, params Map, opts *RequestOptions) (res ListEventsRes, err error)
+
+	// RunTask starts a new run of the task identified by its `taskID`.
+	RunTask(taskID string) (res RunTaskRes, err error)
+
+	// RunTaskWithRequestOptions is the same as RunTask but it also accepts
+	// extra RequestOptions.
+	RunTaskWithRequestOptions(taskID string, opts *RequestOptions) (res RunTaskRes, err error)
+
+	// RetryRun starts a new run of the task of the run identified by its
+	// `runID`. An error is returned if the run did not fail.
+	RetryRun(runID string) (res RunTaskRes, err error)
+
+	// RetryRunWithRequestOptions is the same as RetryRun but it also accepts
+	// extra RequestOptions.
+	RetryRunWithRequestOptions(runID string, opts *RequestOptions) (res RunTaskRes, err error)
+}
+
+type ingestionClient struct {
+	transport *Transport
+}
+
+// NewIngestionClient instantiates a new `IngestionClient` from the provided
+// `appID` and `apiKey`. The `region` is the region of the Ingestion API the
+// application is hosted in: "us" or "eu".
+func NewIngestionClient(appID, apiKey, region string) (IngestionClient, error) {
+	if region != "us" && region != "eu" {
+		return nil, fmt.Errorf("Invalid region %q: should be \"us\" or \"eu\"", region)
+	}
+
+	hosts := []string{"data." + region + ".algolia.com"}
+	return &ingestionClient{
+		transport: newTransportWithOnlyHosts(appID, apiKey, hosts),
+	}, nil
+}
+
+func (c *ingestionClient) SetExtraHeader(key, value string) {
+	c.transport.setExtraHeader(key, value)
+}
+
+func (c *ingestionClient) ListRuns(params Map) (res ListRunsRes, err error) {
+	return c.ListRunsWithRequestOptions(params, nil)
+}
+
+func (c *ingestionClient) ListRunsWithRequestOptions(params Map, opts *RequestOptions) (res ListRunsRes, err error) {
+	err = c.transport.requestJSON(&res, "GET", "/1/runs", params, read, opts)
+	return
+}
+
+func (c *ingestionClient) GetRun(runID string) (run Run, err error) {
+	return c.GetRunWithRequestOptions(runID, nil)
+}
+
+func (c *ingestionClient) GetRunWithRequestOptions(runID string, opts *RequestOptions) (run Run, err error) {
+	path := "/1/runs/" + url.QueryEscape(runID)
+	err = c.transport.requestJSON(&run, "GET", path, nil, read, opts)
+	return
+}
+
+func (c *ingestionClient) ListEvents(runID string, params Map) (res ListEventsRes, err error) {
+	return c.ListEventsWithRequestOptions(runID, params, nil)
+}
+
+func (c *ingestionClient) ListEventsWithRequestOptions(runID string, params Map, opts *RequestOptions) (res ListEventsRes, err error) {
+	path := "/1/runs/" + url.QueryEscape(runID) + "/events"
+	err = c.transport.requestJSON(&res, "GET", path, params, read, opts)
+	return
+}
+
+func (c *ingestionClient) RunTask(taskID string) (res RunTaskRes, err error) {
+	return c.RunTaskWithRequestOptions(taskID, nil)
+}
+
+func (c *ingestionClient) RunTaskWithRequestOptions(taskID string, opts *RequestOptions) (res RunTaskRes, err error) {
+	path := "/1/tasks/" + url.QueryEscape(taskID) + "/run"
+	err = c.transport.requestJSON(&res, "POST", path, nil, write, opts)
+	return
+}
+
+func (c *ingestionClient) RetryRun(runID string) (res RunTaskRes, err error) {
+	return c.RetryRunWithRequestOptions(runID, nil)
+}
+
+func (c *ingestionClient) RetryRunWithRequestOptions(runID string, opts *RequestOptions) (res RunTaskRes, err error) {
+	var run Run
+	if run, err = c.GetRunWithRequestOptions(runID, opts); err != nil {
+		return
+	}
+
+	if !run.Failed() {
+		err = fmt.Errorf("Cannot retry run %s: it did not fail (status: %s, outcome: %s)", runID, run.Status, run.Outcome)
+		return
+	}
+
+	return c.RunTaskWithRequestOptions(run.TaskID, opts)
+}
@@ -0,0 +1,73 @@
+package algoliasearch
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIngestionClient(t *testing.T) {
+	t.Log("TestIngestionClient: Start a fake Ingestion API")
+	var taskRuns int
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /1/runs":
+			require.Equal(t, "finished", r.URL.Query().Get("status"))
+			w.Write([]byte(`{"runs":[{"runID":"failed","taskID":"task","status":"finished","outcome":"failure"}],"pagination":{"nbPages":1,"page":1,"nbItems":1,"itemsPerPage":10}}`))
+		case "GET /1/runs/failed":
+			w.Write([]byte(`{"runID":"failed","taskID":"task","status":"finished","outcome":"failure"}`))
+		case "GET /1/runs/succeeded":
+			w.Write([]byte(`{"runID":"succeeded","taskID":"task","status":"finished","outcome":"success"}`))
+		case "GET /1/runs/failed/events":
+			w.Write([]byte(`{"events":[{"eventID":"event","runID":"failed","status":"failed","type":"record","batchSize":100}]}`))
+		case "POST /1/tasks/task/run":
+			taskRuns++
+			w.Write([]byte(`{"runID":"retried","createdAt":"2017-07-04T12:00:00Z"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"Not found","status":404}`))
+		}
+	}))
+	defer server.Close()
+
+	transport := newTestTransport(server)
+	transport.onlyProvidedHosts = true
+	c := &ingestionClient{transport: transport}
+
+	t.Log("TestIngestionClient: List the runs and their events")
+	{
+		res, err := c.ListRuns(Map{"status": "finished"})
+		require.Nil(t, err, "should list the runs without error")
+		require.Len(t, res.Runs, 1)
+		require.True(t, res.Runs[0].Failed())
+		require.Equal(t, 1, res.Pagination.NbItems)
+
+		events, err := c.ListEvents("failed", nil)
+		require.Nil(t, err, "should list the events without error")
+		require.Len(t, events.Events, 1)
+		require.Equal(t, 100, events.Events[0].BatchSize)
+	}
+
+	t.Log("TestIngestionClient: Retry runs")
+	{
+		res, err := c.RetryRun("failed")
+		require.Nil(t, err, "should retry the failed run without error")
+		require.Equal(t, "retried", res.RunID)
+		require.Equal(t, 1, taskRuns)
+
+		_, err = c.RetryRun("succeeded")
+		require.NotNil(t, err, "should not retry successful runs")
+
+		_, err = c.GetRun("missing")
+		require.True(t, IsNotFound(err), "should only try the provided hosts")
+		require.Equal(t, 1, taskRuns)
+	}
+
+	t.Log("TestIngestionClient: Check the region")
+	{
+		_, err := NewIngestionClient("appID", "apiKey", "ap")
+		require.NotNil(t, err)
+	}
+}
@@ -0,0 +1,167 @@
+package algoliasearch
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+const (
+	// maxInsightsEventsPerRequest is the maximum number of events the
+	// Insights API accepts in a single request.
+	maxInsightsEventsPerRequest = 1000
+
+	maxInsightsEventNameLength = 64
+	maxInsightsObjectIDs       = 20
+	maxInsightsFilters         = 10
+
+	// maxInsightsEventAge is how old the events sent to the Insights API can
+	// be, while insightsClockSkew is how far in the future their timestamps
+	// are accepted to be, to account for unsynchronized clocks.
+	maxInsightsEventAge = 4 * 24 * time.Hour
+	insightsClockSkew   = time.Minute
+)
+
+var insightsUserTokenRegexp = regexp.MustCompile(`^[a-zA-Z0-9_=/+-]{1,129}$`)
+
+// InsightsClient gives access to the Algolia Insights API, which collects
+// the click, conversion and view events used by Click Analytics and
+// Personalization.
+type InsightsClient interface {
+	// SetExtraHeader allows to set custom headers while reaching out to the
+	// Insights API.
+	SetExtraHeader(key, value string)
+
+	// SendEvent sends a single event to the Insights API.
+	SendEvent(event InsightsEvent) (res InsightsRes, err error)
+
+	// SendEventWithRequestOptions is the same as SendEvent but it also
+	// accepts extra RequestOptions.
+	SendEventWithRequestOptions(event InsightsEvent, opts *RequestOptions) (res InsightsRes, err error)
+
+	// SendEvents sends the given `events` to the Insights API. They are all
+	// checked before any of them is sent and are sent by batches of 1000
+	// events, the maximum accepted by the API. The response of the last
+	// batch is returned; if a batch fails, the following ones are not sent.
+	SendEvents(events []InsightsEvent) (res InsightsRes, err error)
+
+	// SendEventsWithRequestOptions is the same as SendEvents but it also
+	// accepts extra RequestOptions.
+	SendEventsWithRequestOptions(events []InsightsEvent, opts *RequestOptions) (res InsightsRes, err error)
+}
+
+type insightsClient struct {
+	transport *Transport
+}
+
+// NewInsightsClient instantiates a new `InsightsClient` from the provided
+// `appID` and `apiKey`. The `region` is the region of the Insights API the
+// application is hosted in: "us" or "de".
+func NewInsightsClient(appID, apiKey, region string) (InsightsClient, error) {
+	if region != "us" && region != "de" {
+		return nil, fmt.Errorf("Invalid region %q: should be \"us\" or \"de\"", region)
+	}
+
+	hosts := []string{"insights." + region + ".algolia.io"}
+	return &insightsClient{
+		transport: newTransportWithOnlyHosts(appID, apiKey, hosts),
+	}, nil
+}
+
+func (c *insightsClient) SetExtraHeader(key, value string) {
+	c.transport.setExtraHeader(key, value)
+}
+
+func (c *insightsClient) SendEvent(event InsightsEvent) (res InsightsRes, err error) {
+	return c.SendEventWithRequestOptions(event, nil)
+}
+
+func (c *insightsClient) SendEventWithRequestOptions(event InsightsEvent, opts *RequestOptions) (res InsightsRes, err error) {
+	return c.SendEventsWithRequestOptions([]InsightsEvent{event}, opts)
+}
+
+func (c *insightsClient) SendEvents(events []InsightsEvent) (res InsightsRes, err error) {
+	return c.SendEventsWithRequestOptions(events, nil)
+}
+
+func (c *insightsClient) SendEventsWithRequestOptions(events []InsightsEvent, opts *RequestOptions) (res InsightsRes, err error) {
+	now := time.Now()
+	for n, event := range events {
+		if err = checkInsightsEvent(event, now); err != nil {
+			err = fmt.Errorf("Invalid event #%d: %s", n, err)
+			return
+		}
+	}
+
+	for start := 0; start < len(events); start += maxInsightsEventsPerRequest {
+		end := start + maxInsightsEventsPerRequest
+		if end > len(events) {
+			end = len(events)
+		}
+
+		body := map[string][]InsightsEvent{"events": events[start:end]}
+		if err = c.transport.requestJSON(&res, "POST", "/1/events", body, write, opts); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// checkInsightsEvent checks that the event would be accepted by the Insights
+// API at the given time `now`.
+func checkInsightsEvent(e InsightsEvent, now time.Time) error {
+	switch e.EventType {
+	case InsightsEventClick, InsightsEventConversion, InsightsEventView:
+	default:
+		return fmt.Errorf("`eventType` should be %q, %q or %q, got %q", InsightsEventClick, InsightsEventConversion, InsightsEventView, e.EventType)
+	}
+
+	if e.EventName == "" || len(e.EventName) > maxInsightsEventNameLength {
+		return fmt.Errorf("`eventName` should be between 1 and %d characters long", maxInsightsEventNameLength)
+	}
+
+	if e.Index == "" {
+		return fmt.Errorf("`index` should not be empty")
+	}
+
+	if !insightsUserTokenRegexp.MatchString(e.UserToken) {
+		return fmt.Errorf("`userToken` should be 1 to 129 alphanumeric, `_`, `=`, `/`, `+` or `-` characters, got %q", e.UserToken)
+	}
+
+	if e.Timestamp != 0 {
+		t := time.Unix(0, e.Timestamp*int64(time.Millisecond))
+		if t.Before(now.Add(-maxInsightsEventAge)) || t.After(now.Add(insightsClockSkew)) {
+			return fmt.Errorf("`timestamp` should be within the last 4 days, got %s", t.UTC().Format(time.RFC3339))
+		}
+	}
+
+	if len(e.ObjectIDs) == 0 && len(e.Filters) == 0 {
+		return fmt.Errorf("either `objectIDs` or `filters` should be set")
+	}
+
+	if len(e.ObjectIDs) > 0 && len(e.Filters) > 0 {
+		return fmt.Errorf("`objectIDs` and `filters` should not be both set")
+	}
+
+	if len(e.ObjectIDs) > maxInsightsObjectIDs {
+		return fmt.Errorf("`objectIDs` should not contain more than %d elements, got %d", maxInsightsObjectIDs, len(e.ObjectIDs))
+	}
+
+	if len(e.Filters) > maxInsightsFilters {
+		return fmt.Errorf("`filters` should not contain more than %d elements, got %d", maxInsightsFilters, len(e.Filters))
+	}
+
+	if e.QueryID != "" {
+		if e.EventType == InsightsEventView {
+			return fmt.Errorf("view events should not have a `queryID`")
+		}
+		if e.EventType == InsightsEventClick && len(e.Positions) != len(e.ObjectIDs) {
+			return fmt.Errorf("click events with a `queryID` should have one position per objectID")
+		}
+	} else if len(e.Positions) > 0 {
+		return fmt.Errorf("`positions` should only be set for events with a `queryID`")
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package algoliasearch
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsightsClient(t *testing.T) {
+	t.Log("TestInsightsClient: Start a fake Insights API")
+	var batches []int
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Events []InsightsEvent `json:"events"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		batches = append(batches, len(body.Events))
+		w.Write([]byte(`{"status":200,"message":"OK"}`))
+	}))
+	defer server.Close()
+
+	transport := newTestTransport(server)
+	transport.onlyProvidedHosts = true
+	c := &insightsClient{transport: transport}
+
+	t.Log("TestInsightsClient: Send events by batches")
+	{
+		events := make([]InsightsEvent, 2500)
+		for n := range events {
+			events[n] = NewClickEvent("Product Clicked", "products", "user-42", HitPosition{
+				ObjectID: "iphone",
+				QueryID:  "43b15df305339e827f0ac0bdc5ebcaa7",
+				Position: 1,
+			})
+		}
+
+		res, err := c.SendEvents(events)
+		require.Nil(t, err, "should send the events without error")
+		require.Equal(t, 200, res.Status)
+		require.Equal(t, []int{1000, 1000, 500}, batches)
+	}
+
+	t.Log("TestInsightsClient: Check that invalid events are not sent")
+	{
+		batches = nil
+		valid := InsightsEvent{EventType: InsightsEventView, EventName: "Product Viewed", Index: "products", UserToken: "user-42", ObjectIDs: []string{"iphone"}}
+		invalid := valid
+		invalid.UserToken = "user 42"
+
+		_, err := c.SendEvents([]InsightsEvent{valid, invalid})
+		require.NotNil(t, err, "should reject the batch")
+		require.Empty(t, batches, "should not send any event")
+	}
+}
+
+func TestInsightsEventValidation(t *testing.T) {
+	now := time.Now()
+	valid := InsightsEvent{
+		EventType: InsightsEventConversion,
+		EventName: "Product Purchased",
+		Index:     "products",
+		UserToken: "user-42",
+		Timestamp: now.Add(-time.Hour).UnixNano() / int64(time.Millisecond),
+		ObjectIDs: []string{"iphone"},
+	}
+	require.Nil(t, checkInsightsEvent(valid, now))
+
+	tooManyObjectIDs := make([]string, 21)
+	for n := range tooManyObjectIDs {
+		tooManyObjectIDs[n] = "iphone"
+	}
+
+	for _, update := range []func(e *InsightsEvent){
+		func(e *InsightsEvent) { e.EventType = "purchase" },
+		func(e *InsightsEvent) { e.EventName = "" },
+		func(e *InsightsEvent) { e.EventName = string(make([]byte, 65)) },
+		func(e *InsightsEvent) { e.Index = "" },
+		func(e *InsightsEvent) { e.UserToken = "" },
+		func(e *InsightsEvent) { e.UserToken = "user@example.com" },
+		func(e *InsightsEvent) { e.Timestamp = now.Add(-5*24*time.Hour).UnixNano() / int64(time.Millisecond) },
+		func(e *InsightsEvent) { e.Timestamp = now.Add(time.Hour).UnixNano() / int64(time.Millisecond) },
+		func(e *InsightsEvent) { e.ObjectIDs = nil },
+		func(e *InsightsEvent) { e.ObjectIDs = tooManyObjectIDs },
+		func(e *InsightsEvent) { e.Filters = []string{"brand:apple"} },
+		func(e *InsightsEvent) { e.Positions = []int{1} },
+		func(e *InsightsEvent) {
+			e.EventType = InsightsEventClick
+			e.QueryID = "43b15df305339e827f0ac0bdc5ebcaa7"
+		},
+	} {
+		e := valid
+		update(&e)
+		require.NotNil(t, checkInsightsEvent(e, now), "should reject %#v", e)
+	}
+}
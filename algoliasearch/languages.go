@@ -0,0 +1,54 @@
+package algoliasearch
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// languages lists the ISO codes of the languages supported by the engine,
+// accepted by the `queryLanguages`, `naturalLanguages` and `indexLanguages`
+// parameters.
+var languages = map[string]bool{
+	"af": true, "ar": true, "az": true, "bg": true, "bn": true, "ca": true,
+	"cs": true, "cy": true, "da": true, "de": true, "el": true, "en": true,
+	"eo": true, "es": true, "et": true, "eu": true, "fa": true, "fi": true,
+	"fo": true, "fr": true, "ga": true, "gl": true, "he": true, "hi": true,
+	"hu": true, "hy": true, "id": true, "is": true, "it": true, "ja": true,
+	"ka": true, "kk": true, "ko": true, "ku": true, "ky": true, "lt": true,
+	"lv": true, "mi": true, "mn": true, "mr": true, "ms": true, "mt": true,
+	"nb": true, "nl": true, "no": true, "ns": true, "pl": true, "ps": true,
+	"pt": true, "pt-br": true, "qu": true, "ro": true, "ru": true, "sk": true,
+	"sq": true, "sv": true, "sw": true, "ta": true, "te": true, "th": true,
+	"tl": true, "tn": true, "tr": true, "tt": true, "uk": true, "ur": true,
+	"uz": true, "zh": true,
+}
+
+// IsSupportedLanguage returns `true` if `code` is the ISO code of a language
+// supported by the engine (e.g. "en", "fr" or "pt-br").
+func IsSupportedLanguage(code string) bool {
+	return languages[code]
+}
+
+// checkLanguages checks that the value `v` of the parameter `k` is a list of
+// the ISO codes of languages supported by the engine.
+func checkLanguages(k string, v interface{}) error {
+	codes, ok := v.([]string)
+	if !ok {
+		return invalidType(k, "[]string")
+	}
+
+	var unknown []string
+	for _, code := range codes {
+		if !IsSupportedLanguage(code) {
+			unknown = append(unknown, fmt.Sprintf("%q", code))
+		}
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("`%s` should only contain supported language codes (e.g. \"en\" or \"pt-br\"), got %s", k, strings.Join(unknown, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package algoliasearch
+
+import "fmt"
+
+// UserID is a user of a multi-cluster application, assigned to the cluster
+// named `ClusterName` where its `NbRecords` records take `DataSize` bytes.
+type UserID struct {
+	UserID          string `json:"userID"`
+	ClusterName     string `json:"clusterName"`
+	NbRecords       int    `json:"nbRecords"`
+	DataSize        int    `json:"dataSize"`
+	HighlightResult Map    `json:"_highlightResult,omitempty"`
+}
+
+// SearchUserIDsRes is the response of Client.SearchUserIDs.
+type SearchUserIDsRes struct {
+	Hits        []UserID `json:"hits"`
+	NbHits      int      `json:"nbHits"`
+	Page        int      `json:"page"`
+	HitsPerPage int      `json:"hitsPerPage"`
+	UpdatedAt   int64    `json:"updatedAt"`
+}
+
+func (c *client) SearchUserIDs(query, clusterName string, page, hitsPerPage int) (res SearchUserIDsRes, err error) {
+	return c.SearchUserIDsWithRequestOptions(query, clusterName, page, hitsPerPage, nil)
+}
+
+func (c *client) SearchUserIDsWithRequestOptions(query, clusterName string, page, hitsPerPage int, opts *RequestOptions) (res SearchUserIDsRes, err error) {
+	if page < 0 {
+		err = fmt.Errorf("`page` should be positive, got %d", page)
+		return
+	}
+	if hitsPerPage < 0 {
+		err = fmt.Errorf("`hitsPerPage` should be positive, got %d", hitsPerPage)
+		return
+	}
+
+	body := Map{"query": query}
+	if clusterName != "" {
+		body["clusterName"] = clusterName
+	}
+	if page > 0 {
+		body["page"] = page
+	}
+	if hitsPerPage > 0 {
+		body["hitsPerPage"] = hitsPerPage
+	}
+
+	err = c.request(&res, "POST", "/1/clusters/mapping/search", body, read, opts)
+	return
+}
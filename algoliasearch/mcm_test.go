@@ -0,0 +1,49 @@
+package algoliasearch
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchUserIDs(t *testing.T) {
+	t.Log("TestSearchUserIDs: Start a server holding a user of a cluster")
+	var bodies []Map
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/1/clusters/mapping/search", r.URL.Path)
+		var body Map
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&body))
+		bodies = append(bodies, body)
+		w.Write([]byte(`{"hits":[{"userID":"tenant-42","clusterName":"c1-test","nbRecords":1234,"dataSize":5678,"objectID":"tenant-42"}],"nbHits":1,"page":1,"hitsPerPage":10,"updatedAt":1514562690001}`))
+	}))
+	defer server.Close()
+	c := &client{transport: newTestTransport(server), readOnly: true}
+
+	t.Log("TestSearchUserIDs: Check that the users are decoded with their cluster")
+	{
+		res, err := c.SearchUserIDs("tenant", "c1-test", 1, 10)
+		require.Nil(t, err)
+		require.Equal(t, []UserID{{UserID: "tenant-42", ClusterName: "c1-test", NbRecords: 1234, DataSize: 5678}}, res.Hits)
+		require.Equal(t, 1, res.NbHits)
+		require.Equal(t, Map{"query": "tenant", "clusterName": "c1-test", "page": 1.0, "hitsPerPage": 10.0}, bodies[0])
+	}
+
+	t.Log("TestSearchUserIDs: Check that only the set parameters are sent")
+	{
+		_, err := c.SearchUserIDs("", "", 0, 0)
+		require.Nil(t, err)
+		require.Equal(t, Map{"query": ""}, bodies[1])
+	}
+
+	t.Log("TestSearchUserIDs: Check the validation of the parameters")
+	{
+		_, err := c.SearchUserIDs("", "", -1, 0)
+		require.NotNil(t, err)
+		_, err = c.SearchUserIDs("", "", 0, -1)
+		require.NotNil(t, err)
+		require.Len(t, bodies, 2)
+	}
+}
@@ -0,0 +1,17 @@
+package algoliasearch
+
+// MetricsHook receives the metrics reported by the client. `metric` is the
+// name of the metric (e.g. `freshness_ms`), `value` its value and `tags` the
+// dimensions it applies to (e.g. the index name). Implementations are
+// expected to forward the metrics to a monitoring system (StatsD,
+// Prometheus, etc.) and must be safe for concurrent use.
+type MetricsHook interface {
+	Observe(metric string, value float64, tags map[string]string)
+}
+
+// MetricsHookFunc is an adapter to use ordinary functions as MetricsHook.
+type MetricsHookFunc func(metric string, value float64, tags map[string]string)
+
+func (f MetricsHookFunc) Observe(metric string, value float64, tags map[string]string) {
+	f(metric, value, tags)
+}
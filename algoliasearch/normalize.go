@@ -0,0 +1,133 @@
+package algoliasearch
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// This file holds the response normalization layer: the typed structures
+// whose fields were renamed or changed shape across versions of the Algolia
+// API decode both the legacy and the current forms, so that upgrades of the
+// API do not silently leave fields empty. Each normalizer rewrites the raw
+// JSON object into its current form before decoding it as usual.
+
+// rawObject is a JSON object whose values are not decoded yet.
+type rawObject map[string]json.RawMessage
+
+// rename moves the `legacy` field of the object to `current`, unless the
+// `current` field is already present.
+func (o rawObject) rename(legacy, current string) {
+	v, ok := o[legacy]
+	if !ok {
+		return
+	}
+
+	delete(o, legacy)
+	if _, ok := o[current]; !ok {
+		o[current] = v
+	}
+}
+
+// stringify turns the given fields of the object into JSON strings if they
+// are JSON numbers or booleans.
+func (o rawObject) stringify(fields ...string) {
+	for _, field := range fields {
+		v, ok := o[field]
+		if !ok || len(v) == 0 || v[0] == '"' || string(v) == "null" {
+			continue
+		}
+		o[field], _ = json.Marshal(string(v))
+	}
+}
+
+// unixTime turns the given field of the object into a UNIX timestamp if it
+// is an RFC 3339 date string.
+func (o rawObject) unixTime(field string) {
+	var s string
+	if json.Unmarshal(o[field], &s) != nil {
+		return
+	}
+
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		o[field], _ = json.Marshal(t.Unix())
+	}
+}
+
+// normalizeJSON decodes the JSON object `data`, applies the given
+// `normalizer` to it and decodes the normalized object into `v`.
+func normalizeJSON(data []byte, v interface{}, normalizer func(o rawObject)) error {
+	var o rawObject
+	if err := json.Unmarshal(data, &o); err != nil {
+		return err
+	}
+
+	if o != nil {
+		normalizer(o)
+	}
+
+	normalized, err := json.Marshal(o)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(normalized, v)
+}
+
+// UnmarshalJSON decodes keys returned either by the current keys endpoints
+// or by the legacy ones, which used `key` instead of `value` for the key
+// itself and an RFC 3339 date for `createdAt`. The expiration date of the
+// key is computed from its creation date and its validity so that decoding
+// the same key always leads to the same value.
+func (k *Key) UnmarshalJSON(data []byte) error {
+	type key Key
+	err := normalizeJSON(data, (*key)(k), func(o rawObject) {
+		o.rename("key", "value")
+		o.unixTime("createdAt")
+	})
+	if err != nil {
+		return err
+	}
+
+	if k.Validity > 0 && k.CreatedAt > 0 {
+		k.ValidUntil = time.Unix(int64(k.CreatedAt+k.Validity), 0)
+	}
+	return nil
+}
+
+// UnmarshalJSON decodes index statistics returned either by the current
+// indexes endpoint or by the legacy one, which used `numberOfPendingTask`
+// instead of `numberOfPendingTasks`.
+func (r *IndexRes) UnmarshalJSON(data []byte) error {
+	type indexRes IndexRes
+	return normalizeJSON(data, (*indexRes)(r), func(o rawObject) {
+		o.rename("numberOfPendingTask", "numberOfPendingTasks")
+	})
+}
+
+// UnmarshalJSON decodes log entries whose numeric fields are either sent as
+// strings (legacy API) or as numbers (current API).
+func (l *LogRes) UnmarshalJSON(data []byte) error {
+	type logRes LogRes
+	return normalizeJSON(data, (*logRes)(l), func(o rawObject) {
+		o.stringify("answer_code", "nb_api_calls", "processing_time_ms", "query_nb_hits")
+	})
+}
+
+// UnmarshalJSON decodes rules using either the legacy single `condition`
+// field or the current `conditions` array. Rules whose `conditions` array
+// only holds one condition are decoded as legacy rules, i.e. with their
+// `Condition` field set, so that both forms lead to the same Rule.
+func (r *Rule) UnmarshalJSON(data []byte) error {
+	type rule Rule
+	return normalizeJSON(data, (*rule)(r), func(o rawObject) {
+		if _, ok := o["condition"]; ok {
+			return
+		}
+
+		var conditions []json.RawMessage
+		if json.Unmarshal(o["conditions"], &conditions) == nil && len(conditions) == 1 {
+			o["condition"] = conditions[0]
+			delete(o, "conditions")
+		}
+	})
+}
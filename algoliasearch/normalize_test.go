@@ -0,0 +1,89 @@
+package algoliasearch
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestNormalize decodes responses of both the legacy and the current versions
+// of the API (see testdata/normalize), with all the fields the endpoints send,
+// and checks that they lead to the same typed values.
+func TestNormalize(t *testing.T) {
+	cases := []struct {
+		name     string
+		expected interface{}
+	}{
+		{"key", Key{
+			ACL:         []string{"search", "browse"},
+			CreatedAt:   1499169600,
+			Description: "Frontend key",
+			Indexes:     []string{"products"},
+			Referers:    []string{},
+			Validity:    3600,
+			Value:       "1eb37de6308abdccf9b760ddacb418b4",
+			ValidUntil:  time.Unix(1499173200, 0),
+		}},
+		{"index", IndexRes{
+			CreatedAt:           "2017-07-04T12:00:00.000Z",
+			DataSize:            340000,
+			Entries:             1200,
+			FileSize:            520000,
+			LastBuildTimeS:      2,
+			Name:                "products",
+			NumberOfPendingTask: 3,
+			PendingTask:         true,
+			UpdatedAt:           "2017-07-05T12:00:00.000Z",
+		}},
+		{"log", LogRes{
+			Answer:           "{\n  \"hits\": [],\n  \"nbHits\": 42,\n  \"page\": 0,\n  \"hitsPerPage\": 20\n}",
+			AnswerCode:       "200",
+			IP:               "127.0.0.1",
+			Method:           "POST",
+			NbAPICalls:       "1",
+			ProcessingTimeMs: "3",
+			QueryBody:        `{"params":"query=phone"}`,
+			QueryHeaders:     "User-Agent: Algolia for Go (2.20.0)\nX-Algolia-Application-Id: latency\nX-Algolia-API-Key: 1eb3********************************",
+			QueryNbHits:      "42",
+			SHA1:             "26c53bd7e38ca71f4741b71994cd94a600b7ac68",
+			Timestamp:        "2017-07-04T12:00:00Z",
+			URL:              "/1/indexes/products/query",
+		}},
+		{"rule", Rule{
+			ObjectID:    "phone-promo",
+			Description: "Promote the latest phone",
+			Condition:   NewSimpleRuleCondition(Contains, "phone"),
+			Consequence: RuleConsequence{
+				Params:  Map{"query": "smartphone"},
+				Promote: []PromotedObject{{ObjectID: "iphone", Position: 0}},
+			},
+		}},
+	}
+
+	for _, c := range cases {
+		for _, version := range []string{"legacy", "current"} {
+			file := filepath.Join("testdata", "normalize", c.name+"_"+version+".json")
+			t.Logf("TestNormalize: Decode %s", file)
+
+			data, err := ioutil.ReadFile(file)
+			require.Nil(t, err, "should read the fixture without error")
+
+			decoded := reflect.New(reflect.TypeOf(c.expected))
+			err = json.Unmarshal(data, decoded.Interface())
+			require.Nil(t, err, "should decode the fixture without error")
+
+			actual := decoded.Elem().Interface()
+			if res, ok := actual.(IndexRes); ok {
+				// Fields only sent by the current API
+				res.Replicas = nil
+				actual = res
+			}
+			require.Equal(t, c.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package algoliasearch
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxObjectIDLength is the maximum size, in bytes, accepted for an objectID.
+const maxObjectIDLength = 512
+
+// NewObjectID returns a deterministic objectID derived from the given business
+// `keys` (for instance a tenant identifier and a SKU). The same keys always
+// produce the same objectID which makes re-imports idempotent. The keys are
+// length-prefixed before being hashed with SHA-1 so that `("ab", "c")` and
+// `("a", "bc")` do not collide.
+func NewObjectID(keys ...string) string {
+	h := sha1.New()
+	for _, k := range keys {
+		h.Write([]byte(strconv.Itoa(len(k))))
+		h.Write([]byte{':'})
+		h.Write([]byte(k))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// NewPrefixedObjectID is the same as NewObjectID but the generated objectID is
+// prefixed with the given `prefix`, which is useful to keep objectIDs
+// human-readable (e.g. "product_3f7a..."). The prefix may only contain ASCII
+// letters, digits, `-`, `_`, `.` and `:` characters.
+func NewPrefixedObjectID(prefix string, keys ...string) (objectID string, err error) {
+	for _, r := range prefix {
+		if !isObjectIDPrefixRune(r) {
+			err = fmt.Errorf("Cannot generate objectID: invalid character %q in prefix %q", r, prefix)
+			return
+		}
+	}
+
+	objectID = prefix + NewObjectID(keys...)
+	err = CheckObjectID(objectID)
+	return
+}
+
+// SetObjectIDFromAttributes derives a deterministic objectID from the values of
+// the given `attributes` of the `object` and sets its `objectID` field
+// accordingly. String values are used as-is while the other values are
+// JSON-encoded before being hashed. A non-nil error is returned if one of the
+// attributes is missing.
+func SetObjectIDFromAttributes(object Object, attributes ...string) error {
+	if len(attributes) == 0 {
+		return fmt.Errorf("Cannot generate objectID: no attribute given")
+	}
+
+	keys := make([]string, len(attributes))
+	for i, attr := range attributes {
+		v, ok := object[attr]
+		if !ok {
+			return fmt.Errorf("Cannot generate objectID: attribute `%s` is missing", attr)
+		}
+
+		switch v := v.(type) {
+		case string:
+			keys[i] = v
+		default:
+			data, err := json.Marshal(v)
+			if err != nil {
+				return fmt.Errorf("Cannot generate objectID: attribute `%s` cannot be encoded: %s", attr, err)
+			}
+			keys[i] = string(data)
+		}
+	}
+
+	object["objectID"] = NewObjectID(keys...)
+	return nil
+}
+
+// CheckObjectID returns a non-nil error if the given `objectID` cannot be used
+// as an Algolia objectID i.e. if it is empty, longer than 512 bytes, not a
+// valid UTF-8 string or if it contains control characters.
+func CheckObjectID(objectID string) error {
+	if objectID == "" {
+		return fmt.Errorf("Invalid objectID: should not be empty")
+	}
+
+	if len(objectID) > maxObjectIDLength {
+		return fmt.Errorf("Invalid objectID: should not be longer than %d bytes (got %d)", maxObjectIDLength, len(objectID))
+	}
+
+	if !utf8.ValidString(objectID) {
+		return fmt.Errorf("Invalid objectID: should be a valid UTF-8 string")
+	}
+
+	for _, r := range objectID {
+		if unicode.IsControl(r) {
+			return fmt.Errorf("Invalid objectID: should not contain control characters")
+		}
+	}
+
+	return nil
+}
+
+func isObjectIDPrefixRune(r rune) bool {
+	return ('a' <= r && r <= 'z') ||
+		('A' <= r && r <= 'Z') ||
+		('0' <= r && r <= '9') ||
+		r == '-' || r == '_' || r == '.' || r == ':'
+}
@@ -0,0 +1,48 @@
+package algoliasearch
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewObjectID(t *testing.T) {
+	t.Log("TestNewObjectID: Check that objectIDs are deterministic")
+	require.Equal(t, NewObjectID("tenant", "sku-42"), NewObjectID("tenant", "sku-42"))
+	require.Len(t, NewObjectID("tenant", "sku-42"), 40, "should be an hex-encoded SHA-1")
+
+	t.Log("TestNewObjectID: Check that composite keys do not collide")
+	require.NotEqual(t, NewObjectID("ab", "c"), NewObjectID("a", "bc"))
+	require.NotEqual(t, NewObjectID("abc"), NewObjectID("ab", "c"))
+
+	t.Log("TestNewObjectID: Check prefixed objectIDs")
+	{
+		objectID, err := NewPrefixedObjectID("product_", "sku-42")
+		require.Nil(t, err)
+		require.Equal(t, "product_"+NewObjectID("sku-42"), objectID)
+
+		_, err = NewPrefixedObjectID("product/", "sku-42")
+		require.NotNil(t, err, "should reject prefixes with invalid characters")
+
+		_, err = NewPrefixedObjectID(strings.Repeat("a", maxObjectIDLength), "sku-42")
+		require.NotNil(t, err, "should reject objectIDs which are too long")
+	}
+
+	t.Log("TestNewObjectID: Check objectIDs derived from attributes")
+	{
+		object := Object{"tenant": "acme", "id": 42}
+		err := SetObjectIDFromAttributes(object, "tenant", "id")
+		require.Nil(t, err)
+		require.Equal(t, NewObjectID("acme", "42"), object["objectID"])
+
+		err = SetObjectIDFromAttributes(Object{"tenant": "acme"}, "tenant", "id")
+		require.NotNil(t, err, "should fail if an attribute is missing")
+	}
+
+	t.Log("TestNewObjectID: Check objectID validation")
+	require.Nil(t, CheckObjectID("valid-objectID"))
+	require.NotNil(t, CheckObjectID(""))
+	require.NotNil(t, CheckObjectID("invalid\nobjectID"))
+	require.NotNil(t, CheckObjectID(string([]byte{0xff, 0xfe})))
+}
@@ -0,0 +1,151 @@
+package algoliasearch
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sync"
+)
+
+// defaultBatcherConcurrency is the number of concurrent batch requests sent
+// by a ParallelBatcher if none is specified.
+const defaultBatcherConcurrency = 4
+
+// ParallelBatcher uploads large sets of batch operations to an index by
+// splitting them into chunks sent concurrently by `Concurrency` workers.
+// The operations targeting the same objectID are always sent by the same
+// worker, in order, so that they are applied in the order they were given.
+type ParallelBatcher struct {
+	index       Index
+	Concurrency int
+	ChunkSize   int
+}
+
+// NewParallelBatcher returns a ParallelBatcher sending the operations to the
+// `index` with `concurrency` workers (4 if not positive), in chunks of 1000
+// operations.
+func NewParallelBatcher(index Index, concurrency int) *ParallelBatcher {
+	if concurrency <= 0 {
+		concurrency = defaultBatcherConcurrency
+	}
+
+	return &ParallelBatcher{
+		index:       index,
+		Concurrency: concurrency,
+		ChunkSize:   batchChunkSize,
+	}
+}
+
+// AddObjects adds the `objects` to the index. See Batch.
+func (b *ParallelBatcher) AddObjects(objects []Object) (BatchesRes, error) {
+	return b.AddObjectsWithRequestOptions(objects, nil)
+}
+
+// AddObjectsWithRequestOptions is the same as AddObjects but it also accepts
+// extra RequestOptions.
+func (b *ParallelBatcher) AddObjectsWithRequestOptions(objects []Object, opts *RequestOptions) (BatchesRes, error) {
+	operations, err := newBatchOperations(objects, ActionAddObject)
+	if err != nil {
+		return nil, err
+	}
+	return b.BatchWithRequestOptions(operations, opts)
+}
+
+// Batch sends the `operations` concurrently and returns the responses of
+// all the batch requests, grouped by worker. Index-level operations
+// (`clear` and `delete`) are rejected as they cannot be ordered with the
+// other operations. Once a request fails, the remaining chunks are not sent:
+// the first error is returned and the response of the failed chunks holds
+// their failed operations.
+func (b *ParallelBatcher) Batch(operations []BatchOperation) (BatchesRes, error) {
+	return b.BatchWithRequestOptions(operations, nil)
+}
+
+// BatchWithRequestOptions is the same as Batch but it also accepts extra
+// RequestOptions.
+func (b *ParallelBatcher) BatchWithRequestOptions(operations []BatchOperation, opts *RequestOptions) (BatchesRes, error) {
+	lanes, err := b.split(operations)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		mu       sync.Mutex
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	results := make([]BatchesRes, len(lanes))
+
+	for n := range lanes {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+
+			lane := lanes[n]
+			for start := 0; start < len(lane); start += b.ChunkSize {
+				mu.Lock()
+				failed := firstErr != nil
+				mu.Unlock()
+				if failed {
+					return
+				}
+
+				end := start + b.ChunkSize
+				if end > len(lane) {
+					end = len(lane)
+				}
+
+				res, err := b.index.BatchWithRequestOptions(lane[start:end], opts)
+				results[n] = append(results[n], res)
+				if err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					mu.Unlock()
+					return
+				}
+			}
+		}(n)
+	}
+	wg.Wait()
+
+	var res BatchesRes
+	for _, r := range results {
+		res = append(res, r...)
+	}
+	return res, firstErr
+}
+
+// split distributes the `operations` among the workers: the operations on a
+// given objectID always go to the same worker while the additions without
+// objectID are evenly spread.
+func (b *ParallelBatcher) split(operations []BatchOperation) ([][]BatchOperation, error) {
+	if b.ChunkSize <= 0 || b.Concurrency <= 0 {
+		return nil, fmt.Errorf("ParallelBatcher should have a positive concurrency and chunk size")
+	}
+
+	lanes := make([][]BatchOperation, b.Concurrency)
+	next := 0
+
+	for _, op := range operations {
+		if op.Action == ActionClear || op.Action == ActionDelete {
+			return nil, fmt.Errorf("Cannot send %q operations with a ParallelBatcher", op.Action)
+		}
+
+		objectID := batchOperationObjectID(op)
+		lane := next
+		if objectID != "" {
+			h := fnv.New32a()
+			h.Write([]byte(objectID))
+			lane = int(h.Sum32() % uint32(b.Concurrency))
+		} else if op.Action == ActionAddObject {
+			next = (next + 1) % b.Concurrency
+		} else {
+			return nil, fmt.Errorf("Cannot send %q operations without objectID with a ParallelBatcher", op.Action)
+		}
+
+		lanes[lane] = append(lanes[lane], op)
+	}
+
+	return lanes, nil
+}
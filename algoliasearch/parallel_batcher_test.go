@@ -0,0 +1,87 @@
+package algoliasearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParallelBatcher(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests int
+		applied  = make(map[string][]interface{})
+	)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Requests []BatchOperation `json:"requests"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		taskID := requests
+
+		var objectIDs []string
+		for _, op := range body.Requests {
+			object := op.Body.(map[string]interface{})
+			objectID, _ := object["objectID"].(string)
+			if objectID == "fail" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"message":"Invalid record","status":400}`))
+				return
+			}
+			applied[objectID] = append(applied[objectID], object["version"])
+			objectIDs = append(objectIDs, objectID)
+		}
+		json.NewEncoder(w).Encode(BatchRes{ObjectIDs: objectIDs, TaskID: taskID})
+	}))
+	defer server.Close()
+
+	c := &client{transport: newTestTransport(server)}
+	b := NewParallelBatcher(c.InitIndex("products"), 3)
+	b.ChunkSize = 10
+
+	t.Log("TestParallelBatcher: Send operations concurrently")
+	{
+		var operations []BatchOperation
+		for version := 0; version < 5; version++ {
+			for n := 0; n < 20; n++ {
+				operations = append(operations, BatchOperation{
+					Action: "updateObject",
+					Body:   Object{"objectID": fmt.Sprintf("object-%d", n), "version": version},
+				})
+			}
+		}
+
+		res, err := b.Batch(operations)
+		require.Nil(t, err, "should send the operations without error")
+		require.Len(t, res.ObjectIDs(), 100)
+		require.Len(t, res.TaskIDs(), requests)
+
+		for n := 0; n < 20; n++ {
+			require.Equal(t, []interface{}{0.0, 1.0, 2.0, 3.0, 4.0}, applied[fmt.Sprintf("object-%d", n)], "should apply the operations of a record in order")
+		}
+	}
+
+	t.Log("TestParallelBatcher: Check the failures")
+	{
+		b.ChunkSize = 1
+		res, err := b.AddObjects([]Object{{"objectID": "fail"}, {"objectID": "ok"}})
+		require.NotNil(t, err, "should report the failed request")
+		require.Len(t, res.FailedOperations(), 1)
+		require.Equal(t, "fail", res.FailedOperations()[0].ObjectID)
+
+		_, err = b.Batch([]BatchOperation{{Action: "clear"}})
+		require.NotNil(t, err, "should reject index-level operations")
+
+		_, err = b.Batch([]BatchOperation{{Action: "deleteObject", Body: Object{}}})
+		require.NotNil(t, err, "should reject record operations without objectID")
+	}
+}
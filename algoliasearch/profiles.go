@@ -0,0 +1,110 @@
+package algoliasearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+// Profile describes how to connect to the Algolia application of a given
+// environment. The API key is never stored in the profile itself: `APIKeyEnv`
+// is the name of the environment variable holding it. Timeouts are expressed
+// in milliseconds, like for Client.SetTimeout.
+type Profile struct {
+	AppID          string   `json:"appID"`
+	APIKeyEnv      string   `json:"apiKeyEnv"`
+	Hosts          []string `json:"hosts,omitempty"`
+	ConnectTimeout int      `json:"connectTimeout,omitempty"`
+	ReadTimeout    int      `json:"readTimeout,omitempty"`
+	IndexPrefix    string   `json:"indexPrefix,omitempty"`
+}
+
+// Profiles maps environment names (e.g. "staging", "production") to their
+// Profile.
+type Profiles map[string]Profile
+
+// LoadProfiles reads the JSON configuration file located at `path` and
+// returns the profiles it describes. The file is expected to be a JSON object
+// whose keys are the environment names, for instance:
+//
+//	{
+//	  "staging": {
+//	    "appID": "STAGINGAPPID",
+//	    "apiKeyEnv": "ALGOLIA_STAGING_API_KEY",
+//	    "indexPrefix": "staging_"
+//	  },
+//	  "production": {
+//	    "appID": "PRODAPPID",
+//	    "apiKeyEnv": "ALGOLIA_API_KEY",
+//	    "connectTimeout": 2000,
+//	    "readTimeout": 5000
+//	  }
+//	}
+func LoadProfiles(path string) (Profiles, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot read profiles: %s", err)
+	}
+
+	return ParseProfiles(data)
+}
+
+// ParseProfiles is the same as LoadProfiles but reads the configuration from
+// the given `data` directly.
+func ParseProfiles(data []byte) (Profiles, error) {
+	var profiles Profiles
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, fmt.Errorf("Cannot parse profiles: %s", err)
+	}
+
+	for env, p := range profiles {
+		if p.AppID == "" {
+			return nil, fmt.Errorf("Invalid profile %q: missing appID", env)
+		}
+		if p.APIKeyEnv == "" {
+			return nil, fmt.Errorf("Invalid profile %q: missing apiKeyEnv", env)
+		}
+	}
+
+	return profiles, nil
+}
+
+// NewClient returns a new Client configured according to the profile of the
+// `env` environment. A non-nil error is returned if there is no such profile
+// or if its API key cannot be found.
+func (p Profiles) NewClient(env string) (Client, error) {
+	profile, ok := p[env]
+	if !ok {
+		return nil, fmt.Errorf("Cannot find profile %q", env)
+	}
+
+	return profile.NewClient()
+}
+
+// NewClient returns a new Client configured according to the profile. The API
+// key is read from the environment variable named by `APIKeyEnv` and the
+// names of the indexes are prefixed with `IndexPrefix` (see WithIndexPrefix).
+func (p Profile) NewClient() (Client, error) {
+	apiKey := os.Getenv(p.APIKeyEnv)
+	if apiKey == "" {
+		return nil, fmt.Errorf("Cannot create client: environment variable %s is not set", p.APIKeyEnv)
+	}
+
+	return NewClientWithOptions(p.AppID, apiKey,
+		WithHosts(p.Hosts...),
+		WithTimeouts(
+			time.Duration(p.ConnectTimeout)*time.Millisecond,
+			time.Duration(p.ReadTimeout)*time.Millisecond,
+		),
+		WithIndexPrefix(p.IndexPrefix),
+	), nil
+}
+
+// IndexName returns the name of the index `name` once prefixed with the
+// `IndexPrefix` of the profile. It should not be used with the clients
+// returned by NewClient, which already prefix the index names.
+func (p Profile) IndexName(name string) string {
+	return p.IndexPrefix + name
+}
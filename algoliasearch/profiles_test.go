@@ -0,0 +1,61 @@
+package algoliasearch
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfiles(t *testing.T) {
+	t.Log("TestProfiles: Parse a valid configuration")
+	profiles, err := ParseProfiles([]byte(`{
+		"staging": {
+			"appID": "STAGING",
+			"apiKeyEnv": "TEST_PROFILES_STAGING_KEY",
+			"hosts": ["staging.example.com"],
+			"indexPrefix": "staging_"
+		},
+		"production": {
+			"appID": "PRODUCTION",
+			"apiKeyEnv": "TEST_PROFILES_PRODUCTION_KEY",
+			"connectTimeout": 2000,
+			"readTimeout": 5000
+		}
+	}`))
+	require.Nil(t, err, "should parse the profiles without error")
+	require.Len(t, profiles, 2)
+	require.Equal(t, []string{"staging.example.com"}, profiles["staging"].Hosts)
+	require.Equal(t, "staging_products", profiles["staging"].IndexName("products"))
+	require.Equal(t, "products", profiles["production"].IndexName("products"))
+
+	t.Log("TestProfiles: Check client instantiation")
+	{
+		os.Setenv("TEST_PROFILES_STAGING_KEY", "key")
+		defer os.Unsetenv("TEST_PROFILES_STAGING_KEY")
+
+		c, err := profiles.NewClient("staging")
+		require.Nil(t, err, "should create the client without error")
+		require.Equal(t, []string{"staging.example.com"}, c.(*client).transport.providedHosts)
+		require.Equal(t, "staging_", c.(*client).indexPrefix)
+		require.Equal(t, "staging_products", c.InitIndex("products").(*index).name)
+
+		_, err = profiles.NewClient("production")
+		require.NotNil(t, err, "should fail if the API key is not set")
+
+		_, err = profiles.NewClient("unknown")
+		require.NotNil(t, err, "should fail if the profile does not exist")
+	}
+
+	t.Log("TestProfiles: Check invalid configurations")
+	{
+		_, err := ParseProfiles([]byte(`{"staging": {"apiKeyEnv": "KEY"}}`))
+		require.NotNil(t, err, "should fail if the appID is missing")
+
+		_, err = ParseProfiles([]byte(`{"staging": {"appID": "STAGING"}}`))
+		require.NotNil(t, err, "should fail if the apiKeyEnv is missing")
+
+		_, err = ParseProfiles([]byte(`[]`))
+		require.NotNil(t, err, "should fail on invalid JSON")
+	}
+}
@@ -0,0 +1,140 @@
+package algoliasearch
+
+import (
+	"context"
+	"fmt"
+)
+
+// DeleteByQueryProgress is reported by QueryDeleter.Run as the deletion
+// progresses: `Matched` is the number of matching records found so far and
+// `Deleted` the number of records whose deletion was sent so far.
+type DeleteByQueryProgress struct {
+	Matched int
+	Deleted int
+}
+
+// DeleteByQueryRes describes the records deleted by QueryDeleter.Run: their
+// number along with the IDs of the tasks of the batch requests sent.
+type DeleteByQueryRes struct {
+	Deleted int
+	TaskIDs []int
+}
+
+// QueryDeleter deletes all the records of an index matching a query. The
+// objectIDs of the matching records are first collected by browsing the
+// index, only retrieving the `objectID` attribute, and the records are then
+// deleted in chunks of `ChunkSize` records. `MaxDeletions`, if positive, is a
+// safety cap: nothing is deleted if more records match the query.
+// `OnProgress`, if non-nil, is called after each page browsed and each chunk
+// deleted.
+type QueryDeleter struct {
+	index        Index
+	ChunkSize    int
+	MaxDeletions int
+	OnProgress   func(DeleteByQueryProgress)
+}
+
+// NewQueryDeleter returns a QueryDeleter deleting records from the `index`
+// in chunks of 1000 records, without any cap.
+func NewQueryDeleter(index Index) *QueryDeleter {
+	return &QueryDeleter{
+		index:     index,
+		ChunkSize: batchChunkSize,
+	}
+}
+
+// Run deletes all the records matching the `query` and `params` then waits
+// for the deletions to be applied. Once `ctx` is cancelled, no more request
+// is sent and its error is returned, along with the deletions sent so far.
+func (d *QueryDeleter) Run(ctx context.Context, query string, params Map) (DeleteByQueryRes, error) {
+	return d.RunWithRequestOptions(ctx, query, params, nil)
+}
+
+// RunWithRequestOptions is the same as Run but it also accepts extra
+// RequestOptions.
+func (d *QueryDeleter) RunWithRequestOptions(ctx context.Context, query string, params Map, opts *RequestOptions) (res DeleteByQueryRes, err error) {
+	if d.ChunkSize <= 0 {
+		err = fmt.Errorf("QueryDeleter should have a positive chunk size")
+		return
+	}
+
+	var objectIDs []string
+	if objectIDs, err = d.collect(ctx, query, params, opts); err != nil {
+		return
+	}
+
+	for start := 0; start < len(objectIDs); start += d.ChunkSize {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+
+		end := start + d.ChunkSize
+		if end > len(objectIDs) {
+			end = len(objectIDs)
+		}
+
+		var batchesRes BatchesRes
+		if batchesRes, err = d.index.DeleteObjectsWithRequestOptions(objectIDs[start:end], opts); err != nil {
+			return
+		}
+		res.Deleted += end - start
+		res.TaskIDs = append(res.TaskIDs, batchesRes.TaskIDs()...)
+		d.progress(len(objectIDs), res.Deleted)
+	}
+
+	if len(res.TaskIDs) == 0 {
+		return
+	}
+
+	// The tasks of an index are processed in order: waiting for the last one
+	// is enough.
+	select {
+	case err = <-d.index.WaitTaskAsyncWithRequestOptions(ctx, res.TaskIDs[len(res.TaskIDs)-1], opts):
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	return
+}
+
+// collect browses the index to collect the objectIDs of the records matching
+// the `query` and `params`.
+func (d *QueryDeleter) collect(ctx context.Context, query string, params Map, opts *RequestOptions) (objectIDs []string, err error) {
+	copy := duplicateMap(params)
+	copy["attributesToRetrieve"] = []string{"objectID"}
+	copy["hitsPerPage"] = 1000
+	copy["query"] = query
+	copy["distinct"] = 0
+
+	var cursor string
+	for {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+
+		var browseRes BrowseRes
+		if browseRes, err = d.index.BrowseWithRequestOptions(copy, cursor, opts); err != nil {
+			return
+		}
+
+		for _, hit := range browseRes.Hits {
+			objectID, _ := hit["objectID"].(string)
+			objectIDs = append(objectIDs, objectID)
+		}
+
+		if d.MaxDeletions > 0 && len(objectIDs) > d.MaxDeletions {
+			err = &TooManyDeletionsErr{Max: d.MaxDeletions}
+			return
+		}
+		d.progress(len(objectIDs), 0)
+
+		if cursor = browseRes.Cursor; cursor == "" {
+			return
+		}
+	}
+}
+
+func (d *QueryDeleter) progress(matched, deleted int) {
+	if d.OnProgress != nil {
+		d.OnProgress(DeleteByQueryProgress{Matched: matched, Deleted: deleted})
+	}
+}
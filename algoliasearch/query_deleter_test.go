@@ -0,0 +1,105 @@
+package algoliasearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryDeleter(t *testing.T) {
+	t.Log("TestQueryDeleter: Start a server browsing two pages of matching records")
+	var mu sync.Mutex
+	var batches [][]string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.URL.Path {
+		case "/1/indexes/products/browse":
+			var body struct {
+				Params string `json:"params"`
+			}
+			require.Nil(t, json.NewDecoder(r.Body).Decode(&body))
+			params, err := url.ParseQuery(body.Params)
+			require.Nil(t, err)
+			require.Equal(t, `["objectID"]`, params.Get("attributesToRetrieve"))
+
+			if params.Get("cursor") == "" {
+				w.Write([]byte(`{"hits":[{"objectID":"1"},{"objectID":"2"}],"cursor":"next"}`))
+			} else {
+				w.Write([]byte(`{"hits":[{"objectID":"3"}]}`))
+			}
+
+		case "/1/indexes/products/batch":
+			var req struct {
+				Requests []struct {
+					Body struct {
+						ObjectID string `json:"objectID"`
+					} `json:"body"`
+				} `json:"requests"`
+			}
+			require.Nil(t, json.NewDecoder(r.Body).Decode(&req))
+			var objectIDs []string
+			for _, op := range req.Requests {
+				objectIDs = append(objectIDs, op.Body.ObjectID)
+			}
+			batches = append(batches, objectIDs)
+			fmt.Fprintf(w, `{"taskID":%d,"objectIDs":[]}`, len(batches))
+
+		default:
+			w.Write([]byte(`{"status":"published"}`))
+		}
+	}))
+	defer server.Close()
+	index := (&client{transport: newTestTransport(server)}).InitIndex("products")
+
+	t.Log("TestQueryDeleter: Check that the records are deleted in chunks")
+	{
+		var progress []DeleteByQueryProgress
+		d := NewQueryDeleter(index)
+		d.ChunkSize = 2
+		d.OnProgress = func(p DeleteByQueryProgress) { progress = append(progress, p) }
+
+		res, err := d.Run(context.Background(), "phone", nil)
+		require.Nil(t, err)
+		require.Equal(t, DeleteByQueryRes{Deleted: 3, TaskIDs: []int{1, 2}}, res)
+		require.Equal(t, [][]string{{"1", "2"}, {"3"}}, batches)
+		require.Equal(t, []DeleteByQueryProgress{
+			{Matched: 2}, {Matched: 3}, {Matched: 3, Deleted: 2}, {Matched: 3, Deleted: 3},
+		}, progress)
+	}
+
+	t.Log("TestQueryDeleter: Check that nothing is deleted above the cap")
+	{
+		batches = nil
+		d := NewQueryDeleter(index)
+		d.MaxDeletions = 2
+
+		_, err := d.Run(context.Background(), "phone", nil)
+		require.True(t, IsTooManyDeletions(err))
+		require.Nil(t, batches)
+	}
+
+	t.Log("TestQueryDeleter: Check that nothing is sent once cancelled")
+	{
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := NewQueryDeleter(index).Run(ctx, "phone", nil)
+		require.Equal(t, context.Canceled, err)
+		require.Nil(t, batches)
+	}
+
+	t.Log("TestQueryDeleter: Check that DeleteByQuery relies on it")
+	{
+		require.Nil(t, index.DeleteByQuery("phone", nil))
+		require.Equal(t, [][]string{{"1", "2", "3"}}, batches)
+	}
+}
@@ -0,0 +1,291 @@
+package algoliasearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// QueryType controls how prefix matching applies to the words of the query,
+// to be used as the value of the `queryType` search parameter.
+type QueryType string
+
+const (
+	// PrefixLast only considers the last word of the query as a prefix (the
+	// default).
+	PrefixLast QueryType = "prefixLast"
+
+	// PrefixAll considers all the words of the query as prefixes.
+	PrefixAll QueryType = "prefixAll"
+
+	// PrefixNone disables prefix matching.
+	PrefixNone QueryType = "prefixNone"
+)
+
+func (t QueryType) encodeParam() string {
+	return string(t)
+}
+
+// checkQueryType checks that the value `v` of the `queryType` parameter is
+// a valid query type, given either as a string or as a QueryType.
+func checkQueryType(k string, v interface{}) error {
+	var t QueryType
+
+	switch v := v.(type) {
+	case string:
+		t = QueryType(v)
+	case QueryType:
+		t = v
+	default:
+		return invalidType(k, "string or QueryType")
+	}
+
+	switch t {
+	case PrefixLast, PrefixAll, PrefixNone:
+		return nil
+	default:
+		return fmt.Errorf("`%s` should be one of %q, %q or %q, got %q", k, PrefixLast, PrefixAll, PrefixNone, t)
+	}
+}
+
+// MatchAnyWord returns a copy of the `params` whose `optionalWords` are the
+// words of the `query`, so that the records matching any of the words of
+// the query are retrieved (instead of the ones matching all of them), the
+// records matching the most words being ranked first.
+func MatchAnyWord(query string, params Map) Map {
+	copy := duplicateMap(params)
+	copy["optionalWords"] = strings.Fields(query)
+	return copy
+}
+
+// OffsetLength returns a copy of the `params` retrieving `length` hits
+// starting at the `offset`-th one (zero-based), instead of a page of hits.
+// The `page` and `hitsPerPage` parameters, which cannot be used along with
+// `offset` and `length`, are removed. The QueryRes of the search then has its
+// `Offset` and `Length` set.
+func OffsetLength(params Map, offset, length int) Map {
+	copy := duplicateMap(params)
+	delete(copy, "page")
+	delete(copy, "hitsPerPage")
+	copy["offset"] = offset
+	copy["length"] = length
+	return copy
+}
+
+// ClickAnalytics returns a copy of the `params` enabling click analytics: the
+// QueryRes of the search then has a `QueryID`, which is used to relate the
+// clicks and conversions sent to the Insights API to the search (see
+// QueryRes.HitPositions).
+func ClickAnalytics(params Map) Map {
+	copy := duplicateMap(params)
+	copy["clickAnalytics"] = true
+	return copy
+}
+
+// ReRanking returns a copy of the `params` enabling or disabling the AI
+// re-ranking of the results for the query. If `applyFilters` (`facet:value`
+// facet filters) are given, only the hits matching all of them are
+// re-ranked. The hits promoted by the re-ranking are reported by
+// RankingInfo.PromotedByReRanking.
+func ReRanking(params Map, enabled bool, applyFilters ...string) Map {
+	copy := duplicateMap(params)
+	copy["enableReRanking"] = enabled
+	if len(applyFilters) > 0 {
+		copy["reRankingApplyFilter"] = applyFilters
+	}
+	return copy
+}
+
+// RuleContexts returns a copy of the `params` triggering the query rules
+// whose condition requires one of the given `contexts` (see
+// NewContextRuleCondition). The contexts should only contain alphanumeric
+// characters, hyphens and underscores.
+func RuleContexts(params Map, contexts ...string) Map {
+	copy := duplicateMap(params)
+	copy["ruleContexts"] = contexts
+	return copy
+}
+
+// EnableRules returns a copy of the `params` enabling or disabling the query
+// rules for the query.
+func EnableRules(params Map, enabled bool) Map {
+	copy := duplicateMap(params)
+	copy["enableRules"] = enabled
+	return copy
+}
+
+// checkRuleContexts checks that the value `v` of the `ruleContexts`
+// parameter is a list of valid contexts.
+func checkRuleContexts(v interface{}) error {
+	var contexts []string
+
+	switch v := v.(type) {
+	case string:
+		contexts = []string{v}
+	case []string:
+		contexts = v
+	default:
+		return invalidType("ruleContexts", "string or []string")
+	}
+
+	for _, context := range contexts {
+		if context == "" || strings.TrimFunc(context, isRuleContextRune) != "" {
+			return fmt.Errorf("`ruleContexts` should only contain alphanumeric characters, hyphens and underscores, got %q", context)
+		}
+	}
+
+	return nil
+}
+
+func isRuleContextRune(r rune) bool {
+	return r == '-' || r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
+
+// Personalization returns a copy of the `params` personalizing the results
+// for the user identified by `userToken`, whose personalization profile
+// weighs for `impact` percents (between 0 and 100) in the ranking of the
+// hits. The part of the ranking due to the personalization is reported by
+// RankingInfo.Personalization.
+func Personalization(params Map, userToken string, impact int) Map {
+	copy := duplicateMap(params)
+	copy["enablePersonalization"] = true
+	copy["userToken"] = userToken
+	copy["personalizationImpact"] = impact
+	return copy
+}
+
+// checkPersonalizationImpact checks that the `personalizationImpact` query
+// parameter is a percentage.
+func checkPersonalizationImpact(v interface{}) error {
+	impact, ok := v.(int)
+	if !ok {
+		return invalidType("personalizationImpact", "int")
+	}
+	if impact < 0 || impact > 100 {
+		return fmt.Errorf("`personalizationImpact` should be between 0 and 100, got %d", impact)
+	}
+	return nil
+}
+
+// checkUserToken checks that the `userToken` query parameter is a non-empty
+// string of at most 129 characters.
+func checkUserToken(v interface{}) error {
+	token, ok := v.(string)
+	if !ok {
+		return invalidType("userToken", "string")
+	}
+	if token == "" || len(token) > 129 {
+		return fmt.Errorf("`userToken` should be between 1 and 129 characters long, got %q", token)
+	}
+	return nil
+}
+
+// Limits of the `analyticsTags` query parameter, checked before sending the
+// query.
+const (
+	maxAnalyticsTags      = 100
+	maxAnalyticsTagLength = 255
+)
+
+// Analytics returns a copy of the `params` enabling or disabling the
+// analytics for the query. If `tags` are given, the query is attributed to
+// them in the analytics, e.g. to compare the platforms or the user segments.
+func Analytics(params Map, enabled bool, tags ...string) Map {
+	copy := duplicateMap(params)
+	copy["analytics"] = enabled
+	if len(tags) > 0 {
+		copy["analyticsTags"] = tags
+	}
+	return copy
+}
+
+// EnableABTest returns a copy of the `params` enabling or disabling the A/B
+// tests for the query. The A/B test and the variant the query was attributed
+// to are reported by QueryRes.ABTestID and QueryRes.ABTestVariantID.
+func EnableABTest(params Map, enabled bool) Map {
+	copy := duplicateMap(params)
+	copy["enableABTest"] = enabled
+	return copy
+}
+
+// checkAnalyticsTags checks that the value `v` of the `analyticsTags`
+// parameter is a reasonable list of non-empty tags.
+func checkAnalyticsTags(v interface{}) error {
+	var tags []string
+
+	switch v := v.(type) {
+	case string:
+		// A comma-separated list of tags
+		tags = strings.Split(v, ",")
+	case []string:
+		tags = v
+	default:
+		return invalidType("analyticsTags", "string or []string")
+	}
+
+	if len(tags) > maxAnalyticsTags {
+		return fmt.Errorf("`analyticsTags` should contain at most %d tags, got %d", maxAnalyticsTags, len(tags))
+	}
+
+	for _, tag := range tags {
+		if strings.TrimSpace(tag) == "" || len(tag) > maxAnalyticsTagLength {
+			return fmt.Errorf("`analyticsTags` should only contain tags between 1 and %d characters long, got %q", maxAnalyticsTagLength, tag)
+		}
+	}
+
+	return nil
+}
+
+// HitPosition identifies a hit of a search response, as expected by the
+// click events of the Insights API: `Position` is the absolute position of
+// the hit in the results, starting at 1.
+type HitPosition struct {
+	ObjectID string
+	QueryID  string
+	Position int
+}
+
+// HitPositions returns the HitPosition of each hit of the response, in
+// order. The search should have been sent with click analytics enabled
+// (see ClickAnalytics), otherwise the `QueryID` of the positions is empty.
+func (r QueryRes) HitPositions() []HitPosition {
+	objectIDs := make([]string, len(r.Hits))
+	for n, hit := range r.Hits {
+		objectIDs[n], _ = hit["objectID"].(string)
+	}
+	return r.hitPositions(objectIDs)
+}
+
+// HitPositions is the same as QueryRes.HitPositions for the undecoded hits
+// of the response.
+func (r RawQueryRes) HitPositions() []HitPosition {
+	objectIDs := make([]string, len(r.Hits))
+	for n, hit := range r.Hits {
+		var h struct {
+			ObjectID string `json:"objectID"`
+		}
+		json.Unmarshal(hit, &h)
+		objectIDs[n] = h.ObjectID
+	}
+	return r.QueryRes.hitPositions(objectIDs)
+}
+
+// hitPositions returns the HitPosition of the hits identified by the
+// `objectIDs`, in order, given the pagination of the response.
+func (r QueryRes) hitPositions(objectIDs []string) []HitPosition {
+	first := r.Page*r.HitsPerPage + 1
+	if r.Length > 0 {
+		first = r.Offset + 1
+	}
+
+	positions := make([]HitPosition, len(objectIDs))
+	for n, objectID := range objectIDs {
+		positions[n] = HitPosition{
+			ObjectID: objectID,
+			QueryID:  r.QueryID,
+			Position: first + n,
+		}
+	}
+
+	return positions
+}
@@ -0,0 +1,205 @@
+package algoliasearch
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryOptions(t *testing.T) {
+	t.Log("TestQueryOptions: Check the queryType parameter")
+	{
+		require.Nil(t, checkQuery(Map{"queryType": PrefixAll}))
+		require.Nil(t, checkQuery(Map{"queryType": "prefixNone"}))
+		require.NotNil(t, checkQuery(Map{"queryType": "prefixFirst"}))
+		require.NotNil(t, checkQuery(Map{"queryType": 1}))
+		require.Equal(t, "queryType=prefixAll", encodeMap(Map{"queryType": PrefixAll}))
+	}
+
+	t.Log("TestQueryOptions: Check the MatchAnyWord helper")
+	{
+		params := Map{"hitsPerPage": 10}
+		res := MatchAnyWord(" apple  iphone case ", params)
+		require.Equal(t, []string{"apple", "iphone", "case"}, res["optionalWords"])
+		require.Equal(t, 10, res["hitsPerPage"])
+		require.NotContains(t, params, "optionalWords", "should not modify the given params")
+		require.Nil(t, checkQuery(res))
+	}
+}
+
+func TestClickAnalytics(t *testing.T) {
+	t.Log("TestClickAnalytics: Check the ClickAnalytics helper")
+	{
+		params := Map{"hitsPerPage": 10}
+		require.Equal(t, Map{"hitsPerPage": 10, "clickAnalytics": true}, ClickAnalytics(params))
+		require.Equal(t, Map{"hitsPerPage": 10}, params, "should not modify the given params")
+		require.Nil(t, checkQuery(ClickAnalytics(nil)))
+	}
+
+	t.Log("TestClickAnalytics: Check the positions of the hits")
+	{
+		res := QueryRes{
+			Hits:        []Map{{"objectID": "a"}, {"objectID": "b"}},
+			HitsPerPage: 20,
+			Page:        2,
+			QueryID:     "43b15df305339e827f0ac0bdc5ebcaa7",
+		}
+		require.Equal(t, []HitPosition{
+			{ObjectID: "a", QueryID: "43b15df305339e827f0ac0bdc5ebcaa7", Position: 41},
+			{ObjectID: "b", QueryID: "43b15df305339e827f0ac0bdc5ebcaa7", Position: 42},
+		}, res.HitPositions())
+
+		res.Offset = 5
+		res.Length = 2
+		require.Equal(t, 6, res.HitPositions()[0].Position, "should use offset/length pagination if set")
+	}
+}
+
+func TestOffsetLength(t *testing.T) {
+	t.Log("TestOffsetLength: Check the OffsetLength helper")
+	{
+		params := Map{"page": 2, "hitsPerPage": 10, "filters": "brand:apple"}
+		res := OffsetLength(params, 15, 5)
+		require.Equal(t, Map{"offset": 15, "length": 5, "filters": "brand:apple"}, res)
+		require.Equal(t, 2, params["page"], "should not modify the given params")
+		require.Nil(t, checkQuery(res))
+	}
+
+	t.Log("TestOffsetLength: Check the validation of the pagination")
+	{
+		require.Nil(t, checkQuery(Map{"page": 1, "hitsPerPage": 10}))
+		require.NotNil(t, checkQuery(Map{"offset": 10}))
+		require.NotNil(t, checkQuery(Map{"length": 10}))
+		require.NotNil(t, checkQuery(Map{"offset": 10, "length": 10, "page": 1}))
+		require.NotNil(t, checkQuery(Map{"offset": 10, "length": 10, "hitsPerPage": 20}))
+		require.NotNil(t, checkQuery(Map{"offset": -1, "length": 10}))
+		require.NotNil(t, checkQuery(Map{"offset": 0, "length": 1001}))
+	}
+
+	t.Log("TestOffsetLength: Check that the response fields are decoded")
+	{
+		var res QueryRes
+		require.Nil(t, json.Unmarshal([]byte(`{"hits":[],"nbHits":42,"exhaustiveNbHits":true,"offset":15,"length":5}`), &res))
+		require.Equal(t, 15, res.Offset)
+		require.Equal(t, 5, res.Length)
+		require.Equal(t, 42, res.NbHits)
+		require.True(t, res.ExhaustiveNbHits)
+	}
+}
+
+func TestLanguages(t *testing.T) {
+	t.Log("TestLanguages: Check the language query parameters")
+	{
+		require.Nil(t, checkQuery(Map{"queryLanguages": []string{"fr", "pt-br"}, "naturalLanguages": []string{"en"}, "decompoundQuery": true}))
+		require.NotNil(t, checkQuery(Map{"queryLanguages": "fr"}))
+		require.NotNil(t, checkQuery(Map{"decompoundQuery": "true"}))
+
+		err := checkQuery(Map{"naturalLanguages": []string{"en", "english", "FR"}})
+		require.NotNil(t, err)
+		require.Equal(t, "`naturalLanguages` should only contain supported language codes (e.g. \"en\" or \"pt-br\"), got \"FR\", \"english\"", err.Error())
+	}
+
+	t.Log("TestLanguages: Check the language settings")
+	{
+		require.Nil(t, checkSettings(Map{"indexLanguages": []string{"ja"}, "queryLanguages": []string{"ja", "en"}}))
+		require.NotNil(t, checkSettings(Map{"indexLanguages": []string{"jp"}}))
+	}
+}
+
+func TestReRanking(t *testing.T) {
+	t.Log("TestReRanking: Check the ReRanking helper")
+	{
+		params := Map{"hitsPerPage": 10}
+		require.Equal(t, Map{"hitsPerPage": 10, "enableReRanking": false}, ReRanking(params, false))
+		res := ReRanking(params, true, "category:phone")
+		require.Equal(t, Map{"hitsPerPage": 10, "enableReRanking": true, "reRankingApplyFilter": []string{"category:phone"}}, res)
+		require.Equal(t, Map{"hitsPerPage": 10}, params, "should not modify the given params")
+		require.Nil(t, checkQuery(res))
+	}
+
+	t.Log("TestReRanking: Check the validation of the parameters")
+	{
+		require.Nil(t, checkQuery(Map{"reRankingApplyFilter": "category:phone"}))
+		require.Nil(t, checkQuery(Map{"reRankingApplyFilter": [][]string{{"category:phone", "category:tablet"}}}))
+		require.NotNil(t, checkQuery(Map{"reRankingApplyFilter": []string{"phone"}}))
+		require.NotNil(t, checkQuery(Map{"enableReRanking": "true"}))
+	}
+}
+
+func TestRuleContexts(t *testing.T) {
+	t.Log("TestRuleContexts: Check the RuleContexts and EnableRules helpers")
+	{
+		params := Map{"hitsPerPage": 10}
+		res := RuleContexts(params, "mobile", "campaign_x")
+		require.Equal(t, Map{"hitsPerPage": 10, "ruleContexts": []string{"mobile", "campaign_x"}}, res)
+		require.Equal(t, Map{"hitsPerPage": 10}, params, "should not modify the given params")
+		require.Nil(t, checkQuery(res))
+		require.Equal(t, `ruleContexts=%5B%22mobile%22%2C%22campaign_x%22%5D`, encodeMap(Map{"ruleContexts": res["ruleContexts"]}))
+
+		require.Equal(t, Map{"enableRules": false}, EnableRules(nil, false))
+		require.Nil(t, checkQuery(EnableRules(nil, false)))
+	}
+
+	t.Log("TestRuleContexts: Check the validation of the parameters")
+	{
+		require.Nil(t, checkQuery(Map{"ruleContexts": "mobile"}))
+		require.NotNil(t, checkQuery(Map{"ruleContexts": []string{"mobile", ""}}))
+		require.NotNil(t, checkQuery(Map{"ruleContexts": []string{"mobile,campaign_x"}}))
+		require.NotNil(t, checkQuery(Map{"ruleContexts": 1}))
+		require.NotNil(t, checkQuery(Map{"enableRules": "false"}))
+	}
+}
+
+func TestPersonalization(t *testing.T) {
+	t.Log("TestPersonalization: Check the Personalization helper")
+	{
+		params := Map{"hitsPerPage": 10}
+		res := Personalization(params, "user-42", 50)
+		require.Equal(t, Map{"hitsPerPage": 10, "enablePersonalization": true, "userToken": "user-42", "personalizationImpact": 50}, res)
+		require.Equal(t, Map{"hitsPerPage": 10}, params, "should not modify the given params")
+		require.Nil(t, checkQuery(res))
+	}
+
+	t.Log("TestPersonalization: Check the validation of the parameters")
+	{
+		require.NotNil(t, checkQuery(Map{"enablePersonalization": 1}))
+		require.NotNil(t, checkQuery(Map{"personalizationImpact": 101}))
+		require.NotNil(t, checkQuery(Map{"personalizationImpact": "50"}))
+		require.NotNil(t, checkQuery(Map{"userToken": ""}))
+		require.NotNil(t, checkQuery(Map{"userToken": 42}))
+	}
+}
+
+func TestAnalytics(t *testing.T) {
+	t.Log("TestAnalytics: Check the Analytics and EnableABTest helpers")
+	{
+		params := Map{"hitsPerPage": 10}
+		res := Analytics(params, true, "mobile", "fr")
+		require.Equal(t, Map{"hitsPerPage": 10, "analytics": true, "analyticsTags": []string{"mobile", "fr"}}, res)
+		require.Equal(t, Map{"hitsPerPage": 10}, params, "should not modify the given params")
+		require.Nil(t, checkQuery(res))
+
+		require.Equal(t, Map{"analytics": false}, Analytics(nil, false))
+		require.Equal(t, Map{"enableABTest": false}, EnableABTest(nil, false))
+		require.Nil(t, checkQuery(EnableABTest(nil, true)))
+	}
+
+	t.Log("TestAnalytics: Check the validation of the parameters")
+	{
+		require.Nil(t, checkQuery(Map{"analyticsTags": "mobile,fr"}))
+		require.NotNil(t, checkQuery(Map{"analyticsTags": []string{"mobile", " "}}))
+		require.NotNil(t, checkQuery(Map{"analyticsTags": []string{strings.Repeat("a", 256)}}))
+		require.NotNil(t, checkQuery(Map{"analyticsTags": make([]string, 101)}))
+		require.NotNil(t, checkQuery(Map{"enableABTest": "true"}))
+	}
+
+	t.Log("TestAnalytics: Check the A/B test fields of the response")
+	{
+		var res QueryRes
+		require.Nil(t, json.Unmarshal([]byte(`{"hits":[],"abTestID":42,"abTestVariantID":2}`), &res))
+		require.Equal(t, 42, res.ABTestID)
+		require.Equal(t, 2, res.ABTestVariantID)
+	}
+}
@@ -0,0 +1,62 @@
+package algoliasearch
+
+import "strings"
+
+// readOnlyPOSTRoutes are the routes of the POST requests which do not modify
+// the application (searches, browses and retrievals). A `{}` segment matches
+// any single segment, e.g. the (escaped) name of an index, so that writes to
+// an index named after a route, e.g. "query", are not mistaken for reads.
+var readOnlyPOSTRoutes = [][]string{
+	splitRoute("/1/indexes/{}/query"),
+	splitRoute("/1/indexes/*/queries"),
+	splitRoute("/1/indexes/{}/browse"),
+	splitRoute("/1/indexes/{}/synonyms/search"),
+	splitRoute("/1/indexes/{}/rules/search"),
+	splitRoute("/1/indexes/{}/facets/{}/query"),
+	splitRoute("/1/indexes/*/objects"),
+	splitRoute("/1/dictionaries/{}/search"),
+	splitRoute("/1/clusters/mapping/search"),
+}
+
+// isReadOnlyRequest returns `true` if the request with the given HTTP
+// `method` and `path` does not modify the application, hence can be sent by
+// a read-only client (see WithReadOnly).
+func isReadOnlyRequest(method, path string) bool {
+	switch method {
+	case "GET", "HEAD":
+		return true
+	case "POST":
+		if n := strings.IndexByte(path, '?'); n >= 0 {
+			path = path[:n]
+		}
+		segments := splitRoute(path)
+		for _, route := range readOnlyPOSTRoutes {
+			if matchRoute(route, segments) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func splitRoute(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+// matchRoute returns `true` if the path `segments` have the same shape as the
+// `route` ones, `{}` matching any non-empty segment.
+func matchRoute(route, segments []string) bool {
+	if len(route) != len(segments) {
+		return false
+	}
+	for n, segment := range route {
+		if segment == "{}" && segments[n] != "" {
+			continue
+		}
+		if segment != segments[n] {
+			return false
+		}
+	}
+	return true
+}
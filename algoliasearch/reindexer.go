@@ -0,0 +1,193 @@
+package algoliasearch
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// defaultUpdatedAtAttribute is the attribute of the records holding the time
+// of their last update if none is specified.
+const defaultUpdatedAtAttribute = "updatedAt"
+
+// Source provides the records reindexed by a Reindexer. Next returns the next
+// record, which must have an `objectID`, along with the time of its last
+// update. It returns NoMoreHitsErr once all the records were returned.
+type Source interface {
+	Next() (object Object, updatedAt time.Time, err error)
+}
+
+// ReindexRes describes the changes applied by Reindexer.Run: the number of
+// records which were added, updated, deleted or left unchanged, along with
+// the responses of the batch requests sent to the index.
+type ReindexRes struct {
+	Added     int
+	Updated   int
+	Deleted   int
+	Unchanged int
+	Batches   BatchesRes
+}
+
+// Reindexer incrementally synchronizes an index with a Source: instead of
+// sending all the records again, only the new records and the ones whose
+// update time changed are written, while the records which are no longer
+// provided by the source are deleted. The update time of each record is
+// stored, as a Unix timestamp in seconds, in its `UpdatedAtAttribute`
+// attribute, which is compared with the one given by the source.
+type Reindexer struct {
+	index              Index
+	UpdatedAtAttribute string
+	ChunkSize          int
+}
+
+// NewReindexer returns a Reindexer synchronizing the `index`, whose records
+// hold their update time in the `updatedAt` attribute, and sending the
+// changes in chunks of 1000 operations.
+func NewReindexer(index Index) *Reindexer {
+	return &Reindexer{
+		index:              index,
+		UpdatedAtAttribute: defaultUpdatedAtAttribute,
+		ChunkSize:          batchChunkSize,
+	}
+}
+
+// Run browses the index to retrieve the update time of all its records,
+// then writes the records of the `source` which are new or were updated
+// since and finally deletes the records which were not returned by the
+// `source`. If the `source` fails, nothing is deleted. Once a batch request
+// fails, the following ones are not sent: the error is returned along with
+// the changes applied so far.
+func (r *Reindexer) Run(source Source) (ReindexRes, error) {
+	return r.RunWithRequestOptions(source, nil)
+}
+
+// RunWithRequestOptions is the same as Run but it also accepts extra
+// RequestOptions.
+func (r *Reindexer) RunWithRequestOptions(source Source, opts *RequestOptions) (res ReindexRes, err error) {
+	if r.ChunkSize <= 0 || r.UpdatedAtAttribute == "" {
+		err = fmt.Errorf("Reindexer should have a positive chunk size and an updatedAt attribute")
+		return
+	}
+
+	var live map[string]int64
+	if live, err = r.liveUpdateTimes(opts); err != nil {
+		return
+	}
+
+	seen := make(map[string]bool, len(live))
+	var operations []BatchOperation
+
+	for {
+		var object Object
+		var updatedAt time.Time
+		if object, updatedAt, err = source.Next(); err == NoMoreHitsErr {
+			break
+		} else if err != nil {
+			return
+		}
+
+		var objectID string
+		if objectID, err = object.ObjectID(); err != nil {
+			return
+		}
+		if seen[objectID] {
+			err = fmt.Errorf("Cannot reindex: objectID %q is returned twice by the source", objectID)
+			return
+		}
+		seen[objectID] = true
+
+		liveUpdatedAt, exists := live[objectID]
+		if exists && liveUpdatedAt == updatedAt.Unix() {
+			res.Unchanged++
+			continue
+		}
+
+		record := Object(duplicateMap(Map(object)))
+		record[r.UpdatedAtAttribute] = updatedAt.Unix()
+		operations = append(operations, BatchOperation{Action: ActionUpdateObject, Body: record})
+		if exists {
+			res.Updated++
+		} else {
+			res.Added++
+		}
+
+		if len(operations) == r.ChunkSize {
+			if err = r.send(&res, operations, opts); err != nil {
+				return
+			}
+			operations = nil
+		}
+	}
+	err = nil
+
+	// The deleted records are sorted to generate the batches in a
+	// deterministic order.
+	var deleted []string
+	for objectID := range live {
+		if !seen[objectID] {
+			deleted = append(deleted, objectID)
+		}
+	}
+	sort.Strings(deleted)
+
+	for _, objectID := range deleted {
+		operations = append(operations, BatchOperation{Action: ActionDeleteObject, Body: Map{"objectID": objectID}})
+		res.Deleted++
+
+		if len(operations) == r.ChunkSize {
+			if err = r.send(&res, operations, opts); err != nil {
+				return
+			}
+			operations = nil
+		}
+	}
+
+	if len(operations) > 0 {
+		err = r.send(&res, operations, opts)
+	}
+
+	return
+}
+
+// liveUpdateTimes browses the whole index and returns the update time of
+// its records, by objectID. The records without a numeric update time are
+// given a zero one, so that they are always rewritten.
+func (r *Reindexer) liveUpdateTimes(opts *RequestOptions) (map[string]int64, error) {
+	params := Map{
+		"attributesToRetrieve": []string{"objectID", r.UpdatedAtAttribute},
+	}
+
+	// A missing or empty index has no records.
+	it, err := r.index.BrowseAllWithRequestOptions(params, opts)
+	if err == NoMoreHitsErr || IsNotFound(err) {
+		return map[string]int64{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	live := make(map[string]int64)
+	for {
+		hit, err := it.Next()
+		if err == NoMoreHitsErr {
+			return live, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		objectID, ok := hit["objectID"].(string)
+		if !ok {
+			continue
+		}
+
+		updatedAt, _ := hit[r.UpdatedAtAttribute].(float64)
+		live[objectID] = int64(updatedAt)
+	}
+}
+
+// send sends the `operations` to the index in a single batch request and
+// records its response in `res`.
+func (r *Reindexer) send(res *ReindexRes, operations []BatchOperation, opts *RequestOptions) error {
+	batchRes, err := r.index.BatchWithRequestOptions(operations, opts)
+	res.Batches = append(res.Batches, batchRes)
+	return err
+}
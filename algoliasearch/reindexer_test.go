@@ -0,0 +1,93 @@
+package algoliasearch
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type sliceSource struct {
+	objects []Object
+	times   []time.Time
+}
+
+func (s *sliceSource) Next() (Object, time.Time, error) {
+	if len(s.objects) == 0 {
+		return nil, time.Time{}, NoMoreHitsErr
+	}
+	object, updatedAt := s.objects[0], s.times[0]
+	s.objects, s.times = s.objects[1:], s.times[1:]
+	return object, updatedAt, nil
+}
+
+func TestReindexer(t *testing.T) {
+	t.Log("TestReindexer: Start a server holding three records")
+	var batches [][]BatchOperation
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/1/indexes/products/browse":
+			w.Write([]byte(`{"hits":[
+				{"objectID":"unchanged","updatedAt":1000},
+				{"objectID":"updated","updatedAt":1000},
+				{"objectID":"deleted","updatedAt":1000}
+			]}`))
+		case "/1/indexes/products/batch":
+			var body struct {
+				Requests []BatchOperation `json:"requests"`
+			}
+			require.Nil(t, json.NewDecoder(r.Body).Decode(&body))
+			batches = append(batches, body.Requests)
+			w.Write([]byte(`{"taskID":1}`))
+		default:
+			t.Errorf("unexpected request [%s] %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	c := &client{transport: newTestTransport(server)}
+
+	t.Log("TestReindexer: Check that only the changes are sent")
+	{
+		source := &sliceSource{
+			objects: []Object{
+				{"objectID": "unchanged", "name": "one"},
+				{"objectID": "updated", "name": "two"},
+				{"objectID": "added", "name": "three"},
+			},
+			times: []time.Time{time.Unix(1000, 0), time.Unix(2000, 0), time.Unix(3000, 0)},
+		}
+
+		r := NewReindexer(c.InitIndex("products"))
+		r.ChunkSize = 2
+		res, err := r.Run(source)
+		require.Nil(t, err)
+		require.Equal(t, 1, res.Added)
+		require.Equal(t, 1, res.Updated)
+		require.Equal(t, 1, res.Deleted)
+		require.Equal(t, 1, res.Unchanged)
+		require.Len(t, res.Batches, 2)
+
+		require.Len(t, batches, 2)
+		require.Equal(t, "updateObject", batches[0][0].Action)
+		require.Equal(t, map[string]interface{}{"objectID": "updated", "name": "two", "updatedAt": 2000.0}, batches[0][0].Body)
+		require.Equal(t, "updateObject", batches[0][1].Action)
+		require.Equal(t, map[string]interface{}{"objectID": "deleted"}, batches[1][0].Body)
+		require.Equal(t, "deleteObject", batches[1][0].Action)
+	}
+
+	t.Log("TestReindexer: Check that nothing is deleted if the source is invalid")
+	{
+		batches = nil
+		source := &sliceSource{
+			objects: []Object{{"name": "no objectID"}},
+			times:   []time.Time{time.Unix(1000, 0)},
+		}
+
+		_, err := NewReindexer(c.InitIndex("products")).Run(source)
+		require.NotNil(t, err)
+		require.Len(t, batches, 0)
+	}
+}
@@ -0,0 +1,178 @@
+package algoliasearch
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Replica is an entry of the `replicas` setting of an index. A virtual
+// replica (also known as relevant sort replica) shares the records and the
+// ranking of its primary index and only sorts the most relevant hits.
+type Replica struct {
+	Name    string
+	Virtual bool
+}
+
+// NewVirtualReplica returns the entry of the `replicas` setting declaring
+// `name` as a virtual replica, i.e. `virtual(name)`.
+func NewVirtualReplica(name string) string {
+	return Replica{Name: name, Virtual: true}.String()
+}
+
+// ParseReplica parses an entry of the `replicas` setting, which is either
+// the name of a standard replica or `virtual(name)` for a virtual one.
+func ParseReplica(replica string) Replica {
+	if strings.HasPrefix(replica, "virtual(") && strings.HasSuffix(replica, ")") {
+		return Replica{
+			Name:    replica[len("virtual(") : len(replica)-1],
+			Virtual: true,
+		}
+	}
+	return Replica{Name: replica}
+}
+
+// String returns the form of the replica expected by the `replicas` setting.
+func (r Replica) String() string {
+	if r.Virtual {
+		return "virtual(" + r.Name + ")"
+	}
+	return r.Name
+}
+
+// ParseReplicas returns the typed form of the `replicas` setting.
+func ParseReplicas(replicas []string) []Replica {
+	res := make([]Replica, len(replicas))
+	for n, r := range replicas {
+		res[n] = ParseReplica(r)
+	}
+	return res
+}
+
+// checkRelevancyStrictness checks that the `relevancyStrictness` setting or
+// query parameter is a percentage.
+func checkRelevancyStrictness(v interface{}) error {
+	strictness, ok := v.(int)
+	if !ok {
+		return invalidType("relevancyStrictness", "int")
+	}
+	if strictness < 0 || strictness > 100 {
+		return fmt.Errorf("`relevancyStrictness` should be between 0 and 100, got %d", strictness)
+	}
+	return nil
+}
+
+// updateReplicas performs a read-modify-write of the `replicas` setting of
+// the index: `update` computes the new replicas from the current ones. As
+// the API has no conditional write, a concurrent modification cannot be
+// prevented. It is detected instead by reading the setting once again after
+// the write is published: ReplicasModifiedErr is returned, along with the
+// result of the write, if it differs from the written replicas.
+func (i *index) updateReplicas(update func(replicas []string) ([]string, error), opts *RequestOptions) (res UpdateTaskRes, err error) {
+	settings, err := i.GetSettingsWithRequestOptions(opts)
+	if err != nil {
+		return
+	}
+
+	replicas, err := update(settings.Replicas)
+	if err != nil {
+		return
+	}
+
+	if res, err = i.SetSettingsWithRequestOptions(Map{"replicas": replicas}, opts); err != nil {
+		return
+	}
+
+	if err = i.WaitTaskWithRequestOptions(res.TaskID, opts); err != nil {
+		return
+	}
+
+	current, err := i.GetSettingsWithRequestOptions(opts)
+	if err != nil {
+		return
+	}
+
+	if !sameStrings(replicas, current.Replicas) {
+		err = ReplicasModifiedErr
+	}
+	return
+}
+
+func (i *index) AddReplica(name string, settings Map) (res UpdateTaskRes, err error) {
+	return i.AddReplicaWithRequestOptions(name, settings, nil)
+}
+
+func (i *index) AddReplicaWithRequestOptions(name string, settings Map, opts *RequestOptions) (res UpdateTaskRes, err error) {
+	// The replicas are read and written without the index prefix
+	replica := ParseReplica(name)
+	if replica.Name == "" || replica.Name == i.unprefixedName() {
+		err = fmt.Errorf("invalid replica name %q", name)
+		return
+	}
+	name = replica.String()
+
+	if settings != nil {
+		if err = checkSettings(settings); err != nil {
+			return
+		}
+	}
+
+	res, err = i.updateReplicas(func(replicas []string) ([]string, error) {
+		for _, r := range replicas {
+			if ParseReplica(r).Name == replica.Name {
+				return nil, fmt.Errorf("%s is already a replica of %s", replica.Name, i.unprefixedName())
+			}
+		}
+		return append(replicas, name), nil
+	}, opts)
+	if err != nil || settings == nil {
+		return
+	}
+
+	// The replica is created asynchronously by the engine, hence its
+	// settings can only be pushed once the primary task, already waited
+	// for by updateReplicas, is over.
+	replicaIndex := i.client.InitIndex(replica.Name)
+	replicaRes, err := replicaIndex.SetSettingsWithRequestOptions(settings, opts)
+	if err != nil {
+		return
+	}
+
+	err = replicaIndex.WaitTaskWithRequestOptions(replicaRes.TaskID, opts)
+	return
+}
+
+func (i *index) RemoveReplica(name string) (res UpdateTaskRes, err error) {
+	return i.RemoveReplicaWithRequestOptions(name, nil)
+}
+
+func (i *index) RemoveReplicaWithRequestOptions(name string, opts *RequestOptions) (res UpdateTaskRes, err error) {
+	name = ParseReplica(name).Name
+
+	return i.updateReplicas(func(replicas []string) ([]string, error) {
+		updated := make([]string, 0, len(replicas))
+		for _, r := range replicas {
+			if ParseReplica(r).Name != name {
+				updated = append(updated, r)
+			}
+		}
+
+		if len(updated) == len(replicas) {
+			return nil, fmt.Errorf("%s is not a replica of %s", name, i.unprefixedName())
+		}
+		return updated, nil
+	}, opts)
+}
+
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for n := range a {
+		if a[n] != b[n] {
+			return false
+		}
+	}
+
+	return true
+}
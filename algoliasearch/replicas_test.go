@@ -0,0 +1,135 @@
+package algoliasearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newReplicasTestServer returns a server storing the settings of the
+// indices. If `concurrentReplicas` is not nil, it is used as the replicas of
+// the primary index after the first settings retrieval, as if the settings
+// were concurrently modified.
+func newReplicasTestServer(replicas, concurrentReplicas []string) (*httptest.Server, map[string]Map) {
+	var mu sync.Mutex
+	settings := map[string]Map{"products": {"replicas": replicas}}
+	reads := 0
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/1/indexes/"), "/")
+		name := parts[0]
+
+		switch {
+		case r.Method == "GET" && parts[1] == "settings":
+			reads++
+			if reads == 2 && concurrentReplicas != nil {
+				settings[name]["replicas"] = concurrentReplicas
+			}
+			json.NewEncoder(w).Encode(settings[name])
+		case r.Method == "PUT" && parts[1] == "settings":
+			var s Map
+			json.NewDecoder(r.Body).Decode(&s)
+			settings[name] = s
+			fmt.Fprint(w, `{"taskID": 1, "updatedAt": "2017-01-01T00:00:00Z"}`)
+		case r.Method == "GET" && parts[1] == "task":
+			fmt.Fprint(w, `{"status": "published", "pendingTask": false}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not found"}`)
+		}
+	}))
+
+	return server, settings
+}
+
+func TestReplicas(t *testing.T) {
+	t.Log("TestReplicas: Add a replica with its settings")
+	{
+		server, settings := newReplicasTestServer([]string{"products_price_asc"}, nil)
+		defer server.Close()
+		c := &client{transport: newTestTransport(server)}
+
+		_, err := c.InitIndex("products").AddReplica("products_price_desc", Map{"ranking": []string{"desc(price)"}})
+		require.Nil(t, err)
+		require.Equal(t, []interface{}{"products_price_asc", "products_price_desc"}, settings["products"]["replicas"])
+		require.Equal(t, []interface{}{"desc(price)"}, settings["products_price_desc"]["ranking"])
+
+		_, err = c.InitIndex("products").AddReplica("products_price_asc", nil)
+		require.NotNil(t, err, "should not add an existing replica")
+	}
+
+	t.Log("TestReplicas: Remove a replica")
+	{
+		server, settings := newReplicasTestServer([]string{"products_price_asc", "products_price_desc"}, nil)
+		defer server.Close()
+		c := &client{transport: newTestTransport(server)}
+
+		_, err := c.InitIndex("products").RemoveReplica("products_price_asc")
+		require.Nil(t, err)
+		require.Equal(t, []interface{}{"products_price_desc"}, settings["products"]["replicas"])
+
+		_, err = c.InitIndex("products").RemoveReplica("products_price_asc")
+		require.NotNil(t, err, "should not remove an unknown replica")
+	}
+
+	t.Log("TestReplicas: Detect concurrent modifications")
+	{
+		server, settings := newReplicasTestServer([]string{"products_price_asc"}, []string{"products_price_asc", "products_newest"})
+		defer server.Close()
+		c := &client{transport: newTestTransport(server)}
+
+		_, err := c.InitIndex("products").AddReplica("products_price_desc", nil)
+		require.Equal(t, ReplicasModifiedErr, err)
+		require.Equal(t, []string{"products_price_asc", "products_newest"}, settings["products"]["replicas"])
+	}
+}
+
+func TestVirtualReplicas(t *testing.T) {
+	t.Log("TestVirtualReplicas: Check the parsing of the replicas setting")
+	{
+		require.Equal(t, "virtual(products_relevant_price)", NewVirtualReplica("products_relevant_price"))
+		require.Equal(t, []Replica{
+			{Name: "products_price_asc"},
+			{Name: "products_relevant_price", Virtual: true},
+		}, ParseReplicas([]string{"products_price_asc", "virtual(products_relevant_price)"}))
+		require.Equal(t, "virtual(a)", ParseReplica("virtual(a)").String())
+	}
+
+	t.Log("TestVirtualReplicas: Add and remove a virtual replica")
+	{
+		server, settings := newReplicasTestServer([]string{"products_price_asc"}, nil)
+		defer server.Close()
+		c := &client{transport: newTestTransport(server)}
+		i := c.InitIndex("products")
+
+		_, err := i.AddReplica(NewVirtualReplica("products_relevant_price"), Map{"relevancyStrictness": 90})
+		require.Nil(t, err)
+		require.Equal(t, []interface{}{"products_price_asc", "virtual(products_relevant_price)"}, settings["products"]["replicas"])
+		require.Equal(t, float64(90), settings["products_relevant_price"]["relevancyStrictness"])
+
+		_, err = i.AddReplica("products_relevant_price", nil)
+		require.NotNil(t, err, "should not add a replica twice, even as a standard one")
+
+		_, err = i.RemoveReplica("products_relevant_price")
+		require.Nil(t, err)
+		require.Equal(t, []interface{}{"products_price_asc"}, settings["products"]["replicas"])
+	}
+
+	t.Log("TestVirtualReplicas: Check the relevancyStrictness parameter")
+	{
+		require.Nil(t, checkQuery(Map{"relevancyStrictness": 0}))
+		require.Nil(t, checkSettings(Map{"relevancyStrictness": 100}))
+		require.NotNil(t, checkQuery(Map{"relevancyStrictness": 101}))
+		require.NotNil(t, checkQuery(Map{"relevancyStrictness": "90"}))
+		require.NotNil(t, checkSettings(Map{"relevancyStrictness": -1}))
+	}
+}
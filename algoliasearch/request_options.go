@@ -1,7 +1,55 @@
 package algoliasearch
 
+import (
+	"net/http"
+	"time"
+)
+
 type RequestOptions struct {
 	ForwardedFor   string
 	ExtraHeaders   map[string]string
 	ExtraUrlParams map[string]string
+
+	// UserToken identifies the end user the request is made for, for
+	// personalization and analytics purposes. It overrides the user token
+	// set with Client.SetUserToken, if any.
+	UserToken string
+
+	// DryRun makes the destructive operations (e.g. Index.Clear or
+	// Index.DeleteBy) only report what they would do, through a
+	// `*DryRunErr`, instead of performing it (see WithDryRun).
+	DryRun bool
+
+	// Duplicates controls how Index.AddObjects and Index.UpdateObjects
+	// handle the records sharing the same objectID (see DuplicatesMode).
+	Duplicates DuplicatesMode
+
+	// Timeout is the deadline of the call, including the retries on the
+	// other hosts. It overrides the `Total` timeout set with
+	// Client.SetTimeouts, if any.
+	Timeout time.Duration
+
+	// OnResponse, if non-nil, is called with the metadata of each HTTP
+	// response received for the call, including the ones of the attempts
+	// which are retried (e.g. 5XX responses), e.g. to log which host served
+	// a slow query. It is not called for the attempts which failed without
+	// a response (e.g. network errors).
+	OnResponse func(ResponseMetadata)
+
+	// WaitSchedule, if non-nil, is the polling schedule used by the calls
+	// waiting for tasks (e.g. Index.WaitTask). It overrides the one set with
+	// Client.SetWaitSchedule.
+	WaitSchedule *WaitSchedule
+}
+
+// ResponseMetadata describes an HTTP response received from the API (see
+// RequestOptions.OnResponse). `Attempt` is the number of times the request
+// was sent so far, starting at 1, and `Duration` the time spent on this
+// attempt.
+type ResponseMetadata struct {
+	Host       string
+	StatusCode int
+	Header     http.Header
+	Attempt    int
+	Duration   time.Duration
 }
@@ -0,0 +1,80 @@
+package algoliasearch
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Requester sends the HTTP requests of a Client (see Client.SetRequester).
+// It is implemented by `*http.Client` as well as by most HTTP client
+// wrappers (retrying, instrumented, etc.).
+type Requester interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// setHTTPClient lets the user (through the exported `Client.SetHTTPClient`)
+// replace the HTTP client sending the requests.
+func (t *Transport) setHTTPClient(client *http.Client) {
+	t.httpClient = client
+	t.ownsHTTPClient = false
+	t.requester = nil
+}
+
+// setRequester lets the user (through the exported `Client.SetRequester`)
+// send the requests with a custom Requester. The transport-level timeouts
+// (connect and write) are then left to the requester while the read timeout
+// and the deadline of the calls are still enforced through the context of
+// the requests.
+func (t *Transport) setRequester(r Requester) {
+	if client, ok := r.(*http.Client); ok {
+		t.setHTTPClient(client)
+		return
+	}
+	t.requester = r
+}
+
+// do sends the `req` request with the Requester of the transport, if any, or
+// with its HTTP client otherwise.
+func (t *Transport) do(req *http.Request) (*http.Response, error) {
+	requester := t.requester
+	if requester == nil {
+		if _, ok := t.httpClient.Transport.(*http.Transport); ok {
+			return t.httpClient.Do(req)
+		}
+		requester = t.httpClient
+	}
+
+	if t.readTimeout <= 0 {
+		return requester.Do(req)
+	}
+
+	// As the requester, or the underlying RoundTripper of the HTTP client,
+	// may not support `ResponseHeaderTimeout`, the request is cancelled if
+	// the response headers are not received in time.
+	ctx, cancel := context.WithCancel(req.Context())
+	timer := time.AfterFunc(t.readTimeout, cancel)
+	res, err := requester.Do(req.WithContext(ctx))
+	timer.Stop()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	res.Body = &cancelOnCloseBody{ReadCloser: res.Body, cancel: cancel}
+	return res, nil
+}
+
+// cancelOnCloseBody is a response body releasing the context of its request
+// once closed.
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
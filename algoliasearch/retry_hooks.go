@@ -0,0 +1,27 @@
+package algoliasearch
+
+// HostFailure describes an attempt of a request which failed on `Host`
+// because of a network error or a server error (5XX), as reported to the
+// hooks set with Client.SetOnRetry and Client.SetOnHostFail. Client errors
+// (4XX) are not reported, even though the request is retried on the next
+// host, as they are not caused by the host. `Attempt` is the number of times
+// the request was sent so far, starting at 1, and `NextHost` the host the
+// request is retried on, if any.
+type HostFailure struct {
+	Host     string
+	NextHost string
+	Err      error
+	Attempt  int
+}
+
+// setOnRetry lets the user (through the exported `Client.SetOnRetry`) be
+// notified each time a request is retried on another host.
+func (t *Transport) setOnRetry(hook func(HostFailure)) {
+	t.onRetry = hook
+}
+
+// setOnHostFail lets the user (through the exported `Client.SetOnHostFail`)
+// be notified each time a host is marked as down.
+func (t *Transport) setOnHostFail(hook func(HostFailure)) {
+	t.onHostFail = hook
+}
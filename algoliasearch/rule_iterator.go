@@ -49,7 +49,10 @@ func (it *RuleIterator) loadNextPage() error {
 	it.pos = -1
 	it.page++
 
-	res, err := it.index.SearchRules(Map{"query": ""})
+	res, err := it.index.SearchRules(SearchRulesParams{
+		Page:        it.page,
+		HitsPerPage: it.hitsPerPage,
+	})
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,181 @@
+package algoliasearch
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultScopedKeyValidity is the validity of the API keys generated by
+	// Client.ScopedClient if none is specified.
+	defaultScopedKeyValidity = time.Hour
+
+	// scopedKeyTimeout is the maximum time spent waiting for a newly
+	// generated scoped API key to be usable.
+	scopedKeyTimeout = 2 * time.Minute
+)
+
+// scopedClients caches the clients returned by Client.ScopedClient, by ACL
+// and parameters.
+type scopedClients struct {
+	sync.Mutex
+	clients map[string]*scopedClient
+}
+
+type scopedClient struct {
+	client    Client
+	key       string
+	expiresAt time.Time
+	validity  time.Duration
+}
+
+// stale returns `true` if the key of the scoped client expired or is about
+// to expire, i.e. if less than a tenth of its validity remains.
+func (s *scopedClient) stale() bool {
+	return s.expiresAt.Sub(time.Now()) < s.validity/10
+}
+
+// scopedClientCacheKey returns the key identifying the scoped clients
+// generated for the given `acl` and `params` (see KeyParams.toMap).
+func scopedClientCacheKey(acl []string, params Map) string {
+	sorted := append([]string(nil), acl...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",") + "?" + encodeMap(params)
+}
+
+func (c *client) ScopedClient(params KeyParams) (Client, error) {
+	if params.Validity == 0 {
+		params.Validity = defaultScopedKeyValidity
+	}
+	if params.Description == "" {
+		params.Description = "Scoped key generated by the Go API client"
+	}
+
+	if err := checkKeyParams(params); err != nil {
+		return nil, err
+	}
+
+	// The key is restricted to the indexes the scoped client deals with,
+	// hence to the prefixed ones.
+	if c.indexPrefix != "" && len(params.Indexes) > 0 {
+		prefixed := make([]string, len(params.Indexes))
+		for n, name := range params.Indexes {
+			prefixed[n] = c.prefixedIndexName(name)
+		}
+		params.Indexes = prefixed
+	}
+
+	acl, req := params.toMap()
+	validity := time.Duration(validitySeconds(params.Validity)) * time.Second
+	cacheKey := scopedClientCacheKey(acl, req)
+
+	c.scoped.Lock()
+	cached, ok := c.scoped.clients[cacheKey]
+	c.scoped.Unlock()
+
+	if ok && !cached.stale() {
+		return cached.client, nil
+	}
+
+	// The lock is not held while the key is generated, which can take up to
+	// scopedKeyTimeout, so that the other scoped clients remain available.
+	start := time.Now()
+	res, err := c.AddAPIKey(acl, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = c.waitAPIKey(res.Key); err != nil {
+		return nil, err
+	}
+
+	s := &scopedClient{
+		client:    c.newClientWithKey(res.Key),
+		key:       res.Key,
+		expiresAt: start.Add(validity),
+		validity:  validity,
+	}
+
+	c.scoped.Lock()
+	current, ok := c.scoped.clients[cacheKey]
+	if ok && !current.stale() {
+		// A key was concurrently generated for the same parameters: it is
+		// used instead and the new one is deleted right away.
+		c.scoped.Unlock()
+		c.DeleteAPIKey(res.Key)
+		return current.client, nil
+	}
+	if c.scoped.clients == nil {
+		c.scoped.clients = make(map[string]*scopedClient)
+	}
+	c.scoped.clients[cacheKey] = s
+	c.scoped.Unlock()
+
+	// The previous key is deleted right away instead of waiting for the API
+	// to remove it once expired.
+	if ok {
+		c.DeleteAPIKey(current.key)
+	}
+
+	return s.client, nil
+}
+
+func (c *client) ReleaseScopedClients() error {
+	c.scoped.Lock()
+	defer c.scoped.Unlock()
+
+	var failed []string
+	for cacheKey, s := range c.scoped.clients {
+		if _, err := c.DeleteAPIKey(s.key); err != nil && !IsNotFound(err) {
+			failed = append(failed, err.Error())
+			continue
+		}
+		delete(c.scoped.clients, cacheKey)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("Cannot delete %d scoped keys: %s", len(failed), strings.Join(failed, "; "))
+	}
+
+	return nil
+}
+
+// newClientWithKey returns a new client sharing the configuration of the
+// client (application ID, hosts, timeouts, HTTP client, extra headers, index
+// prefix, etc.) but authenticated with the given API `key`.
+func (c *client) newClientWithKey(key string) Client {
+	return &client{
+		dryRun:        c.dryRun,
+		dryRunLog:     c.dryRunLog,
+		indexPrefix:   c.indexPrefix,
+		preSearchHook: c.preSearchHook,
+		readOnly:      c.readOnly,
+		transport:     c.transport.withAPIKey(key),
+		waitSched:     c.waitSched,
+	}
+}
+
+// waitAPIKey waits until the given API `key` can be retrieved, i.e. until it
+// is usable.
+func (c *client) waitAPIKey(key string) (err error) {
+	start := time.Now()
+	sleepDuration := 100 * time.Millisecond
+
+	for {
+		if _, err = c.GetAPIKey(key); err == nil || !IsNotFound(err) {
+			return
+		}
+
+		if time.Since(start) > scopedKeyTimeout {
+			return fmt.Errorf("Scoped API key not available after %s: %s", scopedKeyTimeout, err)
+		}
+
+		time.Sleep(sleepDuration)
+		if sleepDuration < 2*time.Second {
+			sleepDuration *= 2
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package algoliasearch
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// scrubber removes a fixed set of attributes from the records returned by the
+// API. Nested attributes are specified using the dot notation (e.g.
+// `billing.iban`).
+type scrubber struct {
+	paths [][]string
+}
+
+func newScrubber(attributes []string) *scrubber {
+	if len(attributes) == 0 {
+		return nil
+	}
+
+	s := &scrubber{}
+	for _, attr := range attributes {
+		s.paths = append(s.paths, strings.Split(attr, "."))
+	}
+	return s
+}
+
+// scrubHits removes the scrubbed attributes from all the given `hits`.
+func (s *scrubber) scrubHits(hits []Map) {
+	if s == nil {
+		return
+	}
+
+	for _, hit := range hits {
+		s.scrub(hit)
+	}
+}
+
+// scrubRawHits removes the scrubbed attributes from all the given raw `hits`.
+// As each hit is decoded and encoded again, the hits are left untouched if no
+// attribute is scrubbed.
+func (s *scrubber) scrubRawHits(hits []json.RawMessage) error {
+	if s == nil {
+		return nil
+	}
+
+	for n, raw := range hits {
+		var hit Map
+		if err := json.Unmarshal(raw, &hit); err != nil {
+			return err
+		}
+		s.scrub(hit)
+
+		data, err := json.Marshal(hit)
+		if err != nil {
+			return err
+		}
+		hits[n] = data
+	}
+
+	return nil
+}
+
+// scrubObjects removes the scrubbed attributes from all the given `objects`.
+func (s *scrubber) scrubObjects(objects []Object) {
+	if s == nil {
+		return
+	}
+
+	for _, object := range objects {
+		s.scrub(object)
+	}
+}
+
+// scrub removes the scrubbed attributes from the given `record`, including
+// from its highlighting and snippeting results if any.
+func (s *scrubber) scrub(record map[string]interface{}) {
+	if s == nil || record == nil {
+		return
+	}
+
+	for _, path := range s.paths {
+		removePath(record, path)
+
+		for _, key := range []string{"_highlightResult", "_snippetResult"} {
+			if m, ok := record[key].(map[string]interface{}); ok {
+				removePath(m, path)
+			}
+		}
+	}
+}
+
+// removePath deletes the attribute identified by the `path` from the `m` map,
+// walking through nested objects.
+func removePath(m map[string]interface{}, path []string) {
+	if len(path) == 1 {
+		delete(m, path[0])
+		return
+	}
+
+	switch v := m[path[0]].(type) {
+	case map[string]interface{}:
+		removePath(v, path[1:])
+	case []interface{}:
+		for _, item := range v {
+			if nested, ok := item.(map[string]interface{}); ok {
+				removePath(nested, path[1:])
+			}
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package algoliasearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScrubber(t *testing.T) {
+	s := newScrubber([]string{"email", "billing.iban"})
+
+	hits := []Map{
+		{
+			"name":    "John",
+			"email":   "john@example.com",
+			"billing": map[string]interface{}{"iban": "FR76", "country": "FR"},
+			"_highlightResult": map[string]interface{}{
+				"name":  map[string]interface{}{"value": "John"},
+				"email": map[string]interface{}{"value": "john@example.com"},
+			},
+		},
+	}
+
+	t.Log("TestScrubber: Scrub the hits")
+	s.scrubHits(hits)
+
+	require.Equal(t, "John", hits[0]["name"])
+	require.NotContains(t, hits[0], "email")
+	require.Equal(t, map[string]interface{}{"country": "FR"}, hits[0]["billing"])
+	require.NotContains(t, hits[0]["_highlightResult"], "email")
+	require.Contains(t, hits[0]["_highlightResult"], "name")
+
+	t.Log("TestScrubber: Check that a nil scrubber is a no-op")
+	var noop *scrubber
+	objects := []Object{{"email": "john@example.com"}}
+	noop.scrubObjects(objects)
+	require.Contains(t, objects[0], "email")
+	require.Nil(t, newScrubber(nil))
+}
@@ -0,0 +1,35 @@
+package algoliasearch
+
+import "time"
+
+// PreSearchHook is a function called before every search query sent by a
+// Client, typically used to call an external service (embedding, intent
+// detection, etc.) deriving extra query parameters from the query itself. It
+// receives the name of the targeted index, the query and a copy of the query
+// parameters. The returned parameters (such as `optionalFilters` or
+// `ruleContexts`) are merged into the query parameters, overriding the
+// existing ones. If a non-nil error is returned, the search is aborted and the
+// error is returned to the caller.
+type PreSearchHook func(indexName, query string, params Map) (Map, error)
+
+// runPreSearchHook applies the `hook`, if non-nil, on the given `params` whose
+// `query` field should already be set. The merged parameters are returned
+// along with the time spent in the hook.
+func runPreSearchHook(hook PreSearchHook, indexName, query string, params Map) (Map, time.Duration, error) {
+	if hook == nil {
+		return params, 0, nil
+	}
+
+	start := time.Now()
+	extra, err := hook(indexName, query, duplicateMap(params))
+	duration := time.Since(start)
+	if err != nil {
+		return nil, duration, err
+	}
+
+	for k, v := range extra {
+		params[k] = v
+	}
+
+	return params, duration, nil
+}
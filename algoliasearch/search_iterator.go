@@ -0,0 +1,86 @@
+package algoliasearch
+
+import "fmt"
+
+// SearchIterator is used by Index.SearchAll to iterate over all the hits of
+// a search query, page after page.
+type SearchIterator interface {
+	// Next returns the next hit each time it is called. Subsequent pages of
+	// results are automatically loaded and an error is returned if a problem
+	// occurs. Once the last hit was returned, NoMoreHitsErr is returned.
+	Next() (res Map, err error)
+
+	// Res returns the response of the last page loaded, e.g. to retrieve the
+	// total number of hits or the facets.
+	Res() QueryRes
+}
+
+type searchIterator struct {
+	index  *index
+	opts   *RequestOptions
+	page   QueryRes
+	params Map
+	pos    int
+	query  string
+}
+
+// newSearchIterator instantiates a SearchIterator searching for `query` in
+// the `index` according to the given `params`. The first page of results is
+// loaded right away.
+func newSearchIterator(i *index, query string, params Map, opts *RequestOptions) (it *searchIterator, err error) {
+	for _, k := range []string{"offset", "length"} {
+		if _, ok := params[k]; ok {
+			err = fmt.Errorf("Cannot search all the pages: `%s` cannot be used with SearchAll", k)
+			return
+		}
+	}
+
+	it = &searchIterator{
+		index:  i,
+		opts:   opts,
+		params: duplicateMap(params),
+		query:  query,
+	}
+
+	page, _ := it.params["page"].(int)
+	err = it.loadPage(page)
+	return
+}
+
+func (it *searchIterator) Next() (res Map, err error) {
+	if it.pos == len(it.page.Hits) {
+		// The number of pages returned by the engine is already capped by
+		// the `paginationLimitedTo` setting.
+		if len(it.page.Hits) == 0 || it.page.Page+1 >= it.page.NbPages {
+			err = NoMoreHitsErr
+			return
+		}
+
+		if err = it.loadPage(it.page.Page + 1); err != nil {
+			return
+		}
+
+		if len(it.page.Hits) == 0 {
+			err = NoMoreHitsErr
+			return
+		}
+	}
+
+	res = it.page.Hits[it.pos]
+	it.pos++
+	return
+}
+
+func (it *searchIterator) Res() QueryRes {
+	return it.page
+}
+
+// loadPage loads the given `page` of results.
+func (it *searchIterator) loadPage(page int) (err error) {
+	it.params["page"] = page
+	if it.page, err = it.index.SearchWithRequestOptions(it.query, it.params, it.opts); err != nil {
+		return
+	}
+	it.pos = 0
+	return
+}
@@ -0,0 +1,54 @@
+package algoliasearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchIterator(t *testing.T) {
+	t.Log("TestSearchIterator: Start a server returning three pages of two hits")
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Params string `json:"params"`
+		}
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&body))
+		params, err := url.ParseQuery(body.Params)
+		require.Nil(t, err)
+
+		page := params.Get("page")
+		fmt.Fprintf(w, `{"hits":[{"objectID":"%s-1"},{"objectID":"%s-2"}],"page":%s,"nbPages":3,"nbHits":6}`, page, page, page)
+	}))
+	defer server.Close()
+	index := (&client{transport: newTestTransport(server)}).InitIndex("products")
+
+	t.Log("TestSearchIterator: Check that all the pages are loaded")
+	{
+		it, err := index.SearchAll("phone", Map{"page": 1})
+		require.Nil(t, err)
+
+		var objectIDs []string
+		for {
+			hit, err := it.Next()
+			if err == NoMoreHitsErr {
+				break
+			}
+			require.Nil(t, err)
+			objectIDs = append(objectIDs, hit["objectID"].(string))
+		}
+
+		require.Equal(t, []string{"1-1", "1-2", "2-1", "2-2"}, objectIDs)
+		require.Equal(t, 6, it.Res().NbHits)
+	}
+
+	t.Log("TestSearchIterator: Check that offset and length are rejected")
+	{
+		_, err := index.SearchAll("phone", Map{"offset": 10})
+		require.NotNil(t, err)
+	}
+}
@@ -5,6 +5,9 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
 )
 
 // GenerateSecuredAPIKey generates a public API key intended to restrict access
@@ -34,3 +37,76 @@ func GenerateSecuredAPIKey(apiKey string, params Map) (key string, err error) {
 	key = base64.StdEncoding.EncodeToString([]byte(securedKey + message))
 	return
 }
+
+// SecuredKeyRestrictions are the restrictions embedded in a secured API key
+// by GenerateSecuredAPIKeyWithRestrictions. All the fields are optional:
+//   - `Filters` restricts the records which can be retrieved
+//   - `ValidUntil` is the expiration date of the key
+//   - `RestrictIndices` lists the indices the key can query (`*` wildcards
+//     are accepted)
+//   - `RestrictSources` lists the IPv4 networks (e.g. `192.168.1.0/24`)
+//     the key can be used from
+//   - `Referers` lists the allowed referers (`*` wildcards are accepted)
+//   - `UserToken` identifies the user the key is generated for, which is
+//     used to rate-limit the queries per user instead of per IP
+//   - `SearchParams` are any other query parameters enforced on the
+//     queries made with the key
+type SecuredKeyRestrictions struct {
+	Filters         string
+	ValidUntil      time.Time
+	RestrictIndices []string
+	RestrictSources []string
+	Referers        []string
+	UserToken       string
+	SearchParams    Map
+}
+
+// toMap returns the restrictions as the parameters of GenerateSecuredAPIKey.
+// An error is returned if one of the `SearchParams` conflicts with a typed
+// field.
+func (r SecuredKeyRestrictions) toMap() (Map, error) {
+	params := duplicateMap(r.SearchParams)
+
+	set := func(k string, v interface{}) error {
+		if _, ok := params[k]; ok {
+			return fmt.Errorf("`%s` cannot be set both as a field and as a search parameter of the restrictions", k)
+		}
+		params[k] = v
+		return nil
+	}
+
+	var err error
+	if r.Filters != "" {
+		err = set("filters", r.Filters)
+	}
+	if err == nil && !r.ValidUntil.IsZero() {
+		err = set("validUntil", int(r.ValidUntil.Unix()))
+	}
+	if err == nil && len(r.RestrictIndices) > 0 {
+		err = set("restrictIndices", strings.Join(r.RestrictIndices, ","))
+	}
+	if err == nil && len(r.RestrictSources) > 0 {
+		err = set("restrictSources", strings.Join(r.RestrictSources, ";"))
+	}
+	if err == nil && len(r.Referers) > 0 {
+		err = set("referers", r.Referers)
+	}
+	if err == nil && r.UserToken != "" {
+		err = set("userToken", r.UserToken)
+	}
+
+	return params, err
+}
+
+// GenerateSecuredAPIKeyWithRestrictions is the same as GenerateSecuredAPIKey
+// but the restrictions of the key are given as typed fields, which are
+// encoded as expected by the API (e.g. the indices as a comma-separated list
+// and the expiration date as a UNIX timestamp).
+func GenerateSecuredAPIKeyWithRestrictions(apiKey string, restrictions SecuredKeyRestrictions) (string, error) {
+	params, err := restrictions.toMap()
+	if err != nil {
+		return "", err
+	}
+
+	return GenerateSecuredAPIKey(apiKey, params)
+}
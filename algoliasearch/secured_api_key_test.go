@@ -3,6 +3,9 @@ package algoliasearch
 import (
 	"os"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
 )
 
 func TestSecuredApiKeyGeneration(t *testing.T) {
@@ -64,3 +67,50 @@ func TestSecuredApiKeyGeneration(t *testing.T) {
 		}
 	}
 }
+
+func TestSecuredKeyRestrictions(t *testing.T) {
+	t.Log("TestSecuredKeyRestrictions: Check the encoding of the restrictions")
+	{
+		restrictions := SecuredKeyRestrictions{
+			Filters:         "visible_by:group/42",
+			ValidUntil:      time.Unix(1481901339, 0),
+			RestrictIndices: []string{"products", "dev_*"},
+			RestrictSources: []string{"192.168.1.0/24", "10.0.0.1"},
+			Referers:        []string{"https://algolia.com/*"},
+			UserToken:       "user42",
+			SearchParams:    Map{"hitsPerPage": 10},
+		}
+
+		params, err := restrictions.toMap()
+		require.Nil(t, err)
+		require.Equal(t, Map{
+			"filters":         "visible_by:group/42",
+			"validUntil":      1481901339,
+			"restrictIndices": "products,dev_*",
+			"restrictSources": "192.168.1.0/24;10.0.0.1",
+			"referers":        []string{"https://algolia.com/*"},
+			"userToken":       "user42",
+			"hitsPerPage":     10,
+		}, params)
+
+		expected, err := GenerateSecuredAPIKey("apikey", params)
+		require.Nil(t, err)
+		key, err := GenerateSecuredAPIKeyWithRestrictions("apikey", restrictions)
+		require.Nil(t, err)
+		require.Equal(t, expected, key)
+	}
+
+	t.Log("TestSecuredKeyRestrictions: Check that conflicting restrictions are rejected")
+	{
+		_, err := GenerateSecuredAPIKeyWithRestrictions("apikey", SecuredKeyRestrictions{
+			Filters:      "brand:apple",
+			SearchParams: Map{"filters": "brand:samsung"},
+		})
+		require.NotNil(t, err)
+
+		_, err = GenerateSecuredAPIKeyWithRestrictions("apikey", SecuredKeyRestrictions{
+			SearchParams: Map{"hitsPerPage": "10"},
+		})
+		require.NotNil(t, err, "should check the search parameters")
+	}
+}
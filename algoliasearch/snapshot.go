@@ -0,0 +1,96 @@
+package algoliasearch
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultSnapshotSuffixFormat is the time layout used by Index.Snapshot to
+// name the snapshots when no suffix format is given.
+const DefaultSnapshotSuffixFormat = "_backup_20060102T1504"
+
+// SnapshotRes describes the snapshot taken by Index.Snapshot: the name of the
+// index the records were copied to and the ID of the copy task.
+type SnapshotRes struct {
+	Name   string
+	TaskID int
+}
+
+func (i *index) Snapshot(suffixFormat string) (res SnapshotRes, err error) {
+	return i.SnapshotWithRequestOptions(suffixFormat, nil)
+}
+
+func (i *index) SnapshotWithRequestOptions(suffixFormat string, opts *RequestOptions) (res SnapshotRes, err error) {
+	if suffixFormat, err = checkSnapshotSuffixFormat(suffixFormat); err != nil {
+		return
+	}
+
+	res.Name = i.unprefixedName() + time.Now().UTC().Format(suffixFormat)
+
+	var task UpdateTaskRes
+	task, err = i.CopyWithRequestOptions(res.Name, opts)
+	res.TaskID = task.TaskID
+	return
+}
+
+func (i *index) PruneSnapshots(suffixFormat string, maxAge time.Duration) (deleted []string, err error) {
+	return i.PruneSnapshotsWithRequestOptions(suffixFormat, maxAge, nil)
+}
+
+func (i *index) PruneSnapshotsWithRequestOptions(suffixFormat string, maxAge time.Duration, opts *RequestOptions) (deleted []string, err error) {
+	if suffixFormat, err = checkSnapshotSuffixFormat(suffixFormat); err != nil {
+		return
+	}
+	if maxAge <= 0 {
+		err = fmt.Errorf("Cannot prune snapshots: the maximum age should be positive, got %s", maxAge)
+		return
+	}
+
+	var indexes []IndexRes
+	if indexes, err = i.client.ListIndexesWithRequestOptions(opts); err != nil {
+		return
+	}
+
+	name := i.unprefixedName()
+	now := time.Now()
+	for _, idx := range indexes {
+		if !strings.HasPrefix(idx.Name, name) {
+			continue
+		}
+
+		// Only the indices whose suffix is a timestamp in the expected
+		// format are snapshots of the index.
+		takenAt, parseErr := time.Parse(suffixFormat, strings.TrimPrefix(idx.Name, name))
+		if parseErr != nil || now.Sub(takenAt) <= maxAge {
+			continue
+		}
+
+		if _, err = i.client.InitIndex(idx.Name).DeleteWithRequestOptions(opts); err != nil {
+			return deleted, fmt.Errorf("Cannot delete snapshot %s: %s", idx.Name, err)
+		}
+		deleted = append(deleted, idx.Name)
+	}
+
+	return
+}
+
+// unprefixedName returns the name of the index without the index prefix of
+// its client (see WithIndexPrefix).
+func (i *index) unprefixedName() string {
+	return strings.TrimPrefix(i.name, i.client.indexPrefix)
+}
+
+// checkSnapshotSuffixFormat returns the `suffixFormat` of the snapshots or
+// the default one if empty, checking that it holds a timestamp.
+func checkSnapshotSuffixFormat(suffixFormat string) (string, error) {
+	if suffixFormat == "" {
+		return DefaultSnapshotSuffixFormat, nil
+	}
+
+	reference := time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+	if reference.Format(suffixFormat) == suffixFormat {
+		return "", fmt.Errorf("Invalid snapshot suffix format %q: it should contain a time layout, e.g. %q", suffixFormat, DefaultSnapshotSuffixFormat)
+	}
+	return suffixFormat, nil
+}
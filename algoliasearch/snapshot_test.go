@@ -0,0 +1,67 @@
+package algoliasearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot(t *testing.T) {
+	recent := time.Now().UTC().Add(-time.Hour).Format(DefaultSnapshotSuffixFormat)
+	old := time.Now().UTC().Add(-72 * time.Hour).Format(DefaultSnapshotSuffixFormat)
+
+	t.Log("TestSnapshot: Start a server holding a recent and an old snapshot")
+	var copies []IndexOperation
+	var deletions []string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST":
+			var op IndexOperation
+			require.Nil(t, json.NewDecoder(r.Body).Decode(&op))
+			copies = append(copies, op)
+			w.Write([]byte(`{"taskID":42}`))
+		case r.Method == "DELETE":
+			deletions = append(deletions, r.URL.Path)
+			w.Write([]byte(`{"taskID":43}`))
+		default:
+			fmt.Fprintf(w, `{"items":[{"name":"test_products"},{"name":"test_products_staging"},{"name":"test_products%s"},{"name":"test_products%s"},{"name":"test_users%s"}]}`, recent, old, old)
+		}
+	}))
+	defer server.Close()
+	index := (&client{transport: newTestTransport(server), indexPrefix: "test_"}).InitIndex("products")
+
+	t.Log("TestSnapshot: Check that the index is copied to a timestamped name")
+	{
+		before := time.Now().UTC().Truncate(time.Minute)
+		res, err := index.Snapshot("")
+		require.Nil(t, err)
+		require.Equal(t, 42, res.TaskID)
+		require.Len(t, copies, 1)
+		require.Equal(t, "copy", copies[0].Operation)
+		require.Equal(t, "test_"+res.Name, copies[0].Destination)
+
+		takenAt, err := time.Parse("products"+DefaultSnapshotSuffixFormat, res.Name)
+		require.Nil(t, err)
+		require.False(t, takenAt.Before(before))
+	}
+
+	t.Log("TestSnapshot: Check that suffix formats without timestamp are rejected")
+	{
+		_, err := index.Snapshot("_backup")
+		require.NotNil(t, err)
+		require.Len(t, copies, 1)
+	}
+
+	t.Log("TestSnapshot: Check that only the old snapshots of the index are pruned")
+	{
+		deleted, err := index.PruneSnapshots("", 24*time.Hour)
+		require.Nil(t, err)
+		require.Equal(t, []string{"products" + old}, deleted)
+		require.Equal(t, []string{"/1/indexes/test_products" + old}, deletions)
+	}
+}
@@ -151,8 +151,8 @@ func addObjectsAndSynonyms(t *testing.T, i Index, testName string) []Synonym {
 	}
 
 	synonyms := []Synonym{
-		NewAltCorrectionSynonym("rob", []string{"robpike"}, "rob", AltCorrection1),
-		NewAltCorrectionSynonym("pike", []string{"robpike"}, "pike", AltCorrection2),
+		NewAltCorrectionSynonym("rob", "rob", []string{"robpike"}, AltCorrection1),
+		NewAltCorrectionSynonym("pike", "pike", []string{"robpike"}, AltCorrection2),
 		NewOneWaySynonym("julien", "speedblue", []string{"julien lemoine"}),
 		NewPlaceholderSynonym("google_placeholder", "<GOOG>", []string{"Google", "GOOG"}),
 	}
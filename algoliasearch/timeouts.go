@@ -0,0 +1,74 @@
+package algoliasearch
+
+import (
+	"net"
+	"time"
+)
+
+// Timeouts gathers the timeouts applied to the requests of a Client (see
+// Client.SetTimeouts). A zero value disables the corresponding timeout,
+// except for `Connect` which then keeps its 2s default.
+type Timeouts struct {
+	// Connect is the maximum time spent establishing the TLS connection with
+	// a host (`TLSHandshakeTimeout` of the underlying http.Transport).
+	Connect time.Duration
+
+	// Read is the maximum time spent waiting for the response headers once
+	// the request was sent (`ResponseHeaderTimeout` of the underlying
+	// http.Transport).
+	Read time.Duration
+
+	// Write is the maximum time spent by each write of the request on the
+	// connection, e.g. while uploading a large batch of records.
+	Write time.Duration
+
+	// Total is the deadline of a whole call, including the retries on the
+	// other hosts and the waits caused by rate limiting. Once exceeded, a
+	// `*TimeoutErr` is returned. It can be overridden per call with
+	// RequestOptions.Timeout.
+	Total time.Duration
+}
+
+// setTimeouts lets the user (through the exported `Client.SetTimeouts`)
+// replace all the timeouts of the transport at once.
+func (t *Transport) setTimeouts(timeouts Timeouts) {
+	connect := timeouts.Connect
+	if connect <= 0 {
+		connect = defaultConnectTimeout
+	}
+	t.setTimeout(connect, timeouts.Read)
+
+	t.writeTimeout = timeouts.Write
+	t.setDial()
+
+	t.totalTimeout = timeouts.Total
+}
+
+// callDeadline returns the time at which the call made with the given `opts`
+// should be abandoned along with the timeout it derives from. A zero time is
+// returned if the call has no deadline.
+func (t *Transport) callDeadline(opts *RequestOptions) (deadline time.Time, timeout time.Duration) {
+	timeout = t.totalTimeout
+	if opts != nil && opts.Timeout > 0 {
+		timeout = opts.Timeout
+	}
+
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+	return
+}
+
+// writeTimeoutConn is a connection whose writes fail if they take more than
+// `timeout`.
+type writeTimeoutConn struct {
+	net.Conn
+	timeout time.Duration
+}
+
+func (c *writeTimeoutConn) Write(b []byte) (int, error) {
+	if err := c.Conn.SetWriteDeadline(time.Now().Add(c.timeout)); err != nil {
+		return 0, err
+	}
+	return c.Conn.Write(b)
+}
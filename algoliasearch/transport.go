@@ -2,23 +2,31 @@ package algoliasearch
 
 import (
 	"bytes"
+	"compress/gzip"
+	"context"
 	_ "crypto/sha512" // Fix certificates
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"math/rand"
 	"net"
 	"net/http"
+	"net/http/httptrace"
 	"net/url"
-	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 const (
 	version = "2.20.0"
+
+	// defaultRateLimitBudget is the maximum time spent, by default, waiting
+	// before retrying requests rejected because of rate limiting.
+	defaultRateLimitBudget = time.Minute
 )
 
 // Define the constants used to specify the type of request.
@@ -34,57 +42,119 @@ func init() {
 }
 
 // Transport is responsible for the connection and the retry strategy to
-// Algolia servers.
+// Algolia servers. The state of the retry strategy, as well as the connection
+// pool as long as it is not configured, are shared by the transports
+// connecting to the same hosts of the same application.
 type Transport struct {
-	activeReadHost    string
-	activeReadSince   time.Time
-	activeWriteHost   string
-	activeWriteSince  time.Time
 	apiKey            string
 	appId             string
-	dialTimeout       time.Duration
+	debug             *debugLogger
 	headers           map[string]string
+	hostsPerm         func(n int) []int
 	httpClient        *http.Client
 	keepAliveDuration time.Duration
+	metrics           MetricsHook
+	onHostFail        func(HostFailure)
+	onRetry           func(HostFailure)
+	onlyProvidedHosts bool
+	openConns         int32
+	ownsHTTPClient    bool
 	providedHosts     []string
+	rateLimitBudget   time.Duration
+	readTimeout       time.Duration
+	requester         Requester
+	state             *hostsState
+	totalTimeout      time.Duration
+	writeTimeout      time.Duration
 }
 
 // NewTransport instantiates a new Transport with the default Algolia hosts to
 // connect to.
 func NewTransport(appId, apiKey string) *Transport {
+	state := sharedHostsState(appId, nil, false)
 	return &Transport{
-		activeReadHost:    "",
-		activeWriteHost:   "",
 		apiKey:            apiKey,
 		appId:             appId,
-		dialTimeout:       1 * time.Second,
 		headers:           defaultHeaders(appId, apiKey),
-		httpClient:        defaultHttpClient(),
+		httpClient:        state.httpClient,
 		keepAliveDuration: 5 * time.Minute,
 		providedHosts:     nil,
+		rateLimitBudget:   defaultRateLimitBudget,
+		state:             state,
 	}
 }
 
 // NewTransport instantiates a new Transport with the specificed hosts as main
 // servers to connect to.
 func NewTransportWithHosts(appId, apiKey string, hosts []string) *Transport {
+	state := sharedHostsState(appId, hosts, false)
 	return &Transport{
-		activeReadHost:    "",
-		activeWriteHost:   "",
 		apiKey:            apiKey,
 		appId:             appId,
-		dialTimeout:       1 * time.Second,
 		headers:           defaultHeaders(appId, apiKey),
-		httpClient:        defaultHttpClient(),
+		httpClient:        state.httpClient,
 		keepAliveDuration: 5 * 60 * time.Second,
 		providedHosts:     hosts,
+		rateLimitBudget:   defaultRateLimitBudget,
+		state:             state,
 	}
 }
 
+// newTransportWithOnlyHosts instantiates a new Transport which only
+// connects to the specified hosts, without falling back on the default
+// Algolia hosts. It is used by the clients of the APIs which are not served
+// by the search hosts (e.g. the Ingestion API).
+func newTransportWithOnlyHosts(appId, apiKey string, hosts []string) *Transport {
+	t := NewTransportWithHosts(appId, apiKey, hosts)
+	t.onlyProvidedHosts = true
+	t.state = sharedHostsState(appId, hosts, true)
+	t.httpClient = t.state.httpClient
+	return t
+}
+
+// withAPIKey returns a new Transport sharing the configuration and the
+// hosts state of the transport (timeouts, extra headers, etc.) but
+// authenticated with the given `apiKey`. It gets its own copy of the HTTP
+// client so that configuring either transport does not affect the other.
+func (t *Transport) withAPIKey(apiKey string) *Transport {
+	headers := make(map[string]string, len(t.headers))
+	for k, v := range t.headers {
+		headers[k] = v
+	}
+	headers["X-Algolia-API-Key"] = apiKey
+
+	return &Transport{
+		apiKey:            apiKey,
+		appId:             t.appId,
+		debug:             t.debug,
+		headers:           headers,
+		hostsPerm:         t.hostsPerm,
+		httpClient:        cloneHTTPClient(t.httpClient),
+		keepAliveDuration: t.keepAliveDuration,
+		metrics:           t.metrics,
+		onHostFail:        t.onHostFail,
+		onRetry:           t.onRetry,
+		onlyProvidedHosts: t.onlyProvidedHosts,
+		ownsHTTPClient:    true,
+		providedHosts:     t.providedHosts,
+		rateLimitBudget:   t.rateLimitBudget,
+		readTimeout:       t.readTimeout,
+		requester:         t.requester,
+		state:             t.state,
+		totalTimeout:      t.totalTimeout,
+		writeTimeout:      t.writeTimeout,
+	}
+}
+
+// userTokenHeader is the header identifying the end user a request is made
+// for.
+const userTokenHeader = "X-Algolia-UserToken"
+
 // defaultHeaders is used to set the default HTTP headers to use with each
 // requests.
 func defaultHeaders(appId, apiKey string) map[string]string {
 	return map[string]string{
+		"Accept-Encoding":          "gzip",
 		"Connection":               "keep-alive",
 		"User-Agent":               "Algolia for Go (" + version + ")",
 		"X-Algolia-API-Key":        apiKey,
@@ -93,7 +163,8 @@ func defaultHeaders(appId, apiKey string) map[string]string {
 }
 
 // defaultHosts returns the list of the default Algolia hosts to use. The
-// entries are shuffled.
+// entries are shuffled, unless the order was made deterministic (see
+// WithHostsSeed and WithFixedHostsOrder).
 func (t *Transport) defaultHosts() []string {
 	hosts := []string{
 		t.appId + "-1.algolianet.com",
@@ -101,21 +172,39 @@ func (t *Transport) defaultHosts() []string {
 		t.appId + "-3.algolianet.com",
 	}
 
+	perm := rand.Perm
+	if t.hostsPerm != nil {
+		perm = t.hostsPerm
+	}
+
 	shuffled := make([]string, len(hosts))
-	for i, v := range rand.Perm(len(hosts)) {
+	for i, v := range perm(len(hosts)) {
 		shuffled[i] = hosts[v]
 	}
 
 	return shuffled
 }
 
-// defaultHttpClient returns the `*http.Client` which will perform all the
-// requests. All the timeout settings are explicitely defined here.
-func defaultHttpClient() *http.Client {
-	return &http.Client{
-		Timeout:   time.Second * 30,
-		Transport: defaultTransport(1 * time.Second),
+// seededPerm returns a function shuffling the hosts like `rand.Perm` does but
+// with its own RNG, seeded with `seed`, so that the order of the hosts is
+// reproducible.
+func seededPerm(seed int64) func(n int) []int {
+	var mu sync.Mutex
+	rng := rand.New(rand.NewSource(seed))
+	return func(n int) []int {
+		mu.Lock()
+		defer mu.Unlock()
+		return rng.Perm(n)
+	}
+}
+
+// identityPerm keeps the hosts in their declared order.
+func identityPerm(n int) []int {
+	perm := make([]int, n)
+	for i := range perm {
+		perm[i] = i
 	}
+	return perm
 }
 
 // defaultTransport returns the `*http.Transport` which starts and maintain the
@@ -125,7 +214,7 @@ func defaultHttpClient() *http.Client {
 func defaultTransport(dialTimeout time.Duration) *http.Transport {
 	return &http.Transport{
 		Proxy:               http.ProxyFromEnvironment,
-		Dial:                defaultDial(dialTimeout).Dial,
+		DialContext:         defaultDial(dialTimeout).DialContext,
 		DisableKeepAlives:   false,
 		MaxIdleConnsPerHost: 64,
 		TLSHandshakeTimeout: 2 * time.Second,
@@ -141,6 +230,32 @@ func defaultDial(dialTimeout time.Duration) *net.Dialer {
 	}
 }
 
+// dial returns the function used by the underlying http.Transport to open
+// connections, according to the dialer timeout of the retry strategy, to the
+// write timeout of the transport and to its metrics hook, if any.
+func (t *Transport) dial() func(ctx context.Context, network, addr string) (net.Conn, error) {
+	dialContext := t.state.dialContext
+	writeTimeout := t.writeTimeout
+	metrics := t.metrics
+	if writeTimeout <= 0 && metrics == nil {
+		return dialContext
+	}
+
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dialContext(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		if writeTimeout > 0 {
+			conn = &writeTimeoutConn{Conn: conn, timeout: writeTimeout}
+		}
+		if metrics != nil {
+			conn = t.trackConn(conn, metrics)
+		}
+		return conn, nil
+	}
+}
+
 // addHeaders adds the key/value pairs from `headers` to the header list of the
 // `req` request.
 func addHeaders(req *http.Request, headers map[string]string) {
@@ -165,77 +280,225 @@ func (t *Transport) setExtraHeader(key, value string) {
 	t.headers[key] = value
 }
 
+// setUserToken lets the user (through the exported `Client.SetUserToken`)
+// identify the end user all the requests are made for. An empty `token`
+// removes the user token.
+func (t *Transport) setUserToken(token string) {
+	if token == "" {
+		delete(t.headers, userTokenHeader)
+		return
+	}
+	t.headers[userTokenHeader] = token
+}
+
+// setDebug lets the user (through the exported `Client.SetDebug`) dump all
+// the requests and responses to the given writer. A nil `w` disables the
+// dumps.
+func (t *Transport) setDebug(w io.Writer) {
+	if w == nil {
+		t.debug = nil
+		return
+	}
+	t.debug = &debugLogger{w: w}
+}
+
 // setTimeout lets the user (through the exported `Client.SetTimeout`) replace
 // the default values of `TLSHandshakeTimeout` (via `connectTimeout`) and
-// `ResponseHeaderTimeout` (via `readTimeout`).
+// `ResponseHeaderTimeout` (via `readTimeout`). If the underlying RoundTripper
+// of the HTTP client is not an instance of http.Transport, only the read
+// timeout is applied, through the context of the requests.
 func (t *Transport) setTimeout(connectTimeout, readTimeout time.Duration) {
-	switch transport := t.httpClient.Transport.(type) {
-	case *http.Transport:
+	t.readTimeout = readTimeout
+
+	if transport, ok := t.httpTransport(); ok {
 		transport.TLSHandshakeTimeout = connectTimeout
 		transport.ResponseHeaderTimeout = readTimeout
-	default:
-		fmt.Fprintln(os.Stderr, "Timeouts not set for nonstandard underlying Transport")
 	}
 }
 
 // request is the method used by the `Client` to perform the request against
 // the Algolia servers (or to the list of specified hosts).
 func (t *Transport) request(method, path string, body interface{}, typeCall int, opts *RequestOptions) ([]byte, error) {
-	var res []byte
-	var err error
+	var attempts []HostAttempt
+	var attempt int
 
-	for _, host := range t.hostsToTry(typeCall) {
-		res, err = t.tryRequest(method, host, path, body, opts)
+	deadline, timeout := t.callDeadline(opts)
+
+	hosts := t.hostsToTry(typeCall)
+	for n, host := range hosts {
+		start := time.Now()
+		res, err := t.tryRequestRateLimited(method, host, path, body, opts, deadline, &attempt)
 		if err == nil {
-			t.resetDialTimeout()
-			if typeCall == write {
-				t.activeWriteSince = time.Now()
-				t.activeWriteHost = host
-			} else {
-				t.activeReadSince = time.Now()
-				t.activeReadHost = host
-			}
+			t.state.markUp(host, typeCall)
 			return res, nil
 		}
-		t.increaseDialTimeout()
+
+		// The hosts share the rate limit of the application: once the
+		// rate-limit retry budget is exhausted, trying the other ones is
+		// pointless.
+		if _, ok := err.(*RateLimitedErr); ok {
+			return nil, err
+		}
+
+		attempts = append(attempts, HostAttempt{
+			Host:     host,
+			Err:      err,
+			Duration: time.Since(start),
+		})
+
+		// The other hosts are not tried once the deadline of the call is
+		// exceeded. The host is not blamed as it may just have been given
+		// too little time.
+		if !deadline.IsZero() && !time.Now().Before(deadline) {
+			return nil, &TimeoutErr{Timeout: timeout, Attempts: attempts}
+		}
+
+		// Client errors (4XX) are answered by healthy hosts: the request is
+		// still tried on the next host but neither is the host marked as
+		// down nor are the hooks notified.
+		if !isHostFailure(err) {
+			continue
+		}
+
+		t.state.markDown(host)
+
+		failure := HostFailure{Host: host, Err: err, Attempt: attempt}
+		if n+1 < len(hosts) {
+			failure.NextHost = hosts[n+1]
+		}
+		if t.onHostFail != nil {
+			t.onHostFail(failure)
+		}
+		if t.onRetry != nil && failure.NextHost != "" {
+			t.onRetry(failure)
+		}
 	}
 
-	if typeCall == write {
-		t.activeWriteHost = ""
-	} else {
-		t.activeReadHost = ""
+	return nil, &NoMoreHostToTryErr{Attempts: attempts}
+}
+
+// requestJSON sends the request like `request` does and decodes the JSON
+// response into `res`.
+func (t *Transport) requestJSON(res interface{}, method, path string, body interface{}, typeCall int, opts *RequestOptions) error {
+	r, err := t.request(method, path, body, typeCall, opts)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(r, res)
+}
+
+// isHostFailure returns `true` if the request which failed with the given
+// `err` failed because of the host, i.e. because of a network error or a
+// server error (5XX).
+func isHostFailure(err error) bool {
+	e, ok := asAlgoliaErr(err)
+	return !ok || e.Status/100 == 5
+}
+
+// setRateLimitBudget lets the user (through the exported
+// `Client.SetRateLimitRetryBudget`) change the maximum time spent waiting
+// before retrying rate-limited requests.
+func (t *Transport) setRateLimitBudget(budget time.Duration) {
+	t.rateLimitBudget = budget
+}
+
+// tryRequestRateLimited performs the request against the given `host` like
+// `tryRequest` does but, if the API answers with a 429 HTTP status code, the
+// request is sent again after the delay specified by the `Retry-After`
+// response header (or an exponential backoff starting at 1s if the header is
+// missing), as long as the total waiting time stays within the rate-limit
+// budget of the transport and before the `deadline` of the call, if any. Once
+// exhausted, a `*RateLimitedErr` is returned. The `attempt` counter, shared
+// by all the hosts tried for the same request, is incremented each time the
+// request is sent.
+func (t *Transport) tryRequestRateLimited(method, host, path string, body interface{}, opts *RequestOptions, deadline time.Time, attempt *int) ([]byte, error) {
+	var waited time.Duration
+	backoff := time.Second
+
+	for try := 1; ; try++ {
+		*attempt++
+		res, err := t.tryRequest(method, host, path, body, opts, deadline, *attempt)
+		e, ok := err.(*RateLimitedErr)
+		if !ok {
+			return res, err
+		}
+
+		delay := e.RetryAfter
+		if delay <= 0 {
+			delay = backoff
+			backoff *= 2
+		}
+
+		if waited+delay > t.rateLimitBudget ||
+			(!deadline.IsZero() && time.Now().Add(delay).After(deadline)) {
+			e.Attempts = try
+			e.Waited = waited
+			return nil, e
+		}
+
+		time.Sleep(delay)
+		waited += delay
+	}
+}
+
+// parseRetryAfter returns the delay specified by the given `Retry-After` HTTP
+// header value, expressed either as a number of seconds or as an HTTP date.
+// Zero is returned if the value is missing or invalid.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
 	}
 
-	return nil, err
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+
+	return 0
 }
 
 // hostsToTry returns the list of hosts to try ordered by priority according to
-// the type of request (write vs. read/search) and if a previous host was
-// marked as active.
+// the type of request (write vs. read/search), if a previous host was marked
+// as active and if some hosts recently failed. As the state of the hosts is
+// shared by the clients of the same application, a host which failed for one
+// client is tried last by all of them.
 func (t *Transport) hostsToTry(typeCall int) []string {
+	hosts := t.candidateHosts(typeCall)
+
+	up := make([]string, 0, len(hosts))
+	var down []string
+	for _, host := range hosts {
+		if t.state.isDown(host) {
+			down = append(down, host)
+		} else {
+			up = append(up, host)
+		}
+	}
+
+	return append(up, down...)
+}
+
+// candidateHosts returns the list of hosts to try, regardless of their
+// health, ordered by priority.
+func (t *Transport) candidateHosts(typeCall int) []string {
 	var hosts []string
 
 	// Step 1:
 	//
-	// We set the first host to try to the last active one if any and
-	// if it was active recently.
+	// We set the first host to try to the last active one (for the type of
+	// request) if any and if it was used in the last `keepAliveDuration`
+	// seconds. We then put the main algolia.net host for write queries, and
+	// the DSN host otherwise.
 
-	if typeCall == write {
-		// In case the request is a write query, we put the last active write
-		// host first in the list of hosts to try if it was used in the last
-		// `keepAliveDuration` seconds. We then put the main algolia.net host.
-		if t.activeWriteHost != "" &&
-			time.Now().Sub(t.activeWriteSince) <= t.keepAliveDuration {
-			hosts = []string{t.activeWriteHost}
-		}
-	} else {
-		// In case the request is not a write query, we put the last active
-		// read host first in the list of hosts to try if it was used in the
-		// last `keepAliveDuration` seconds. We then put the DSN host.
-		if t.activeReadHost != "" &&
-			time.Now().Sub(t.activeReadSince) <= t.keepAliveDuration {
-			hosts = []string{t.activeReadHost}
-		}
+	if host := t.state.activeHost(typeCall, t.keepAliveDuration); host != "" {
+		hosts = []string{host}
 	}
 
 	// Step 2:
@@ -248,6 +511,10 @@ func (t *Transport) hostsToTry(typeCall int) []string {
 		hosts = append(hosts, t.providedHosts...)
 	}
 
+	if t.onlyProvidedHosts {
+		return hosts
+	}
+
 	// Step 3:
 	//
 	// The main host is added to the list, along with the default ones.
@@ -264,36 +531,105 @@ func (t *Transport) hostsToTry(typeCall int) []string {
 
 // tryRequest is the underlying method which actually performs the request. It
 // returns the response as a byte slice or a non-nil error if anything went
-// wrong.
-func (t *Transport) tryRequest(method, host, path string, body interface{}, opts *RequestOptions) ([]byte, error) {
+// wrong. The request and the response are dumped if the debug mode is
+// enabled, `attempt` being the number of times the request was sent so far.
+func (t *Transport) tryRequest(method, host, path string, body interface{}, opts *RequestOptions, deadline time.Time, attempt int) ([]byte, error) {
 	// Build the request
 	req, err := t.buildRequest(method, host, path, body, opts)
 	if err != nil {
 		return nil, err
 	}
 
+	if !deadline.IsZero() {
+		ctx, cancel := context.WithDeadline(context.Background(), deadline)
+		defer cancel()
+		req = req.WithContext(ctx)
+	}
+
+	if metrics := t.metrics; metrics != nil {
+		req = req.WithContext(httptrace.WithClientTrace(req.Context(), metricsTrace(metrics, host)))
+	}
+
+	debug := t.debug
+	if debug != nil {
+		reqBody, err := readRequestBody(req)
+		if err != nil {
+			return nil, fmt.Errorf("Cannot read request body: %s", err)
+		}
+		debug.logRequest(req, reqBody, attempt)
+	}
+
 	// Perform the request
-	res, err := t.httpClient.Do(req)
+	start := time.Now()
+	res, err := t.do(req)
 	if err != nil {
+		if debug != nil {
+			debug.logError(req, err, attempt, time.Since(start))
+		}
 		return nil, fmt.Errorf("Cannot perform request [%s] %s (%s): %s", method, path, host, err)
 	}
 	defer res.Body.Close()
 
 	// Read response's body
-	bodyRes, err := ioutil.ReadAll(res.Body)
+	bodyRes, err := readResponseBody(res)
 	if err != nil {
+		if debug != nil {
+			debug.logError(req, err, attempt, time.Since(start))
+		}
 		return nil, fmt.Errorf("Cannot read response body: %s", err)
 	}
 
+	if debug != nil {
+		debug.logResponse(req, res, bodyRes, attempt, time.Since(start))
+	}
+
+	if opts != nil && opts.OnResponse != nil {
+		opts.OnResponse(ResponseMetadata{
+			Host:       host,
+			StatusCode: res.StatusCode,
+			Header:     res.Header,
+			Attempt:    attempt,
+			Duration:   time.Since(start),
+		})
+	}
+
 	// Return the body as an error if the status code is not 2XX
 	code := res.StatusCode
+	if code == http.StatusTooManyRequests {
+		return nil, &RateLimitedErr{
+			RetryAfter: parseRetryAfter(res.Header.Get("Retry-After"), time.Now()),
+			Err:        newAlgoliaErr(code, bodyRes),
+		}
+	}
 	if !(200 <= code && code < 300) {
-		return nil, errors.New(string(bodyRes))
+		return nil, newAlgoliaErr(code, bodyRes)
 	}
 
 	return bodyRes, nil
 }
 
+// readResponseBody reads the whole body of the `res` response, decompressing
+// it if needed. As the `Accept-Encoding` header is set explicitly, the
+// responses are never decompressed by the HTTP client itself, whatever its
+// transport.
+func readResponseBody(res *http.Response) ([]byte, error) {
+	if !strings.EqualFold(res.Header.Get("Content-Encoding"), "gzip") {
+		return ioutil.ReadAll(res.Body)
+	}
+
+	gz, err := gzip.NewReader(res.Body)
+	if err == io.EOF {
+		// Empty body
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	return ioutil.ReadAll(gz)
+}
+
 // buildRequest returns a valid `http.Request` with the headers and body (if
 // any) correctly set. The return error is non-nil if the request is invalid or
 // if the body, if non-nil, is not a valid JSON.
@@ -337,6 +673,10 @@ func (t *Transport) buildRequest(method, host, path string, body interface{}, op
 		addHeaders(req, opts.ExtraHeaders)
 		addHeaders(req, map[string]string{"X-Forwarded-For": opts.ForwardedFor})
 		addUrlParameters(req, opts.ExtraUrlParams)
+
+		if opts.UserToken != "" {
+			req.Header.Set(userTokenHeader, opts.UserToken)
+		}
 	}
 
 	return req, nil
@@ -405,46 +745,71 @@ func buildRequestWithBodyParameters(method, url string, body interface{}) (*http
 	return req, nil
 }
 
-// resetDialTimeout increases the `Timeout` value of the underlying dialer by 1
-// second if the underyling RoundTripper of the HTTP client is an instance of
-// http.Transport.
-func (t *Transport) increaseDialTimeout() {
-	t.dialTimeout = t.dialTimeout + time.Second
-	t.setDialTimeout(t.dialTimeout)
+// httpTransport returns the underlying http.Transport of the HTTP client, if
+// any, so that it can be configured. Unless the transport owns its HTTP
+// client, i.e. if it is the one shared with the other clients of the
+// application or the one given by the user, it is first copied so that the
+// configuration only applies to this transport.
+func (t *Transport) httpTransport() (*http.Transport, bool) {
+	if _, ok := t.httpClient.Transport.(*http.Transport); !ok {
+		return nil, false
+	}
+
+	if !t.ownsHTTPClient {
+		t.httpClient = cloneHTTPClient(t.httpClient)
+		t.ownsHTTPClient = true
+	}
+
+	return t.httpClient.Transport.(*http.Transport), true
+}
+
+// cloneHTTPClient returns a copy of the `client` HTTP client whose underlying
+// RoundTripper is also copied if it is an instance of http.Transport.
+func cloneHTTPClient(client *http.Client) *http.Client {
+	clone := *client
+	if transport, ok := client.Transport.(*http.Transport); ok {
+		clone.Transport = transport.Clone()
+	}
+	return &clone
 }
 
-// resetDialTimeout resets the `Timeout` value of the underlying dialer to 1
-// second if the underyling RoundTripper of the HTTP client is an instance of
-// http.Transport.
-func (t *Transport) resetDialTimeout() {
-	t.dialTimeout = 1 * time.Second
-	t.setDialTimeout(t.dialTimeout)
+// setDial makes the underlying dialer apply the write timeout and report the
+// metrics of the transport if the underlying RoundTripper of the HTTP client
+// is an instance of http.Transport.
+func (t *Transport) setDial() {
+	if transport, ok := t.httpTransport(); ok {
+		transport.DialContext = t.dial()
+	}
+	// Do nothing if the HTTP client was overriden and the RoundTripper is not
+	// an instance of http.Transport.
 }
 
-// setDialTimeout sets the `Timeout` value of the underyling dialer to the
-// given value if the underlying RoundTripper of the HTTP client is an instance
-// of http.Transport.
-func (t *Transport) setDialTimeout(dialTimeout time.Duration) {
-	switch transport := t.httpClient.Transport.(type) {
-	case (*http.Transport):
-		transport.Dial = defaultDial(dialTimeout).Dial
-		t.httpClient.Transport = transport
-	default:
-		// Do nothing if the HTTP client was overriden and the RoundTripper is
-		// not an instance of http.Transport.
+// tuneHTTPTransport applies `tune` to the underlying RoundTripper of the HTTP
+// client if it is an instance of `http.Transport`.
+func (t *Transport) tuneHTTPTransport(tune func(*http.Transport)) {
+	if transport, ok := t.httpTransport(); ok {
+		tune(transport)
 	}
+	// Do nothing if the HTTP client was overriden and the RoundTripper is not
+	// an instance of http.Transport.
+}
+
+// disableKeepAlives makes the transport open a new connection for each
+// request.
+func (t *Transport) disableKeepAlives() {
+	delete(t.headers, "Connection")
+	t.tuneHTTPTransport(func(transport *http.Transport) {
+		transport.DisableKeepAlives = true
+	})
 }
 
 // setMaxIdleConnsPerHost sets the `MaxIdleConnsPerHost` via the given
 // `perHosts` value of the underlying RoundTripper of the HTTP client if it is
 // an instance of `http.Transport`.
 func (t *Transport) setMaxIdleConnsPerHost(maxIdleConnsPerHost int) {
-	switch transport := t.httpClient.Transport.(type) {
-	case (*http.Transport):
+	if transport, ok := t.httpTransport(); ok {
 		transport.MaxIdleConnsPerHost = maxIdleConnsPerHost
-		t.httpClient.Transport = transport
-	default:
-		// Do nothing if the HTTP client was overriden and the RoundTripper is
-		// not an instance of http.Transport.
 	}
+	// Do nothing if the HTTP client was overriden and the RoundTripper is not
+	// an instance of http.Transport.
 }
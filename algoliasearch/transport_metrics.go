@@ -0,0 +1,117 @@
+package algoliasearch
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http/httptrace"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Metrics reported by the transport of the client (see
+// Client.SetMetricsHook). The timings are expressed in milliseconds and,
+// except for `connections_open`, are tagged with the host the request was
+// sent to.
+const (
+	// ConnectionsOpenMetric is the number of connections currently opened by
+	// the client, reported each time a connection is opened or closed. It is
+	// only available if the underlying transport is an `*http.Transport`.
+	ConnectionsOpenMetric = "connections_open"
+
+	// ConnectionsNewMetric and ConnectionsReusedMetric are reported, with a
+	// value of 1, each time a request is sent over a new connection or over
+	// an idle connection of the pool, respectively.
+	ConnectionsNewMetric    = "connections_new"
+	ConnectionsReusedMetric = "connections_reused"
+
+	DNSLookupMetric    = "dns_lookup_ms"
+	ConnectMetric      = "connect_ms"
+	TLSHandshakeMetric = "tls_handshake_ms"
+)
+
+// setMetricsHook lets the user (through the exported `Client.SetMetricsHook`)
+// monitor the connections of the transport. A nil `hook` disables the
+// metrics.
+func (t *Transport) setMetricsHook(hook MetricsHook) {
+	t.metrics = hook
+	t.setDial()
+}
+
+// trackConn returns the `conn` connection, updating the number of opened
+// connections reported to the `hook` until it is closed.
+func (t *Transport) trackConn(conn net.Conn, hook MetricsHook) net.Conn {
+	hook.Observe(ConnectionsOpenMetric, float64(atomic.AddInt32(&t.openConns, 1)), nil)
+	return &trackedConn{
+		Conn: conn,
+		onClose: func() {
+			hook.Observe(ConnectionsOpenMetric, float64(atomic.AddInt32(&t.openConns, -1)), nil)
+		},
+	}
+}
+
+// trackedConn is a connection calling `onClose` the first time it is closed.
+type trackedConn struct {
+	net.Conn
+	once    sync.Once
+	onClose func()
+}
+
+func (c *trackedConn) Close() error {
+	err := c.Conn.Close()
+	c.once.Do(c.onClose)
+	return err
+}
+
+// metricsTrace returns the trace reporting to the `hook` how the connection
+// used by a request sent to `host` was obtained.
+func metricsTrace(hook MetricsHook, host string) *httptrace.ClientTrace {
+	tags := map[string]string{"host": host}
+
+	// The hooks of the trace may be called concurrently, e.g. when the IPv4
+	// and IPv6 addresses of the host are dialed in parallel.
+	var mu sync.Mutex
+	starts := make(map[string]time.Time)
+	start := func(key string) {
+		mu.Lock()
+		starts[key] = time.Now()
+		mu.Unlock()
+	}
+	done := func(key, metric string, err error) {
+		mu.Lock()
+		since, ok := starts[key]
+		delete(starts, key)
+		mu.Unlock()
+		if ok && err == nil {
+			hook.Observe(metric, float64(time.Since(since))/float64(time.Millisecond), tags)
+		}
+	}
+
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			start("dns")
+		},
+		DNSDone: func(info httptrace.DNSDoneInfo) {
+			done("dns", DNSLookupMetric, info.Err)
+		},
+		ConnectStart: func(network, addr string) {
+			start("connect " + network + " " + addr)
+		},
+		ConnectDone: func(network, addr string, err error) {
+			done("connect "+network+" "+addr, ConnectMetric, err)
+		},
+		TLSHandshakeStart: func() {
+			start("tls")
+		},
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			done("tls", TLSHandshakeMetric, err)
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Reused {
+				hook.Observe(ConnectionsReusedMetric, 1, tags)
+			} else {
+				hook.Observe(ConnectionsNewMetric, 1, tags)
+			}
+		},
+	}
+}
@@ -1,10 +1,18 @@
 package algoliasearch
 
 import (
+	"bytes"
+	"compress/gzip"
+	"crypto/tls"
 	"encoding/json"
 	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -69,9 +77,559 @@ func TestTransport_BuildRequest(t *testing.T) {
 	}
 }
 
+func TestTransport_UserToken(t *testing.T) {
+	transport := NewTransport("appid", "apikey")
+	userTokens := func(opts *RequestOptions) []string {
+		req, err := transport.buildRequest("GET", "APPID.algolia.net", "/1/indexes", nil, opts)
+		require.Nil(t, err, "should build a new request without error")
+		return req.Header[http.CanonicalHeaderKey(userTokenHeader)]
+	}
+
+	t.Log("TestTransport_UserToken: Check the client-level user token")
+	require.Empty(t, userTokens(nil))
+	transport.setUserToken("user-42")
+	require.Equal(t, []string{"user-42"}, userTokens(nil))
+
+	t.Log("TestTransport_UserToken: Check the per-request override")
+	require.Equal(t, []string{"user-43"}, userTokens(&RequestOptions{UserToken: "user-43"}))
+	require.Equal(t, []string{"user-42"}, userTokens(&RequestOptions{}))
+
+	t.Log("TestTransport_UserToken: Check the removal of the user token")
+	transport.setUserToken("")
+	require.Empty(t, userTokens(nil))
+}
+
 func checkHeader(t *testing.T, header, value string, headers http.Header) {
 	header = strings.Title(header)
 	require.Contains(t, headers, header)
 	require.Equal(t, 1, len(headers[header]), "header value slice should only contain one element")
 	require.Equal(t, value, headers[header][0], "header should have the correct value")
 }
+
+func TestTransport_RateLimit(t *testing.T) {
+	t.Log("TestTransport_RateLimit: Start a server rate limiting the first request")
+	var calls int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"message":"Too many requests","status":429}`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	transport := newTestTransport(server)
+
+	t.Log("TestTransport_RateLimit: Check that the request is retried after the given delay")
+	{
+		start := time.Now()
+		_, err := transport.request("GET", "/1/isalive", nil, read, nil)
+		require.Nil(t, err, "should succeed once the rate limit is lifted")
+		require.Equal(t, int32(2), atomic.LoadInt32(&calls), "should send the request twice")
+		require.True(t, time.Since(start) >= time.Second, "should honor the Retry-After header")
+	}
+
+	t.Log("TestTransport_RateLimit: Check that an error is returned once the budget is exhausted")
+	{
+		atomic.StoreInt32(&calls, 0)
+		transport.setRateLimitBudget(0)
+		_, err := transport.request("GET", "/1/isalive", nil, read, nil)
+		e, ok := err.(*RateLimitedErr)
+		require.True(t, ok, "should return a *RateLimitedErr")
+		require.Equal(t, 1, e.Attempts)
+		require.Equal(t, time.Second, e.RetryAfter)
+		require.True(t, IsRateLimited(err))
+		require.Equal(t, int32(1), atomic.LoadInt32(&calls), "should not retry on other hosts")
+	}
+
+	t.Log("TestTransport_RateLimit: Check the parsing of the Retry-After header")
+	{
+		now := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+		require.Equal(t, 3*time.Second, parseRetryAfter("3", now))
+		require.Equal(t, 10*time.Second, parseRetryAfter("Sun, 01 Jan 2017 00:00:10 GMT", now))
+		require.Equal(t, time.Duration(0), parseRetryAfter("", now))
+		require.Equal(t, time.Duration(0), parseRetryAfter("-1", now))
+		require.Equal(t, time.Duration(0), parseRetryAfter("invalid", now))
+	}
+}
+
+func TestTransport_Gzip(t *testing.T) {
+	t.Log("TestTransport_Gzip: Start a server compressing its responses when accepted")
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "gzip" || r.URL.Path == "/identity" {
+			w.Write([]byte(`{"hits":[]}`))
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		gz.Write([]byte(`{"hits":[{"objectID":"1"}]}`))
+		gz.Close()
+	}))
+	defer server.Close()
+	transport := newTestTransport(server)
+
+	t.Log("TestTransport_Gzip: Check that compressed responses are decompressed")
+	{
+		res, err := transport.request("GET", "/1/indexes/test/browse", nil, read, nil)
+		require.Nil(t, err)
+		require.Equal(t, `{"hits":[{"objectID":"1"}]}`, string(res))
+	}
+
+	t.Log("TestTransport_Gzip: Check that uncompressed responses are still accepted")
+	{
+		res, err := transport.request("GET", "/identity", nil, read, nil)
+		require.Nil(t, err)
+		require.Equal(t, `{"hits":[]}`, string(res))
+	}
+}
+
+func TestTransport_OnResponse(t *testing.T) {
+	t.Log("TestTransport_OnResponse: Start a server failing the first request")
+	calls := 0
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("X-Algolia-Served-By", "replica-"+strconv.Itoa(calls))
+		if calls == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "https://")
+	transport := newTestTransport(server)
+	transport.providedHosts = []string{host, host}
+	transport.onlyProvidedHosts = true
+
+	t.Log("TestTransport_OnResponse: Check that the metadata of each response is reported")
+	{
+		var responses []ResponseMetadata
+		opts := &RequestOptions{OnResponse: func(m ResponseMetadata) {
+			responses = append(responses, m)
+		}}
+
+		_, err := transport.request("GET", "/1/isalive", nil, read, opts)
+		require.Nil(t, err)
+		require.Len(t, responses, 2)
+
+		require.Equal(t, host, responses[0].Host)
+		require.Equal(t, http.StatusBadGateway, responses[0].StatusCode)
+		require.Equal(t, 1, responses[0].Attempt)
+		require.Equal(t, "replica-1", responses[0].Header.Get("X-Algolia-Served-By"))
+
+		require.Equal(t, http.StatusOK, responses[1].StatusCode)
+		require.Equal(t, 2, responses[1].Attempt)
+		require.Equal(t, "replica-2", responses[1].Header.Get("X-Algolia-Served-By"))
+	}
+}
+
+func TestTransport_RetryHooks(t *testing.T) {
+	t.Log("TestTransport_RetryHooks: Start a failing server and a healthy one")
+	failing := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	healthy := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer healthy.Close()
+
+	failingHost := strings.TrimPrefix(failing.URL, "https://")
+	healthyHost := strings.TrimPrefix(healthy.URL, "https://")
+	transport := newTransportWithOnlyHosts("retry-hooks", "apikey", []string{failingHost, healthyHost})
+	transport.httpClient = newTestTransport(healthy).httpClient
+
+	var retries, failures []HostFailure
+	transport.setOnRetry(func(f HostFailure) { retries = append(retries, f) })
+	transport.setOnHostFail(func(f HostFailure) { failures = append(failures, f) })
+
+	t.Log("TestTransport_RetryHooks: Check that the hooks are called when the first host fails")
+	{
+		_, err := transport.request("GET", "/1/isalive", nil, read, nil)
+		require.Nil(t, err)
+
+		require.Len(t, failures, 1)
+		require.Equal(t, failingHost, failures[0].Host)
+		require.Equal(t, healthyHost, failures[0].NextHost)
+		require.Equal(t, 1, failures[0].Attempt)
+		e, ok := asAlgoliaErr(failures[0].Err)
+		require.True(t, ok)
+		require.Equal(t, http.StatusInternalServerError, e.Status)
+		require.Equal(t, failures, retries)
+	}
+
+	t.Log("TestTransport_RetryHooks: Check that the last host failing is not reported as retried")
+	{
+		retries, failures = nil, nil
+		transport := newTransportWithOnlyHosts("retry-hooks", "apikey", []string{failingHost})
+		transport.httpClient = newTestTransport(failing).httpClient
+		transport.setOnRetry(func(f HostFailure) { retries = append(retries, f) })
+		transport.setOnHostFail(func(f HostFailure) { failures = append(failures, f) })
+
+		_, err := transport.request("GET", "/1/isalive", nil, read, nil)
+		require.NotNil(t, err)
+		require.Len(t, failures, 1)
+		require.Equal(t, "", failures[0].NextHost)
+		require.Nil(t, retries)
+	}
+}
+
+func TestTransport_RetryClientErrors(t *testing.T) {
+	t.Log("TestTransport_RetryClientErrors: Start a server answering 404 and a healthy one")
+	notFound := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"Index does not exist","status":404}`))
+	}))
+	defer notFound.Close()
+	healthy := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer healthy.Close()
+
+	notFoundHost := strings.TrimPrefix(notFound.URL, "https://")
+	healthyHost := strings.TrimPrefix(healthy.URL, "https://")
+
+	t.Log("TestTransport_RetryClientErrors: Check that 4XX responses are retried on the next host")
+	{
+		transport := newTransportWithOnlyHosts("retry-client-errors", "apikey", []string{notFoundHost, healthyHost})
+		transport.httpClient = newTestTransport(healthy).httpClient
+		var failures []HostFailure
+		transport.setOnHostFail(func(f HostFailure) { failures = append(failures, f) })
+		transport.setOnRetry(func(f HostFailure) { failures = append(failures, f) })
+
+		_, err := transport.request("GET", "/1/indexes/products/settings", nil, read, nil)
+		require.Nil(t, err)
+		require.False(t, transport.state.isDown(notFoundHost), "should not mark the host answering 404 as down")
+		require.Nil(t, failures, "should not report the 404 to the hooks")
+	}
+
+	t.Log("TestTransport_RetryClientErrors: Check that the 4XX response is reported once all hosts failed")
+	{
+		transport := newTransportWithOnlyHosts("retry-client-errors", "apikey", []string{notFoundHost})
+		transport.httpClient = newTestTransport(notFound).httpClient
+
+		_, err := transport.request("GET", "/1/indexes/products/settings", nil, read, nil)
+		_, ok := err.(*NoMoreHostToTryErr)
+		require.True(t, ok, "should return a *NoMoreHostToTryErr")
+		require.True(t, IsNotFound(err))
+	}
+}
+
+func TestTransport_Timeouts(t *testing.T) {
+	t.Log("TestTransport_Timeouts: Start a server answering after 200ms")
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	transport := newTestTransport(server)
+
+	t.Log("TestTransport_Timeouts: Check that sub-second timeouts are applied")
+	{
+		transport.setTimeouts(Timeouts{Read: 500 * time.Millisecond, Write: 100 * time.Millisecond})
+		httpTransport := transport.httpClient.Transport.(*http.Transport)
+		require.Equal(t, defaultConnectTimeout, httpTransport.TLSHandshakeTimeout)
+		require.Equal(t, 500*time.Millisecond, httpTransport.ResponseHeaderTimeout)
+		require.Equal(t, 100*time.Millisecond, transport.writeTimeout)
+
+		_, err := transport.request("GET", "/1/isalive", nil, read, nil)
+		require.Nil(t, err)
+	}
+
+	t.Log("TestTransport_Timeouts: Check that the call is abandoned once its deadline is exceeded")
+	{
+		transport.setTimeouts(Timeouts{Total: 50 * time.Millisecond})
+		start := time.Now()
+		_, err := transport.request("GET", "/1/isalive", nil, read, nil)
+		require.True(t, time.Since(start) < 200*time.Millisecond, "should not wait for the response")
+		e, ok := err.(*TimeoutErr)
+		require.True(t, ok, "should return a *TimeoutErr")
+		require.Equal(t, 50*time.Millisecond, e.Timeout)
+		require.Len(t, e.Attempts, 1, "should not try the other hosts")
+		require.True(t, IsTimeout(err))
+	}
+
+	t.Log("TestTransport_Timeouts: Check that the deadline can be overridden per call")
+	{
+		_, err := transport.request("GET", "/1/isalive", nil, read, &RequestOptions{Timeout: 5 * time.Second})
+		require.Nil(t, err)
+	}
+}
+
+func TestTransport_Metrics(t *testing.T) {
+	t.Log("TestTransport_Metrics: Start a server and record the metrics of the transport")
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	transport := newTestTransport(server)
+	host := strings.TrimPrefix(server.URL, "https://")
+
+	var mu sync.Mutex
+	metrics := make(map[string][]float64)
+	transport.setMetricsHook(MetricsHookFunc(func(metric string, value float64, tags map[string]string) {
+		mu.Lock()
+		defer mu.Unlock()
+		if metric != ConnectionsOpenMetric {
+			require.Equal(t, host, tags["host"])
+		}
+		metrics[metric] = append(metrics[metric], value)
+	}))
+
+	t.Log("TestTransport_Metrics: Check the metrics of a new connection")
+	{
+		_, err := transport.request("GET", "/1/isalive", nil, read, nil)
+		require.Nil(t, err)
+
+		mu.Lock()
+		require.Equal(t, []float64{1}, metrics[ConnectionsOpenMetric])
+		require.Equal(t, []float64{1}, metrics[ConnectionsNewMetric])
+		require.Len(t, metrics[ConnectMetric], 1)
+		require.Len(t, metrics[TLSHandshakeMetric], 1)
+		require.Nil(t, metrics[ConnectionsReusedMetric])
+		mu.Unlock()
+	}
+
+	t.Log("TestTransport_Metrics: Check that the reuse of the connection is reported")
+	{
+		_, err := transport.request("GET", "/1/isalive", nil, read, nil)
+		require.Nil(t, err)
+
+		mu.Lock()
+		require.Equal(t, []float64{1}, metrics[ConnectionsReusedMetric])
+		require.Equal(t, []float64{1}, metrics[ConnectionsNewMetric])
+		mu.Unlock()
+	}
+
+	t.Log("TestTransport_Metrics: Check that closed connections are reported")
+	{
+		transport.httpClient.Transport.(*http.Transport).CloseIdleConnections()
+
+		mu.Lock()
+		require.Equal(t, []float64{1, 0}, metrics[ConnectionsOpenMetric])
+		mu.Unlock()
+	}
+
+	t.Log("TestTransport_Metrics: Check that the given HTTP client is left untouched")
+	{
+		httpClient := &http.Client{Transport: &http.Transport{}}
+		transport.setHTTPClient(httpClient)
+		transport.setMetricsHook(nil)
+		require.False(t, transport.httpClient == httpClient, "should copy the HTTP client before configuring it")
+		require.NotNil(t, transport.httpClient.Transport.(*http.Transport).DialContext)
+		require.Nil(t, httpClient.Transport.(*http.Transport).DialContext)
+	}
+}
+
+// countingRequester is a Requester counting the requests it sends.
+type countingRequester struct {
+	client *http.Client
+	calls  int
+}
+
+func (r *countingRequester) Do(req *http.Request) (*http.Response, error) {
+	r.calls++
+	return r.client.Do(req)
+}
+
+func TestTransport_Requester(t *testing.T) {
+	t.Log("TestTransport_Requester: Start a server answering slowly to /slow")
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	transport := newTestTransport(server)
+	requester := &countingRequester{client: transport.httpClient}
+
+	t.Log("TestTransport_Requester: Check that the requests are sent by the requester")
+	{
+		transport.setRequester(requester)
+		_, err := transport.request("GET", "/1/isalive", nil, read, nil)
+		require.Nil(t, err)
+		require.Equal(t, 1, requester.calls)
+	}
+
+	t.Log("TestTransport_Requester: Check that the timeouts still apply")
+	{
+		transport.setTimeouts(Timeouts{Read: 50 * time.Millisecond, Total: 100 * time.Millisecond})
+		_, err := transport.request("GET", "/1/isalive", nil, read, nil)
+		require.Nil(t, err, "should read the whole response body")
+
+		start := time.Now()
+		_, err = transport.request("GET", "/slow", nil, read, nil)
+		require.NotNil(t, err)
+		require.True(t, time.Since(start) < 200*time.Millisecond, "should not wait for the response")
+	}
+
+	t.Log("TestTransport_Requester: Check that an *http.Client replaces the requester")
+	{
+		httpClient := requester.client
+		transport.setRequester(httpClient)
+		require.Nil(t, transport.requester)
+		require.True(t, transport.httpClient == httpClient)
+	}
+
+	t.Log("TestTransport_Requester: Check that the read timeout applies to any RoundTripper")
+	{
+		tripper := &countingRoundTripper{transport: requester.client.Transport}
+		transport.setRequester(&http.Client{Transport: tripper})
+		transport.setTimeouts(Timeouts{Read: 50 * time.Millisecond})
+
+		start := time.Now()
+		_, err := transport.request("GET", "/slow", nil, read, nil)
+		require.NotNil(t, err)
+		require.True(t, time.Since(start) < 200*time.Millisecond, "should not wait for the response")
+		require.NotZero(t, tripper.calls, "should send the requests with the RoundTripper")
+	}
+}
+
+// countingRoundTripper is an http.RoundTripper counting the requests it
+// sends.
+type countingRoundTripper struct {
+	transport http.RoundTripper
+	calls     int
+}
+
+func (r *countingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.calls++
+	return r.transport.RoundTrip(req)
+}
+
+func TestTransport_SharedHostsState(t *testing.T) {
+	t.Log("TestTransport_SharedHostsState: Start a failing server and a healthy one")
+	failing := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"message":"Unavailable","status":503}`))
+	}))
+	defer failing.Close()
+	healthy := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer healthy.Close()
+
+	failingHost := strings.TrimPrefix(failing.URL, "https://")
+	healthyHost := strings.TrimPrefix(healthy.URL, "https://")
+	newTransport := func() *Transport {
+		transport := newTransportWithOnlyHosts("shared", "apikey", []string{failingHost, healthyHost})
+		transport.httpClient = newTestTransport(healthy).httpClient
+		return transport
+	}
+
+	t.Log("TestTransport_SharedHostsState: Check that a failing host is tried last by all the clients")
+	{
+		a, b := newTransport(), newTransport()
+		require.Equal(t, []string{failingHost, healthyHost}, b.hostsToTry(write))
+
+		_, err := a.request("GET", "/1/isalive", nil, read, nil)
+		require.Nil(t, err)
+
+		require.Equal(t, []string{healthyHost, healthyHost, failingHost}, b.hostsToTry(read))
+		require.Equal(t, []string{healthyHost, failingHost}, b.hostsToTry(write))
+	}
+
+	t.Log("TestTransport_SharedHostsState: Check that the connection pool is shared until configured")
+	{
+		a, b := NewTransport("shared", "apikey"), NewTransport("shared", "apikey")
+		require.True(t, a.httpClient == b.httpClient, "should share the HTTP client")
+
+		a.setMaxIdleConnsPerHost(8)
+		require.False(t, a.httpClient == b.httpClient, "should copy the HTTP client before configuring it")
+		require.Equal(t, 8, a.httpClient.Transport.(*http.Transport).MaxIdleConnsPerHost)
+		require.Equal(t, 64, b.httpClient.Transport.(*http.Transport).MaxIdleConnsPerHost)
+	}
+}
+
+func TestTransport_Debug(t *testing.T) {
+	t.Log("TestTransport_Debug: Start a server failing the first request")
+	calls := 0
+	var keys string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"message":"Internal error","status":500}`))
+			return
+		}
+		if strings.HasPrefix(r.URL.Path, "/1/keys") {
+			w.Write([]byte(keys))
+			return
+		}
+		w.Write([]byte(`{"hits":[]}`))
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "https://")
+	transport := newTestTransport(server)
+	transport.providedHosts = []string{host, host}
+	transport.onlyProvidedHosts = true
+
+	var buf bytes.Buffer
+	transport.setDebug(&buf)
+
+	t.Log("TestTransport_Debug: Check that the requests and responses are dumped")
+	{
+		opts := &RequestOptions{ExtraUrlParams: map[string]string{"x-algolia-api-key": "secret"}}
+		_, err := transport.request("POST", "/1/indexes/test/query", Map{"params": strings.Repeat("a", debugBodyLimit)}, search, opts)
+		require.Nil(t, err)
+
+		dump := buf.String()
+		require.False(t, strings.Contains(dump, "apikey"), "should redact the API key header")
+		require.False(t, strings.Contains(dump, "secret"), "should redact the API key parameter")
+		require.Contains(t, dump, "X-Algolia-Api-Key: "+redacted)
+		require.Contains(t, dump, "[algolia] --> POST https://"+host+"/1/indexes/test/query?x-algolia-api-key=%3Credacted%3E (attempt 1)")
+		require.Contains(t, dump, "[algolia] <-- 500 Internal Server Error POST")
+		require.Contains(t, dump, "(attempt 2)")
+		require.Contains(t, dump, `{"hits":[]}`)
+		require.Contains(t, dump, "... (13 bytes truncated)")
+	}
+
+	t.Log("TestTransport_Debug: Check that the API keys are redacted from the keys routes")
+	{
+		buf.Reset()
+		keys = `{"keys":[{"value":"key-one","acl":["search"]},{"value":"key-two","acl":[]}]}`
+		_, err := transport.request("GET", "/1/keys", nil, read, nil)
+		require.Nil(t, err)
+
+		keys = `{"key":"key-three","createdAt":"2017-01-01T00:00:00Z"}`
+		_, err = transport.request("POST", "/1/keys/", Map{"acl": []string{"search"}}, write, nil)
+		require.Nil(t, err)
+
+		keys = `{"value":"key-four","acl":["search"]}`
+		_, err = transport.request("GET", "/1/keys/key-four", nil, read, nil)
+		require.Nil(t, err)
+
+		dump := buf.String()
+		require.NotContains(t, dump, "key-one")
+		require.NotContains(t, dump, "key-two")
+		require.NotContains(t, dump, "key-three")
+		require.NotContains(t, dump, "key-four")
+		require.Contains(t, dump, `"value":"`+redacted+`"`)
+		require.Contains(t, dump, "/1/keys/%3Credacted%3E")
+		require.Contains(t, dump, `"createdAt":"2017-01-01T00:00:00Z"`)
+	}
+
+	t.Log("TestTransport_Debug: Check that the dumps can be disabled")
+	{
+		buf.Reset()
+		transport.setDebug(nil)
+		_, err := transport.request("GET", "/1/isalive", nil, read, nil)
+		require.Nil(t, err)
+		require.Equal(t, 0, buf.Len())
+	}
+}
+
+// newTestTransport returns a new Transport whose first host to try is the
+// given test `server`, whose certificate is not verified.
+func newTestTransport(server *httptest.Server) *Transport {
+	host := strings.TrimPrefix(server.URL, "https://")
+	transport := NewTransportWithHosts("appid", "apikey", []string{host})
+	transport.httpClient = &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+	return transport
+}
@@ -0,0 +1,74 @@
+package algoliasearch
+
+// Statuses of the A/B tests of the Analytics API.
+const (
+	ABTestStatusActive  string = "active"
+	ABTestStatusStopped string = "stopped"
+	ABTestStatusExpired string = "expired"
+	ABTestStatusFailed  string = "failed"
+)
+
+// ABTest is the definition of an A/B test to create with
+// AnalyticsClient.AddABTest. `EndAt` is a RFC 3339 date and the traffic
+// percentages of the two variants should add up to 100.
+type ABTest struct {
+	Name     string          `json:"name"`
+	Variants []ABTestVariant `json:"variants"`
+	EndAt    string          `json:"endAt"`
+}
+
+// ABTestVariant is a variant of an ABTest: the index the search queries are
+// sent to and the share of the traffic it receives.
+type ABTestVariant struct {
+	Index                  string `json:"index"`
+	TrafficPercentage      int    `json:"trafficPercentage"`
+	Description            string `json:"description,omitempty"`
+	CustomSearchParameters Map    `json:"customSearchParameters,omitempty"`
+}
+
+// ABTestRes is an A/B test as returned by the Analytics API, along with its
+// results. The significances are nil as long as not enough searches were
+// performed to compute them.
+type ABTestRes struct {
+	ABTestID               int                `json:"abTestID"`
+	Name                   string             `json:"name"`
+	Status                 string             `json:"status"`
+	CreatedAt              string             `json:"createdAt"`
+	EndAt                  string             `json:"endAt"`
+	ClickSignificance      *float64           `json:"clickSignificance"`
+	ConversionSignificance *float64           `json:"conversionSignificance"`
+	Variants               []ABTestVariantRes `json:"variants"`
+}
+
+// ABTestVariantRes is a variant of an ABTestRes along with its metrics. The
+// rates are nil until the variant received tracked searches.
+type ABTestVariantRes struct {
+	Index                  string   `json:"index"`
+	TrafficPercentage      int      `json:"trafficPercentage"`
+	Description            string   `json:"description"`
+	CustomSearchParameters Map      `json:"customSearchParameters,omitempty"`
+	SearchCount            int      `json:"searchCount"`
+	TrackedSearchCount     int      `json:"trackedSearchCount"`
+	UserCount              int      `json:"userCount"`
+	NoResultCount          int      `json:"noResultCount"`
+	ClickCount             int      `json:"clickCount"`
+	ConversionCount        int      `json:"conversionCount"`
+	AverageClickPosition   *float64 `json:"averageClickPosition"`
+	ClickThroughRate       *float64 `json:"clickThroughRate"`
+	ConversionRate         *float64 `json:"conversionRate"`
+}
+
+type GetABTestsRes struct {
+	ABTests []ABTestRes `json:"abtests"`
+	Count   int         `json:"count"`
+	Total   int         `json:"total"`
+}
+
+// ABTestTaskRes is returned by the AnalyticsClient methods modifying an A/B
+// test. The `TaskID` belongs to the `Index`, which is the index of the first
+// variant of the test.
+type ABTestTaskRes struct {
+	ABTestID int    `json:"abTestID"`
+	Index    string `json:"index"`
+	TaskID   int    `json:"taskID"`
+}
@@ -1,6 +1,21 @@
 package algoliasearch
 
-import "errors"
+import (
+	"encoding/json"
+	"errors"
+)
+
+// Actions which can be performed by the operations of a batch request (see
+// BatchOperation.Action).
+const (
+	ActionAddObject                   = "addObject"
+	ActionUpdateObject                = "updateObject"
+	ActionPartialUpdateObject         = "partialUpdateObject"
+	ActionPartialUpdateObjectNoCreate = "partialUpdateObjectNoCreate"
+	ActionDeleteObject                = "deleteObject"
+	ActionClear                       = "clear"
+	ActionDelete                      = "delete"
+)
 
 type BatchOperation struct {
 	Action string      `json:"action"`
@@ -12,14 +27,140 @@ type BatchOperationIndexed struct {
 	IndexName string `json:"indexName"`
 }
 
+// BatchRes is the response of a batch request. `Operations` holds the
+// outcome of each operation of the batch, in order: if the request failed,
+// all of them hold the error, so that the failed operations can be retried
+// (see FailedOperations). `Duplicates` lists the objectIDs which were
+// duplicated in the records given to Index.AddObjects or Index.UpdateObjects
+// in DuplicatesLastWriteWins mode.
 type BatchRes struct {
-	ObjectIDs []string `json:"objectIDs"`
-	TaskID    int      `json:"taskID"`
+	ObjectIDs  []string            `json:"objectIDs"`
+	TaskID     int                 `json:"taskID"`
+	Operations []BatchOperationRes `json:"-"`
+	Duplicates []string            `json:"-"`
+}
+
+// FailedOperations returns the outcomes of the operations of the batch which
+// failed.
+func (r BatchRes) FailedOperations() []BatchOperationRes {
+	return failedOperations(r.Operations)
+}
+
+// BatchOperationRes is the outcome of a single operation of a batch.
+// `ObjectID` is the objectID of the record targeted by the operation, which
+// is generated by the engine for `addObject` operations without objectID.
+// `IndexName` is only set for the operations sent with Client.Batch, without
+// the index prefix of the client (see WithIndexPrefix). `Err` is nil if the
+// operation was accepted by the engine.
+type BatchOperationRes struct {
+	Operation BatchOperation
+	IndexName string
+	ObjectID  string
+	Err       error
+}
+
+// newBatchOperationsRes returns the outcomes of the `operations` of a batch
+// request given the objectIDs of the response and the error of the request,
+// if any.
+func newBatchOperationsRes(operations []BatchOperation, objectIDs []string, err error) []BatchOperationRes {
+	res := make([]BatchOperationRes, len(operations))
+	for n, op := range operations {
+		res[n] = BatchOperationRes{Operation: op, Err: err}
+
+		if n < len(objectIDs) {
+			res[n].ObjectID = objectIDs[n]
+		} else {
+			res[n].ObjectID = batchOperationObjectID(op)
+		}
+	}
+	return res
+}
+
+// batchOperationObjectID returns the objectID of the record targeted by the
+// operation, if it is known before sending it. Bodies other than Object and
+// Map (e.g. structs) are encoded to JSON to read their `objectID` field.
+func batchOperationObjectID(op BatchOperation) (objectID string) {
+	switch body := op.Body.(type) {
+	case nil:
+	case Object:
+		objectID, _ = body["objectID"].(string)
+	case Map:
+		objectID, _ = body["objectID"].(string)
+	default:
+		var record struct {
+			ObjectID string `json:"objectID"`
+		}
+		if data, err := json.Marshal(body); err == nil {
+			json.Unmarshal(data, &record)
+		}
+		objectID = record.ObjectID
+	}
+	return
+}
+
+func failedOperations(operations []BatchOperationRes) (failed []BatchOperationRes) {
+	for _, op := range operations {
+		if op.Err != nil {
+			failed = append(failed, op)
+		}
+	}
+	return
+}
+
+// BatchesRes aggregates the responses of the batch requests sent when a set of
+// operations is too large to be sent at once and is split into several
+// chunks.
+type BatchesRes []BatchRes
+
+// ObjectIDs returns the objectIDs of all the chunks, in order.
+func (r BatchesRes) ObjectIDs() (objectIDs []string) {
+	for _, res := range r {
+		objectIDs = append(objectIDs, res.ObjectIDs...)
+	}
+	return
+}
+
+// FailedOperations returns the outcomes of the operations of all the chunks
+// which failed, in order.
+func (r BatchesRes) FailedOperations() (failed []BatchOperationRes) {
+	for _, res := range r {
+		failed = append(failed, res.FailedOperations()...)
+	}
+	return
 }
 
+// TaskIDs returns the taskIDs of all the chunks, in order.
+func (r BatchesRes) TaskIDs() (taskIDs []int) {
+	for _, res := range r {
+		taskIDs = append(taskIDs, res.TaskID)
+	}
+	return
+}
+
+// Wait stops the current execution until the tasks of all the chunks, sent
+// to the `index`, are finished.
+func (r BatchesRes) Wait(index Index) error {
+	return r.WaitWithRequestOptions(index, nil)
+}
+
+// WaitWithRequestOptions is the same as Wait but it also accepts extra
+// RequestOptions.
+func (r BatchesRes) WaitWithRequestOptions(index Index, opts *RequestOptions) error {
+	return index.WaitTasksWithRequestOptions(r.TaskIDs(), opts)
+}
+
+// MultipleBatchRes is the response of Client.Batch. As for BatchRes,
+// `Operations` holds the outcome of each operation of the batch, in order.
 type MultipleBatchRes struct {
-	ObjectIDs []string       `json:"objectIDs"`
-	TaskID    map[string]int `json:"taskID"`
+	ObjectIDs  []string            `json:"objectIDs"`
+	TaskID     map[string]int      `json:"taskID"`
+	Operations []BatchOperationRes `json:"-"`
+}
+
+// FailedOperations returns the outcomes of the operations of the batch which
+// failed.
+func (r MultipleBatchRes) FailedOperations() []BatchOperationRes {
+	return failedOperations(r.Operations)
 }
 
 func newBatchOperations(objects []Object, action string) (operations []BatchOperation, err error) {
@@ -28,7 +169,7 @@ func newBatchOperations(objects []Object, action string) (operations []BatchOper
 	for i, o := range objects {
 		// In the case of something else than `addObject` and `clear` operations,
 		// the `objectID` field is required and has to be escaped.
-		if action != "addObject" && action != "clear" {
+		if action != ActionAddObject && action != ActionClear {
 			if objectID, err := o.ObjectID(); err == nil {
 				o["objectID"] = objectID
 			} else {
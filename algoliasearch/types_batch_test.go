@@ -0,0 +1,142 @@
+package algoliasearch
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchOperationsRes(t *testing.T) {
+	fail := false
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"message":"Record is too big","status":400}`))
+			return
+		}
+
+		var body struct {
+			Requests []BatchOperation `json:"requests"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		var objectIDs []string
+		for n := range body.Requests {
+			objectIDs = append(objectIDs, string(rune('a'+n)))
+		}
+
+		if r.URL.Path == "/1/indexes/*/batch" {
+			json.NewEncoder(w).Encode(MultipleBatchRes{ObjectIDs: objectIDs, TaskID: map[string]int{"products": 42}})
+		} else {
+			json.NewEncoder(w).Encode(BatchRes{ObjectIDs: objectIDs, TaskID: 42})
+		}
+	}))
+	defer server.Close()
+
+	c := &client{transport: newTestTransport(server)}
+	i := c.InitIndex("products")
+
+	t.Log("TestBatchOperationsRes: Check the outcomes of a successful batch")
+	{
+		res, err := i.AddObjects([]Object{{"name": "iPhone"}, {"name": "Galaxy"}})
+		require.Nil(t, err)
+		require.Len(t, res.Operations, 2)
+		require.Equal(t, "b", res.Operations[1].ObjectID)
+		require.Equal(t, "addObject", res.Operations[1].Operation.Action)
+		require.Empty(t, res.FailedOperations())
+	}
+
+	t.Log("TestBatchOperationsRes: Check the outcomes of a failed batch")
+	{
+		fail = true
+		res, err := i.PartialUpdateMany(map[string]Map{"iphone": {"stock": 0}, "galaxy": {"stock": 1}}, false)
+		require.NotNil(t, err)
+		failed := res.FailedOperations()
+		require.Len(t, failed, 2)
+		require.Equal(t, "galaxy", failed[0].ObjectID)
+		require.Equal(t, "iphone", failed[1].ObjectID)
+		require.Equal(t, err, failed[0].Err)
+	}
+
+	t.Log("TestBatchOperationsRes: Check the outcomes of a multiple-index batch")
+	{
+		fail = false
+		res, err := c.Batch([]BatchOperationIndexed{
+			{IndexName: "products", BatchOperation: BatchOperation{Action: "deleteObject", Body: Map{"objectID": "iphone"}}},
+			{IndexName: "articles", BatchOperation: BatchOperation{Action: "clear"}},
+		})
+		require.Nil(t, err)
+		require.Equal(t, "products", res.Operations[0].IndexName)
+		require.Equal(t, "articles", res.Operations[1].IndexName)
+		require.Empty(t, res.FailedOperations())
+	}
+}
+
+func TestBatchOperationObjectID(t *testing.T) {
+	t.Log("TestBatchOperationObjectID: Check that the objectID is read from any body")
+	type product struct {
+		ObjectID string `json:"objectID"`
+		Name     string `json:"name"`
+	}
+
+	for _, c := range []struct {
+		body     interface{}
+		objectID string
+	}{
+		{Object{"objectID": "one"}, "one"},
+		{Map{"objectID": "two"}, "two"},
+		{map[string]interface{}{"objectID": "three"}, "three"},
+		{product{ObjectID: "four", Name: "iPhone"}, "four"},
+		{&product{ObjectID: "five"}, "five"},
+		{product{Name: "Galaxy"}, ""},
+		{nil, ""},
+	} {
+		require.Equal(t, c.objectID, batchOperationObjectID(BatchOperation{Action: "updateObject", Body: c.body}))
+	}
+}
+
+func TestBatchOperationsCheck(t *testing.T) {
+	var requests int
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"taskID":1,"objectIDs":[]}`))
+	}))
+	defer server.Close()
+	c := &client{transport: newTestTransport(server)}
+	index := c.InitIndex("products")
+
+	t.Log("TestBatchOperationsCheck: Check that invalid operations are rejected before being sent")
+	{
+		for _, tc := range []struct {
+			op  BatchOperation
+			err string
+		}{
+			{BatchOperation{Action: "addObjects", Body: Object{"name": "typo"}}, `Invalid batch operation #0: unknown action "addObjects"`},
+			{BatchOperation{Action: ActionAddObject}, `Invalid "addObject" batch operation #0: the body is missing`},
+			{BatchOperation{Action: ActionDeleteObject, Body: Map{"name": "no objectID"}}, "Invalid \"deleteObject\" batch operation #0: Cannot extract `objectID` field from Object"},
+			{BatchOperation{Action: ActionPartialUpdateObject, Body: map[string]interface{}{"objectID": 1}}, "Invalid \"partialUpdateObject\" batch operation #0: Cannot cast `objectID` field to string type"},
+		} {
+			_, err := index.Batch([]BatchOperation{tc.op})
+			require.EqualError(t, err, tc.err)
+		}
+
+		_, err := c.Batch([]BatchOperationIndexed{{BatchOperation: BatchOperation{Action: ActionClear}}})
+		require.EqualError(t, err, "Invalid batch operation #0: the index name is missing")
+		require.Equal(t, 0, requests)
+	}
+
+	t.Log("TestBatchOperationsCheck: Check that valid operations are sent")
+	{
+		_, err := index.Batch([]BatchOperation{
+			{Action: ActionAddObject, Body: struct{ Name string }{"struct"}},
+			{Action: ActionUpdateObject, Body: struct{ ObjectID string }{"unchecked"}},
+			{Action: ActionDeleteObject, Body: Object{"objectID": "1"}},
+			{Action: ActionClear},
+		})
+		require.Nil(t, err)
+		require.Equal(t, 1, requests)
+	}
+}
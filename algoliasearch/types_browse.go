@@ -1,7 +1,30 @@
 package algoliasearch
 
+import "encoding/json"
+
 type BrowseRes struct {
 	Cursor  string `json:"cursor"`
 	Warning string `json:"warning"`
 	QueryRes
 }
+
+func (r *BrowseRes) UnmarshalJSON(data []byte) error {
+	// The embedded QueryRes is decoded on its own as its UnmarshalJSON method
+	// would otherwise be promoted and decode it only.
+	if err := json.Unmarshal(data, &r.QueryRes); err != nil {
+		return err
+	}
+
+	aux := struct {
+		Cursor  string `json:"cursor"`
+		Warning string `json:"warning"`
+	}{}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	r.Cursor = aux.Cursor
+	r.Warning = aux.Warning
+	return nil
+}
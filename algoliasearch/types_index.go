@@ -1,15 +1,24 @@
 package algoliasearch
 
 type IndexRes struct {
-	CreatedAt           string `json:"createdAt"`
-	DataSize            int    `json:"dataSize"`
-	Entries             int    `json:"entries"`
-	FileSize            int    `json:"fileSize"`
-	LastBuildTimeS      int    `json:"lastBuildTimeS"`
-	Name                string `json:"name"`
-	NumberOfPendingTask int    `json:"numberOfPendingTask"`
-	PendingTask         bool   `json:"pendingTask"`
-	UpdatedAt           string `json:"updatedAt"`
+	CreatedAt           string   `json:"createdAt"`
+	DataSize            int      `json:"dataSize"`
+	Entries             int      `json:"entries"`
+	FileSize            int      `json:"fileSize"`
+	LastBuildTimeS      int      `json:"lastBuildTimeS"`
+	Name                string   `json:"name"`
+	NumberOfPendingTask int      `json:"numberOfPendingTasks"`
+	PendingTask         bool     `json:"pendingTask"`
+	Primary             string   `json:"primary,omitempty"`
+	Replicas            []string `json:"replicas,omitempty"`
+	UpdatedAt           string   `json:"updatedAt"`
+}
+
+// ListIndexesRes is the structure returned by `ListIndexesByPage`. `NbPages`
+// is the total number of pages of indexes available for the application.
+type ListIndexesRes struct {
+	Items   []IndexRes `json:"items"`
+	NbPages int        `json:"nbPages"`
 }
 
 type listIndexesRes struct {
@@ -0,0 +1,89 @@
+package algoliasearch
+
+// Statuses of the runs of the Ingestion API.
+const (
+	RunStatusCreated  string = "created"
+	RunStatusStarted  string = "started"
+	RunStatusIdled    string = "idled"
+	RunStatusFinished string = "finished"
+	RunStatusSkipped  string = "skipped"
+)
+
+// Outcomes of the finished runs of the Ingestion API.
+const (
+	RunOutcomeSuccess string = "success"
+	RunOutcomeFailure string = "failure"
+)
+
+// Run is a single execution of a task of the Ingestion API.
+type Run struct {
+	RunID      string       `json:"runID"`
+	AppID      string       `json:"appID"`
+	TaskID     string       `json:"taskID"`
+	Status     string       `json:"status"`
+	Progress   *RunProgress `json:"progress,omitempty"`
+	Outcome    string       `json:"outcome,omitempty"`
+	Reason     string       `json:"reason,omitempty"`
+	ReasonCode string       `json:"reasonCode,omitempty"`
+	Type       string       `json:"type"`
+	CreatedAt  string       `json:"createdAt"`
+	StartedAt  string       `json:"startedAt,omitempty"`
+	FinishedAt string       `json:"finishedAt,omitempty"`
+}
+
+// Failed returns `true` if the run is finished and did not succeed.
+func (r Run) Failed() bool {
+	return r.Status == RunStatusFinished && r.Outcome == RunOutcomeFailure
+}
+
+// RunProgress describes how many events of a run were processed.
+type RunProgress struct {
+	ExpectedNbOfEvents int `json:"expectedNbOfEvents"`
+	ReceivedNbOfEvents int `json:"receivedNbOfEvents"`
+}
+
+// Event is a single operation (e.g. a batch of records) processed by a run
+// of the Ingestion API.
+type Event struct {
+	EventID     string `json:"eventID"`
+	RunID       string `json:"runID"`
+	ParentID    string `json:"parentID,omitempty"`
+	Status      string `json:"status"`
+	Type        string `json:"type"`
+	BatchSize   int    `json:"batchSize"`
+	Data        Map    `json:"data,omitempty"`
+	PublishedAt string `json:"publishedAt"`
+}
+
+// IngestionPagination describes the page of results returned by the listing
+// methods of the IngestionClient.
+type IngestionPagination struct {
+	NbPages      int `json:"nbPages"`
+	Page         int `json:"page"`
+	NbItems      int `json:"nbItems"`
+	ItemsPerPage int `json:"itemsPerPage"`
+}
+
+// IngestionWindow is the time window the results returned by the listing
+// methods of the IngestionClient belong to.
+type IngestionWindow struct {
+	StartDate string `json:"startDate"`
+	EndDate   string `json:"endDate"`
+}
+
+type ListRunsRes struct {
+	Runs       []Run               `json:"runs"`
+	Pagination IngestionPagination `json:"pagination"`
+	Window     IngestionWindow     `json:"window"`
+}
+
+type ListEventsRes struct {
+	Events     []Event             `json:"events"`
+	Pagination IngestionPagination `json:"pagination"`
+	Window     IngestionWindow     `json:"window"`
+}
+
+type RunTaskRes struct {
+	RunID     string `json:"runID"`
+	CreatedAt string `json:"createdAt"`
+}
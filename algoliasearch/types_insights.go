@@ -0,0 +1,52 @@
+package algoliasearch
+
+// Types of the events of the Insights API.
+const (
+	InsightsEventClick      string = "click"
+	InsightsEventConversion string = "conversion"
+	InsightsEventView       string = "view"
+)
+
+// InsightsEvent is an event sent to the Insights API, describing how a user
+// interacted with records or filters. Either `ObjectIDs` or `Filters` should
+// be set. Events related to a search (i.e. with a `QueryID`, see
+// ClickAnalytics) should be clicks or conversions; clicks related to a search
+// also need the `Positions` of the clicked records. `Timestamp` is in
+// milliseconds since the UNIX epoch and defaults to the time the event is
+// received by the API.
+type InsightsEvent struct {
+	EventType string   `json:"eventType"`
+	EventName string   `json:"eventName"`
+	Index     string   `json:"index"`
+	UserToken string   `json:"userToken"`
+	Timestamp int64    `json:"timestamp,omitempty"`
+	QueryID   string   `json:"queryID,omitempty"`
+	ObjectIDs []string `json:"objectIDs,omitempty"`
+	Positions []int    `json:"positions,omitempty"`
+	Filters   []string `json:"filters,omitempty"`
+}
+
+// NewClickEvent returns a click event on the given `hits` of a search, as
+// returned by QueryRes.HitPositions. All the hits should belong to the same
+// search.
+func NewClickEvent(eventName, index, userToken string, hits ...HitPosition) InsightsEvent {
+	event := InsightsEvent{
+		EventType: InsightsEventClick,
+		EventName: eventName,
+		Index:     index,
+		UserToken: userToken,
+	}
+
+	for _, hit := range hits {
+		event.QueryID = hit.QueryID
+		event.ObjectIDs = append(event.ObjectIDs, hit.ObjectID)
+		event.Positions = append(event.Positions, hit.Position)
+	}
+
+	return event
+}
+
+type InsightsRes struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
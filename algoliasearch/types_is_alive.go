@@ -0,0 +1,5 @@
+package algoliasearch
+
+type isAliveRes struct {
+	Message string `json:"message"`
+}
@@ -1,15 +1,146 @@
 package algoliasearch
 
+import (
+	"strings"
+	"time"
+)
+
+// Key is an API key. `Validity` is the validity of the key, in seconds (0 if
+// the key never expires) and `ValidUntil` the matching expiration date, i.e.
+// `Validity` seconds after `CreatedAt`, set when the key is decoded.
 type Key struct {
 	ACL                    []string `json:"acl"`
 	CreatedAt              int      `json:"createdAt,omitempty"`
 	Description            string   `json:"description,omitempty"`
+	Indexes                []string `json:"indexes,omitempty"`
 	MaxHitsPerQuery        int      `json:"maxHitsPerQuery,omitempty"`
 	MaxQueriesPerIPPerHour int      `json:"maxQueriesPerIPPerHour,omitempty"`
 	QueryParamaters        string   `json:"queryParameters,omitempty"`
 	Referers               []string `json:"referers,omitempty"`
 	Validity               int      `json:"validity,omitempty"`
 	Value                  string   `json:"value,omitempty"`
+
+	ValidUntil time.Time `json:"-"`
+}
+
+// IsExpired returns `true` if the key has an expiration date which is past.
+func (k Key) IsExpired() bool {
+	return !k.ValidUntil.IsZero() && !time.Now().Before(k.ValidUntil)
+}
+
+// validitySeconds converts the given validity to the number of seconds
+// expected by the API, rounded up so that a positive validity never becomes
+// 0 (i.e. no expiration).
+func validitySeconds(validity time.Duration) int {
+	return int((validity + time.Second - 1) / time.Second)
+}
+
+// encodeKeyParams returns the parameters of an API key with their
+// `validity`, if given as a time.Duration, converted to seconds.
+func encodeKeyParams(params Map) Map {
+	validity, ok := params["validity"].(time.Duration)
+	if !ok {
+		return params
+	}
+
+	encoded := duplicateMap(params)
+	encoded["validity"] = validitySeconds(validity)
+	return encoded
+}
+
+// ACL is a permission granted to an API key.
+type ACL string
+
+// ACLs which can be granted to the API keys.
+const (
+	ACLSearch                     ACL = "search"
+	ACLBrowse                     ACL = "browse"
+	ACLAddObject                  ACL = "addObject"
+	ACLDeleteObject               ACL = "deleteObject"
+	ACLListIndexes                ACL = "listIndexes"
+	ACLDeleteIndex                ACL = "deleteIndex"
+	ACLSettings                   ACL = "settings"
+	ACLEditSettings               ACL = "editSettings"
+	ACLAnalytics                  ACL = "analytics"
+	ACLRecommendation             ACL = "recommendation"
+	ACLUsage                      ACL = "usage"
+	ACLLogs                       ACL = "logs"
+	ACLSeeUnretrievableAttributes ACL = "seeUnretrievableAttributes"
+)
+
+// acls lists all the valid ACLs.
+var acls = []ACL{
+	ACLSearch,
+	ACLBrowse,
+	ACLAddObject,
+	ACLDeleteObject,
+	ACLListIndexes,
+	ACLDeleteIndex,
+	ACLSettings,
+	ACLEditSettings,
+	ACLAnalytics,
+	ACLRecommendation,
+	ACLUsage,
+	ACLLogs,
+	ACLSeeUnretrievableAttributes,
+}
+
+// KeyParams are the parameters of the API keys created or updated with
+// Client.AddAPIKeyWithParams and Client.UpdateAPIKeyWithParams. `ACL` is
+// mandatory while the zero values of the other fields are not sent.
+// `Indexes` and `Referers` accept `*` wildcards (e.g. `dev_*` or
+// `https://algolia.com/*`), `RestrictSources` lists the IP addresses or CIDR
+// networks (e.g. `192.168.1.0/24`) the key can be used from,
+// `QueryParameters` are the search parameters enforced on every query made
+// with the key and `Validity` is the duration after which the key expires
+// (sent as a number of seconds, rounded up).
+type KeyParams struct {
+	ACL                    []ACL
+	Description            string
+	MaxQueriesPerIPPerHour int
+	MaxHitsPerQuery        int
+	Indexes                []string
+	Referers               []string
+	RestrictSources        []string
+	QueryParameters        Map
+	Validity               time.Duration
+}
+
+// toMap returns the parameters as expected by Client.AddAPIKey and
+// Client.UpdateAPIKey, along with the ACLs as strings.
+func (p KeyParams) toMap() (acl []string, params Map) {
+	acl = make([]string, len(p.ACL))
+	for n, a := range p.ACL {
+		acl[n] = string(a)
+	}
+
+	params = Map{}
+	if p.Description != "" {
+		params["description"] = p.Description
+	}
+	if p.MaxQueriesPerIPPerHour != 0 {
+		params["maxQueriesPerIPPerHour"] = p.MaxQueriesPerIPPerHour
+	}
+	if p.MaxHitsPerQuery != 0 {
+		params["maxHitsPerQuery"] = p.MaxHitsPerQuery
+	}
+	if len(p.Indexes) > 0 {
+		params["indexes"] = p.Indexes
+	}
+	if len(p.Referers) > 0 {
+		params["referers"] = p.Referers
+	}
+	if len(p.RestrictSources) > 0 {
+		params["restrictSources"] = strings.Join(p.RestrictSources, ";")
+	}
+	if len(p.QueryParameters) > 0 {
+		params["queryParameters"] = encodeMap(p.QueryParameters)
+	}
+	if p.Validity != 0 {
+		params["validity"] = validitySeconds(p.Validity)
+	}
+
+	return
 }
 
 type listKeysRes struct {
@@ -0,0 +1,117 @@
+package algoliasearch
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyParams(t *testing.T) {
+	t.Log("TestKeyParams: Check the validation of the parameters")
+	{
+		require.Nil(t, checkKeyParams(KeyParams{ACL: []ACL{ACLSearch, ACLBrowse}}))
+		require.NotNil(t, checkKeyParams(KeyParams{}), "should require an ACL")
+		require.NotNil(t, checkKeyParams(KeyParams{ACL: []ACL{"serach"}}), "should reject unknown ACLs")
+		require.NotNil(t, checkKeyParams(KeyParams{ACL: []ACL{ACLSearch}, Validity: -1}))
+		require.NotNil(t, checkKeyParams(KeyParams{ACL: []ACL{ACLSearch}, MaxHitsPerQuery: -1}))
+		require.NotNil(t, checkKeyParams(KeyParams{ACL: []ACL{ACLSearch}, QueryParameters: Map{"hitsPerPage": "10"}}))
+
+		require.Nil(t, checkKey(Map{"acl": []string{"search", "personalization"}}), "should leave the ACLs of untyped keys to the API")
+		require.NotNil(t, checkKey(Map{"acl": "search"}))
+	}
+
+	t.Log("TestKeyParams: Check the validation of the network restrictions")
+	{
+		require.Nil(t, checkKeyParams(KeyParams{
+			ACL:             []ACL{ACLSearch},
+			RestrictSources: []string{"192.168.1.0/24", "10.0.0.1", "2001:db8::/32"},
+			Referers:        []string{"https://algolia.com/*", "*.algolia.com"},
+		}))
+		require.NotNil(t, checkKeyParams(KeyParams{ACL: []ACL{ACLSearch}, RestrictSources: []string{"192.168.1.0/33"}}))
+		require.NotNil(t, checkKeyParams(KeyParams{ACL: []ACL{ACLSearch}, RestrictSources: []string{"localhost"}}))
+		require.NotNil(t, checkKeyParams(KeyParams{ACL: []ACL{ACLSearch}, Referers: []string{""}}))
+		require.NotNil(t, checkKeyParams(KeyParams{ACL: []ACL{ACLSearch}, Referers: []string{"https://algolia.com/* https://example.com/*"}}))
+
+		require.Nil(t, checkKey(Map{"restrictSources": "192.168.1.0/24;10.0.0.1"}))
+		require.NotNil(t, checkKey(Map{"restrictSources": "192.168.1.0/24,10.0.0.1"}))
+		require.NotNil(t, checkKey(Map{"referers": []string{" "}}))
+		require.NotNil(t, checkGenerateSecuredAPIKey(Map{"restrictSources": "10.0.0"}))
+	}
+
+	t.Log("TestKeyParams: Check the parameters sent to the API")
+	var body Map
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := ioutil.ReadAll(r.Body)
+		body = nil
+		json.Unmarshal(data, &body)
+		w.Write([]byte(`{"key":"abc"}`))
+	}))
+	defer server.Close()
+	c := &client{transport: newTestTransport(server)}
+
+	{
+		res, err := c.AddAPIKeyWithParams(KeyParams{
+			ACL:             []ACL{ACLSearch},
+			Description:     "Search key",
+			Indexes:         []string{"dev_*"},
+			RestrictSources: []string{"192.168.1.0/24", "10.0.0.1"},
+			QueryParameters: Map{"hitsPerPage": 10},
+			Validity:        time.Hour,
+		})
+		require.Nil(t, err)
+		require.Equal(t, "abc", res.Key)
+		require.Equal(t, Map{
+			"acl":             []interface{}{"search"},
+			"description":     "Search key",
+			"indexes":         []interface{}{"dev_*"},
+			"restrictSources": "192.168.1.0/24;10.0.0.1",
+			"queryParameters": "hitsPerPage=10",
+			"validity":        3600.0,
+		}, body)
+
+		_, err = c.UpdateAPIKeyWithParams("abc", KeyParams{ACL: []ACL{ACLBrowse}})
+		require.Nil(t, err)
+		require.Equal(t, Map{"acl": []interface{}{"browse"}}, body)
+
+		_, err = c.AddAPIKeyWithParams(KeyParams{ACL: []ACL{"serach"}})
+		require.NotNil(t, err)
+	}
+}
+
+func TestKeyValidity(t *testing.T) {
+	t.Log("TestKeyValidity: Check the conversion of the validity to seconds")
+	{
+		require.Equal(t, 3600, validitySeconds(time.Hour))
+		require.Equal(t, 1, validitySeconds(time.Millisecond), "should round up")
+		require.Equal(t, Map{"validity": 90}, encodeKeyParams(Map{"validity": 90 * time.Second}))
+		require.Equal(t, Map{"validity": 90}, encodeKeyParams(Map{"validity": 90}))
+		require.Nil(t, checkKey(Map{"validity": time.Minute}))
+		require.NotNil(t, checkKey(Map{"validity": "60"}))
+	}
+
+	t.Log("TestKeyValidity: Check the expiration date of the decoded keys")
+	{
+		data := []byte(`{"value":"abc","createdAt":1499169600,"validity":60}`)
+
+		var k, again Key
+		require.Nil(t, json.Unmarshal(data, &k))
+		require.Equal(t, time.Unix(1499169660, 0), k.ValidUntil)
+		require.True(t, k.IsExpired())
+
+		require.Nil(t, json.Unmarshal(data, &again))
+		require.Equal(t, k, again, "should always decode the same key")
+
+		k.ValidUntil = time.Now().Add(time.Minute)
+		require.False(t, k.IsExpired())
+
+		k = Key{}
+		require.Nil(t, json.Unmarshal([]byte(`{"value":"abc","validity":0}`), &k))
+		require.True(t, k.ValidUntil.IsZero(), "should never expire")
+		require.False(t, k.IsExpired())
+	}
+}
@@ -13,7 +13,7 @@ type LogRes struct {
 	ProcessingTimeMs string `json:"processing_time_ms"`
 	QueryBody        string `json:"query_body"`
 	QueryHeaders     string `json:"query_headers"`
-	QueryNbHits      string `json:":query_nb_hits"`
+	QueryNbHits      string `json:"query_nb_hits"`
 	SHA1             string `json:"sha1"`
 	Timestamp        string `json:"timestamp"`
 	URL              string `json:"url"`
@@ -1,41 +1,266 @@
 package algoliasearch
 
+import (
+	"encoding/json"
+	"time"
+)
+
 type multipleQueriesRes struct {
 	Results []MultipleQueryRes `json:"results"`
 }
 
+// MultipleQueryRes is the result of a query sent with
+// Client.MultipleQueries. `Processed` is `false` if the query was skipped
+// because of the StrategyStopIfEnoughMatches strategy, in which case it has
+// no hits.
 type MultipleQueryRes struct {
-	Index string `json:"index"`
+	Index     string `json:"index"`
+	Processed bool   `json:"processed"`
 	QueryRes
 }
 
+func (r *MultipleQueryRes) UnmarshalJSON(data []byte) error {
+	// The embedded QueryRes is decoded on its own as its UnmarshalJSON method
+	// would otherwise be promoted and decode it only.
+	if err := json.Unmarshal(data, &r.QueryRes); err != nil {
+		return err
+	}
+
+	aux := struct {
+		Index     string `json:"index"`
+		Processed *bool  `json:"processed"`
+	}{}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	r.Index = aux.Index
+
+	// The `processed` field is only sent for skipped queries
+	r.Processed = aux.Processed == nil || *aux.Processed
+	return nil
+}
+
 type QueryRes struct {
-	AroundLatLng          string `json:"aroundLatLng"`
-	AutomaticRadius       string `json:"automaticRadius"`
-	ExhaustiveFacetsCount bool   `json:"exhaustiveFacetsCount"`
-	Facets                Map    `json:"facets"`
-	ExhaustiveNbHits      bool   `json:"exhaustiveNbHits"`
-	FacetsStats           Map    `json:"facets_stats"`
-	Hits                  []Map  `json:"hits"`
-	HitsPerPage           int    `json:"hitsPerPage"`
-	Index                 string `json:"index"`
-	Length                int    `json:"length"`
-	Message               string `json:"message"`
-	NbHits                int    `json:"nbHits"`
-	NbPages               int    `json:"nbPages"`
-	Offset                int    `json:"offset"`
-	Page                  int    `json:"page"`
-	Params                string `json:"params"`
-	ParsedQuery           string `json:"parsedQuery"`
-	ProcessingTimeMS      int    `json:"processingTimeMS"`
-	Query                 string `json:"query"`
-	QueryAfterRemoval     string `json:"queryAfterRemoval"`
-	ServerUsed            string `json:"serverUsed"`
-	TimeoutCounts         bool   `json:"timeoutCounts"`
-	TimeoutHits           bool   `json:"timeoutHits"`
+	ABTestID              int        `json:"abTestID"`        // only set if the query was part of an A/B test
+	ABTestVariantID       int        `json:"abTestVariantID"` // only set if the query was part of an A/B test
+	AroundLatLng          string     `json:"aroundLatLng"`
+	AutomaticRadius       string     `json:"automaticRadius"`
+	ExhaustiveFacetsCount bool       `json:"exhaustiveFacetsCount"`
+	Facets                Map        `json:"facets"`           // see FacetCounts
+	ExhaustiveNbHits      bool       `json:"exhaustiveNbHits"` // `false` if `NbHits` is an approximation
+	ExhaustiveTypo        bool       `json:"exhaustiveTypo"`
+	Exhaustive            Exhaustive `json:"exhaustive"`
+	FacetsStats           Map        `json:"facets_stats"` // see FacetStats
+	Hits                  []Map      `json:"hits"`
+	HitsPerPage           int        `json:"hitsPerPage"`
+	Index                 string     `json:"index"`
+	IndexUsed             string     `json:"indexUsed"` // only set with `getRankingInfo`, e.g. the replica used for a virtual replica
+	Length                int        `json:"length"`    // only set with the offset/length pagination (see OffsetLength)
+	Message               string     `json:"message"`
+	NbHits                int        `json:"nbHits"`
+	NbPages               int        `json:"nbPages"`
+	Offset                int        `json:"offset"` // only set with the offset/length pagination (see OffsetLength)
+	Page                  int        `json:"page"`
+	Params                string     `json:"params"`
+	ParsedQuery           string     `json:"parsedQuery"`
+	ProcessingTimeMS      int        `json:"processingTimeMS"`
+	ProcessingTimingsMS   Map        `json:"processingTimingsMS"` // only set with `getRankingInfo` (see ProcessingTiming)
+	Query                 string     `json:"query"`
+	QueryID               string     `json:"queryID"` // only set if `clickAnalytics` is enabled
+	QueryAfterRemoval     string     `json:"queryAfterRemoval"`
+	ServerUsed            string     `json:"serverUsed"`
+	TimeoutCounts         bool       `json:"timeoutCounts"`
+	TimeoutHits           bool       `json:"timeoutHits"`
+
+	// Relevant sort fields, only set when querying a virtual replica.
+	AppliedRelevancyStrictness int `json:"appliedRelevancyStrictness"`
+	NbSortedHits               int `json:"nbSortedHits"`
+
+	// RenderingContent is the display configuration of the index (see
+	// Settings.RenderingContent), possibly overridden by the rules applied to
+	// the query, to be followed by the user interface.
+	RenderingContent *RenderingContent `json:"renderingContent"`
+
+	// PreSearchHookDuration is the time spent in the PreSearchHook of the
+	// Client, if any, before sending the query.
+	PreSearchHookDuration time.Duration `json:"-"`
+}
+
+// Exhaustive tells which parts of a search response were exhaustively
+// computed: as the engine may stop early for costly queries, some counts
+// can be approximations. It is returned as a whole by the recent versions of
+// the engine while the older ones only return the legacy `ExhaustiveNbHits`,
+// `ExhaustiveFacetsCount` and `ExhaustiveTypo` fields of the QueryRes: both
+// are set from one another when decoding a QueryRes. The flags missing from
+// the response, e.g. `FacetsCount` if no facets were requested, are `true`.
+type Exhaustive struct {
+	FacetsCount bool `json:"facetsCount"`
+	FacetValues bool `json:"facetValues"`
+	NbHits      bool `json:"nbHits"`
+	RulesMatch  bool `json:"rulesMatch"`
+	Typo        bool `json:"typo"`
+}
+
+func (r *QueryRes) UnmarshalJSON(data []byte) error {
+	return r.unmarshalJSON(data, hitsDecoder{maps: &r.Hits})
+}
+
+// unmarshalJSON decodes the response held by `data`, its hits being decoded
+// by `hits`.
+func (r *QueryRes) unmarshalJSON(data []byte, hits hitsDecoder) error {
+	type queryRes QueryRes
+	aux := struct {
+		*queryRes
+		Hits       *hitsDecoder `json:"hits"`
+		Exhaustive struct {
+			FacetsCount *bool `json:"facetsCount"`
+			FacetValues *bool `json:"facetValues"`
+			NbHits      *bool `json:"nbHits"`
+			RulesMatch  *bool `json:"rulesMatch"`
+			Typo        *bool `json:"typo"`
+		} `json:"exhaustive"`
+		ExhaustiveFacetsCount *bool `json:"exhaustiveFacetsCount"`
+		ExhaustiveNbHits      *bool `json:"exhaustiveNbHits"`
+		ExhaustiveTypo        *bool `json:"exhaustiveTypo"`
+	}{queryRes: (*queryRes)(r), Hits: &hits}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	r.Exhaustive = Exhaustive{
+		FacetsCount: exhaustiveFlag(aux.Exhaustive.FacetsCount, aux.ExhaustiveFacetsCount),
+		FacetValues: exhaustiveFlag(aux.Exhaustive.FacetValues, nil),
+		NbHits:      exhaustiveFlag(aux.Exhaustive.NbHits, aux.ExhaustiveNbHits),
+		RulesMatch:  exhaustiveFlag(aux.Exhaustive.RulesMatch, nil),
+		Typo:        exhaustiveFlag(aux.Exhaustive.Typo, aux.ExhaustiveTypo),
+	}
+	r.ExhaustiveFacetsCount = r.Exhaustive.FacetsCount
+	r.ExhaustiveNbHits = r.Exhaustive.NbHits
+	r.ExhaustiveTypo = r.Exhaustive.Typo
+	return nil
+}
+
+// hitsDecoder decodes the hits of a search response either into maps or, for
+// RawQueryRes, into raw JSON messages.
+type hitsDecoder struct {
+	maps *[]Map
+	raw  *[]json.RawMessage
+}
+
+func (d *hitsDecoder) UnmarshalJSON(data []byte) error {
+	if d.raw != nil {
+		return json.Unmarshal(data, d.raw)
+	}
+	return json.Unmarshal(data, d.maps)
 }
 
+// RawQueryRes is the response of Index.SearchRaw. It is the same as QueryRes
+// except that the hits are not decoded, so that they can be forwarded as-is
+// (e.g. to a frontend) or decoded on demand with json.Unmarshal.
+type RawQueryRes struct {
+	QueryRes
+	Hits []json.RawMessage `json:"hits"`
+}
+
+func (r *RawQueryRes) UnmarshalJSON(data []byte) error {
+	return r.QueryRes.unmarshalJSON(data, hitsDecoder{raw: &r.Hits})
+}
+
+// exhaustiveFlag returns the value of an exhaustive flag, taken from the
+// consolidated `exhaustive` object of the response or from the `legacy`
+// field, and `true` if both are missing.
+func exhaustiveFlag(consolidated, legacy *bool) bool {
+	if consolidated != nil {
+		return *consolidated
+	}
+	if legacy != nil {
+		return *legacy
+	}
+	return true
+}
+
+// ProcessingTiming returns the time, in milliseconds, spent by the engine in
+// the processing step designated by `path` in `ProcessingTimingsMS`, e.g.
+// ProcessingTiming("afterFetch", "format", "total") or
+// ProcessingTiming("total"). `ok` is `false` if the timing is not part of the
+// response, which happens if the `getRankingInfo` query parameter was not
+// enabled.
+func (r QueryRes) ProcessingTiming(path ...string) (ms int, ok bool) {
+	var v interface{} = map[string]interface{}(r.ProcessingTimingsMS)
+	for _, k := range path {
+		m, isMap := v.(map[string]interface{})
+		if !isMap {
+			return 0, false
+		}
+		if v, ok = m[k]; !ok {
+			return 0, false
+		}
+	}
+
+	f, ok := v.(float64)
+	return int(f), ok
+}
+
+// FacetCounts returns the typed form of `Facets`: the count of each value of
+// each facet, e.g. FacetCounts()["brand"]["Apple"]. It is nil if no facets
+// were requested.
+func (r QueryRes) FacetCounts() (counts map[string]map[string]int) {
+	if data, err := json.Marshal(r.Facets); err == nil {
+		json.Unmarshal(data, &counts)
+	}
+	return
+}
+
+// FacetStats returns the typed form of `FacetsStats`: the statistics of the
+// values of each requested numeric facet. It is nil if there are none.
+func (r QueryRes) FacetStats() (stats map[string]FacetStat) {
+	if data, err := json.Marshal(r.FacetsStats); err == nil {
+		json.Unmarshal(data, &stats)
+	}
+	return
+}
+
+// UnmarshalHits decodes the hits of the response into `v`, typically a
+// pointer to a slice of structs describing the records of the index.
+func (r QueryRes) UnmarshalHits(v interface{}) error {
+	data, err := json.Marshal(r.Hits)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// UnmarshalHits decodes the undecoded hits of the response into `v`, as
+// QueryRes.UnmarshalHits does.
+func (r RawQueryRes) UnmarshalHits(v interface{}) error {
+	data, err := json.Marshal(r.Hits)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// IndexedQuery is a query sent with Client.MultipleQueries. As the `query`
+// argument of Index.Search, a non-empty `Query` takes precedence over the
+// `query` of the `Params`.
 type IndexedQuery struct {
 	IndexName string
+	Query     string
 	Params    Map
 }
+
+// MultipleQueriesStrategy controls how the queries sent with
+// Client.MultipleQueries are processed.
+type MultipleQueriesStrategy string
+
+const (
+	// StrategyNone processes all the queries (the default).
+	StrategyNone MultipleQueriesStrategy = "none"
+
+	// StrategyStopIfEnoughMatches processes the queries in order and skips
+	// the remaining ones as soon as enough hits (`hitsPerPage`) were found.
+	StrategyStopIfEnoughMatches MultipleQueriesStrategy = "stopIfEnoughMatches"
+)
@@ -0,0 +1,220 @@
+package algoliasearch
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultipleQueryRes(t *testing.T) {
+	var res multipleQueriesRes
+	err := json.Unmarshal([]byte(`{"results": [
+		{"index": "products", "hits": [{"objectID": "iphone", "name": "iPhone", "price": 999}], "nbHits": 1, "queryID": "43b15df305339e827f0ac0bdc5ebcaa7"},
+		{"index": "articles", "hits": [], "processed": false}
+	]}`), &res)
+	require.Nil(t, err)
+	require.Len(t, res.Results, 2)
+
+	t.Log("TestMultipleQueryRes: Check the typed fields")
+	{
+		require.Equal(t, "products", res.Results[0].Index)
+		require.True(t, res.Results[0].Processed)
+		require.Equal(t, 1, res.Results[0].NbHits)
+		require.Equal(t, "43b15df305339e827f0ac0bdc5ebcaa7", res.Results[0].QueryID)
+
+		require.Equal(t, "articles", res.Results[1].Index)
+		require.False(t, res.Results[1].Processed)
+	}
+
+	t.Log("TestMultipleQueryRes: Check the decoding of the hits")
+	{
+		var products []struct {
+			ObjectID string  `json:"objectID"`
+			Name     string  `json:"name"`
+			Price    float64 `json:"price"`
+		}
+		require.Nil(t, res.Results[0].UnmarshalHits(&products))
+		require.Len(t, products, 1)
+		require.Equal(t, "iPhone", products[0].Name)
+		require.Equal(t, 999.0, products[0].Price)
+	}
+}
+
+func TestMultipleQueriesRequest(t *testing.T) {
+	var body struct {
+		Requests []map[string]string `json:"requests"`
+		Strategy string              `json:"strategy"`
+	}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"results": [{"index": "products", "hits": []}]}`))
+	}))
+	defer server.Close()
+	c := &client{transport: newTestTransport(server)}
+
+	t.Log("TestMultipleQueriesRequest: Check the query and the strategy")
+	{
+		queries := []IndexedQuery{{IndexName: "products", Query: "iphone", Params: Map{"query": "ignored", "hitsPerPage": 2}}}
+		_, err := c.MultipleQueries(queries, StrategyStopIfEnoughMatches)
+		require.Nil(t, err)
+		require.Equal(t, "stopIfEnoughMatches", body.Strategy)
+
+		params, err := url.ParseQuery(body.Requests[0]["params"])
+		require.Nil(t, err)
+		require.Equal(t, "iphone", params.Get("query"))
+		require.Equal(t, "ignored", queries[0].Params["query"], "should not modify the given params")
+
+		_, err = c.MultipleQueries(queries, "")
+		require.Nil(t, err)
+		require.Equal(t, "none", body.Strategy)
+
+		_, err = c.MultipleQueries(queries, "stopIfNoMatch")
+		require.NotNil(t, err, "should reject unknown strategies")
+	}
+}
+
+func TestQueryResFacets(t *testing.T) {
+	var res QueryRes
+	err := json.Unmarshal([]byte(`{
+		"hits": [],
+		"facets": {"brand": {"Apple": 12, "Samsung": 7}, "price": {"499": 3, "999": 1}},
+		"facets_stats": {"price": {"min": 499, "max": 999, "avg": 624, "sum": 2496}}
+	}`), &res)
+	require.Nil(t, err)
+	require.Equal(t, map[string]interface{}{"Apple": 12.0, "Samsung": 7.0}, res.Facets["brand"])
+	require.Equal(t, map[string]map[string]int{
+		"brand": {"Apple": 12, "Samsung": 7},
+		"price": {"499": 3, "999": 1},
+	}, res.FacetCounts())
+	require.Equal(t, map[string]FacetStat{
+		"price": {Min: 499, Max: 999, Avg: 624, Sum: 2496},
+	}, res.FacetStats())
+
+	res = QueryRes{}
+	require.Nil(t, res.FacetCounts())
+	require.Nil(t, res.FacetStats())
+}
+
+func TestQueryResExhaustive(t *testing.T) {
+	t.Log("TestQueryResExhaustive: Check the legacy fields")
+	{
+		var res QueryRes
+		require.Nil(t, json.Unmarshal([]byte(`{"hits":[],"exhaustiveNbHits":false,"exhaustiveFacetsCount":true}`), &res))
+		require.False(t, res.ExhaustiveNbHits)
+		require.True(t, res.ExhaustiveFacetsCount)
+		require.True(t, res.ExhaustiveTypo)
+		require.Equal(t, Exhaustive{FacetsCount: true, FacetValues: true, NbHits: false, RulesMatch: true, Typo: true}, res.Exhaustive)
+	}
+
+	t.Log("TestQueryResExhaustive: Check the consolidated object")
+	{
+		var res MultipleQueryRes
+		require.Nil(t, json.Unmarshal([]byte(`{"index":"products","hits":[],"exhaustive":{"nbHits":true,"typo":false,"rulesMatch":false}}`), &res))
+		require.Equal(t, "products", res.Index)
+		require.True(t, res.Processed)
+		require.True(t, res.ExhaustiveNbHits)
+		require.False(t, res.ExhaustiveTypo)
+		require.Equal(t, Exhaustive{FacetsCount: true, FacetValues: true, NbHits: true, RulesMatch: false, Typo: false}, res.Exhaustive)
+	}
+}
+
+func TestQueryResRenderingContent(t *testing.T) {
+	var res QueryRes
+	err := json.Unmarshal([]byte(`{"hits":[],"renderingContent":{"facetOrdering":{
+		"facets": {"order": ["brand", "*"]},
+		"values": {"brand": {"order": ["Apple"], "sortRemainingBy": "count"}}
+	}}}`), &res)
+	require.Nil(t, err)
+	require.Equal(t, &RenderingContent{FacetOrdering: &FacetOrdering{
+		Facets: &FacetsOrder{Order: []string{"brand", "*"}},
+		Values: map[string]FacetValuesOrder{"brand": {Order: []string{"Apple"}, SortRemainingBy: SortRemainingByCount}},
+	}}, res.RenderingContent)
+
+	var empty QueryRes
+	require.Nil(t, json.Unmarshal([]byte(`{"hits":[]}`), &empty))
+	require.Nil(t, empty.RenderingContent)
+}
+
+func TestQueryResRankingInfo(t *testing.T) {
+	var res QueryRes
+	err := json.Unmarshal([]byte(`{
+		"hits": [],
+		"serverUsed": "c4-fr-1.algolia.net",
+		"indexUsed": "products_price_asc",
+		"parsedQuery": "iphone",
+		"timeoutCounts": false,
+		"timeoutHits": true,
+		"processingTimingsMS": {"afterFetch": {"format": {"total": 2}, "total": 3}, "fetch": {"total": 10}, "total": 14}
+	}`), &res)
+	require.Nil(t, err)
+	require.Equal(t, "c4-fr-1.algolia.net", res.ServerUsed)
+	require.Equal(t, "products_price_asc", res.IndexUsed)
+	require.Equal(t, "iphone", res.ParsedQuery)
+	require.False(t, res.TimeoutCounts)
+	require.True(t, res.TimeoutHits)
+
+	ms, ok := res.ProcessingTiming("total")
+	require.True(t, ok)
+	require.Equal(t, 14, ms)
+
+	ms, ok = res.ProcessingTiming("afterFetch", "format", "total")
+	require.True(t, ok)
+	require.Equal(t, 2, ms)
+
+	_, ok = res.ProcessingTiming("afterFetch")
+	require.False(t, ok, "should not return a group of timings")
+
+	_, ok = res.ProcessingTiming("request", "roundTrip")
+	require.False(t, ok, "should not return a missing timing")
+
+	_, ok = QueryRes{}.ProcessingTiming("total")
+	require.False(t, ok)
+}
+
+func TestSearchRaw(t *testing.T) {
+	t.Log("TestSearchRaw: Start a server returning two hits")
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"hits":[{"objectID":"1","email":"a@example.com"},{"objectID":"2"}],"nbHits":2,"exhaustiveNbHits":false}`))
+	}))
+	defer server.Close()
+	index := (&client{transport: newTestTransport(server)}).InitIndex("products")
+
+	t.Log("TestSearchRaw: Check that the hits are left undecoded")
+	{
+		res, err := index.SearchRaw("", nil)
+		require.Nil(t, err)
+		require.Equal(t, 2, res.NbHits)
+		require.False(t, res.Exhaustive.NbHits)
+		require.Nil(t, res.QueryRes.Hits)
+		require.Len(t, res.Hits, 2)
+		require.Equal(t, `{"objectID":"1","email":"a@example.com"}`, string(res.Hits[0]))
+		require.Equal(t, `{"objectID":"2"}`, string(res.Hits[1]))
+
+		var hits []struct {
+			ObjectID string `json:"objectID"`
+		}
+		require.Nil(t, res.UnmarshalHits(&hits))
+		require.Len(t, hits, 2)
+		require.Equal(t, "2", hits[1].ObjectID)
+
+		positions := res.HitPositions()
+		require.Len(t, positions, 2)
+		require.Equal(t, HitPosition{ObjectID: "2", Position: 2}, positions[1])
+
+		data, err := json.Marshal(res)
+		require.Nil(t, err)
+		require.Contains(t, string(data), `"hits":[{"objectID":"1","email":"a@example.com"},{"objectID":"2"}]`)
+	}
+
+	t.Log("TestSearchRaw: Check that the scrubbed attributes are removed")
+	{
+		index.SetScrubbedAttributes([]string{"email"})
+		res, err := index.SearchRaw("", nil)
+		require.Nil(t, err)
+		require.Equal(t, `{"objectID":"1"}`, string(res.Hits[0]))
+	}
+}
@@ -0,0 +1,57 @@
+package algoliasearch
+
+import "encoding/json"
+
+// RankingInfo is the ranking information of a hit, returned in its
+// `_rankingInfo` attribute when the `getRankingInfo` query parameter is
+// enabled. It details how each ranking criterion applied to the hit.
+type RankingInfo struct {
+	Filters             int                         `json:"filters"`
+	FirstMatchedWord    int                         `json:"firstMatchedWord"`
+	GeoDistance         int                         `json:"geoDistance"`
+	GeoPrecision        int                         `json:"geoPrecision"`
+	MatchedGeoLocation  *MatchedGeoLocation         `json:"matchedGeoLocation,omitempty"`
+	NbExactWords        int                         `json:"nbExactWords"`
+	NbTypos             int                         `json:"nbTypos"`
+	Personalization     *PersonalizationRankingInfo `json:"personalization,omitempty"`
+	Promoted            bool                        `json:"promoted"`
+	PromotedByReRanking bool                        `json:"promotedByReRanking"`
+	ProximityDistance   int                         `json:"proximityDistance"`
+	UserScore           int                         `json:"userScore"`
+	Words               int                         `json:"words"`
+}
+
+// MatchedGeoLocation is the geo location of a hit which matched the geo
+// search, along with its distance (in meters) to the search location.
+type MatchedGeoLocation struct {
+	Lat      float64 `json:"lat"`
+	Lng      float64 `json:"lng"`
+	Distance int     `json:"distance"`
+}
+
+// PersonalizationRankingInfo is the part of the ranking of a hit due to the
+// personalization, only set if it was enabled for the query (see
+// Personalization).
+type PersonalizationRankingInfo struct {
+	FiltersScore int `json:"filtersScore"`
+	RankingScore int `json:"rankingScore"`
+	Score        int `json:"score"`
+}
+
+// GetRankingInfo returns the ranking information of the given `hit`. `ok` is
+// `false` if the hit has no valid `_rankingInfo` attribute, which happens if
+// the `getRankingInfo` query parameter was not enabled.
+func GetRankingInfo(hit Map) (info RankingInfo, ok bool) {
+	raw, ok := hit["_rankingInfo"]
+	if !ok {
+		return
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return info, false
+	}
+
+	ok = json.Unmarshal(data, &info) == nil
+	return
+}
@@ -0,0 +1,54 @@
+package algoliasearch
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRankingInfo(t *testing.T) {
+	t.Log("TestGetRankingInfo: Decode the ranking information of a hit")
+	{
+		var hit Map
+		err := json.Unmarshal([]byte(`{
+			"objectID": "42",
+			"_rankingInfo": {
+				"nbTypos": 1,
+				"firstMatchedWord": 0,
+				"proximityDistance": 2,
+				"userScore": 17,
+				"geoDistance": 1200,
+				"geoPrecision": 1,
+				"nbExactWords": 1,
+				"words": 2,
+				"filters": 0,
+				"promoted": true,
+				"promotedByReRanking": true,
+				"personalization": {"filtersScore": 10, "rankingScore": 120, "score": 130},
+				"matchedGeoLocation": {"lat": 48.85, "lng": 2.35, "distance": 1200}
+			}
+		}`), &hit)
+		require.Nil(t, err)
+
+		info, ok := GetRankingInfo(hit)
+		require.True(t, ok)
+		require.Equal(t, 1, info.NbTypos)
+		require.Equal(t, 17, info.UserScore)
+		require.Equal(t, 1200, info.GeoDistance)
+		require.True(t, info.Promoted)
+		require.True(t, info.PromotedByReRanking)
+		require.Equal(t, &PersonalizationRankingInfo{FiltersScore: 10, RankingScore: 120, Score: 130}, info.Personalization)
+		require.NotNil(t, info.MatchedGeoLocation)
+		require.Equal(t, 48.85, info.MatchedGeoLocation.Lat)
+	}
+
+	t.Log("TestGetRankingInfo: Check hits without ranking information")
+	{
+		_, ok := GetRankingInfo(Map{"objectID": "42"})
+		require.False(t, ok)
+
+		_, ok = GetRankingInfo(Map{"_rankingInfo": "invalid"})
+		require.False(t, ok)
+	}
+}
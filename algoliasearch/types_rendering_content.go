@@ -0,0 +1,35 @@
+package algoliasearch
+
+// Ways to sort the facet values which are not explicitly ordered by a
+// FacetValuesOrder.
+const (
+	SortRemainingByCount  string = "count"
+	SortRemainingByAlpha  string = "alpha"
+	SortRemainingByHidden string = "hidden"
+)
+
+// RenderingContent describes how the user interface should display the
+// search results, e.g. the order of the facets and of their values.
+type RenderingContent struct {
+	FacetOrdering *FacetOrdering `json:"facetOrdering,omitempty"`
+}
+
+// FacetOrdering describes the order in which the facets (`Facets`) and the
+// values of each facet (`Values`, by facet name) should be displayed.
+type FacetOrdering struct {
+	Facets *FacetsOrder                `json:"facets,omitempty"`
+	Values map[string]FacetValuesOrder `json:"values,omitempty"`
+}
+
+// FacetsOrder lists the facets to display first, in order (`*` stands for
+// all the other facets).
+type FacetsOrder struct {
+	Order []string `json:"order,omitempty"`
+}
+
+// FacetValuesOrder lists the values of a facet to display first, in order
+// (pinned values), and how the remaining ones are sorted.
+type FacetValuesOrder struct {
+	Order           []string `json:"order,omitempty"`
+	SortRemainingBy string   `json:"sortRemainingBy,omitempty"`
+}
@@ -1,31 +1,141 @@
 package algoliasearch
 
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Rule is an Algolia query rule. A rule with a single condition can use
+// either `Condition` or `Conditions`, while a rule with several conditions
+// has to use `Conditions`, which takes precedence when both are set. When
+// retrieved from the API, single-condition rules always have their
+// `Condition` field set and multiple-condition rules their `Conditions`
+// field set. A rule without any condition is always applied. A nil `Enabled`
+// field leaves the rule enabled.
 type Rule struct {
 	ObjectID        string          `json:"objectID,omitempty"`
 	Condition       RuleCondition   `json:"condition"`
+	Conditions      []RuleCondition `json:"conditions,omitempty"`
 	Consequence     RuleConsequence `json:"consequence"`
 	Description     string          `json:"description,omitempty"`
+	Enabled         *bool           `json:"enabled,omitempty"`
+	Validity        []TimeRange     `json:"validity,omitempty"`
 	HighlightResult Map             `json:"_highlightResult,omitempty"`
 }
 
+func (r Rule) MarshalJSON() ([]byte, error) {
+	type rule Rule
+	aux := struct {
+		rule
+		Condition *RuleCondition `json:"condition,omitempty"`
+	}{rule: rule(r)}
+
+	if len(r.Conditions) == 0 && !r.Condition.isEmpty() {
+		aux.Condition = &r.Condition
+	}
+
+	return json.Marshal(aux)
+}
+
+// TimeRange is a time window during which a Rule is active. The bounds are
+// encoded as UNIX timestamps, hence their sub-second parts are ignored.
+type TimeRange struct {
+	From  time.Time
+	Until time.Time
+}
+
+type timeRange struct {
+	From  int64 `json:"from"`
+	Until int64 `json:"until"`
+}
+
+func (r TimeRange) MarshalJSON() ([]byte, error) {
+	return json.Marshal(timeRange{
+		From:  r.From.Unix(),
+		Until: r.Until.Unix(),
+	})
+}
+
+func (r *TimeRange) UnmarshalJSON(data []byte) error {
+	var tr timeRange
+	if err := json.Unmarshal(data, &tr); err != nil {
+		return err
+	}
+
+	r.From = time.Unix(tr.From, 0)
+	r.Until = time.Unix(tr.Until, 0)
+	return nil
+}
+
 // RuleCondition is the part of an Algolia Rule which describes the condition
 // for the rule. The `Context` is optional, hence, it will get ignored if an
-// empty string is used to set it.
+// empty string is used to set it. If `Alternatives` is set, the `Pattern` also
+// matches its typos, plurals and synonyms.
 type RuleCondition struct {
-	Anchoring RulePatternAnchoring `json:"anchoring"`
-	Pattern   string               `json:"pattern"`
-	Context   string               `json:"context,omitempty"`
+	Anchoring    RulePatternAnchoring `json:"anchoring"`
+	Pattern      string               `json:"pattern"`
+	Alternatives bool                 `json:"alternatives,omitempty"`
+	Context      string               `json:"context,omitempty"`
+	Filters      string               `json:"filters,omitempty"`
+}
+
+// isEmpty returns `true` if none of the fields of the condition is set.
+func (c RuleCondition) isEmpty() bool {
+	return c == RuleCondition{}
 }
 
+// MarshalJSON omits the `anchoring` and `pattern` fields of the conditions
+// which only rely on a context or on filters.
+func (c RuleCondition) MarshalJSON() ([]byte, error) {
+	type ruleCondition struct {
+		Anchoring    RulePatternAnchoring `json:"anchoring,omitempty"`
+		Pattern      *string              `json:"pattern,omitempty"`
+		Alternatives bool                 `json:"alternatives,omitempty"`
+		Context      string               `json:"context,omitempty"`
+		Filters      string               `json:"filters,omitempty"`
+	}
+
+	aux := ruleCondition{
+		Anchoring:    c.Anchoring,
+		Alternatives: c.Alternatives,
+		Context:      c.Context,
+		Filters:      c.Filters,
+	}
+	if c.Anchoring != "" || c.Pattern != "" {
+		aux.Pattern = &c.Pattern
+	}
+
+	return json.Marshal(aux)
+}
+
+// RulePatternAnchoring describes how the pattern of a RuleCondition should
+// match the query string.
 type RulePatternAnchoring string
 
 const (
 	Is         RulePatternAnchoring = "is"
-	StarstWith RulePatternAnchoring = "startsWith"
+	StartsWith RulePatternAnchoring = "startsWith"
 	EndsWith   RulePatternAnchoring = "endsWith"
 	Contains   RulePatternAnchoring = "contains"
+
+	// Deprecated: use StartsWith instead.
+	StarstWith = StartsWith
 )
 
+// anchorings lists the valid anchorings of the patterns of the rules.
+var anchorings = []RulePatternAnchoring{Is, StartsWith, EndsWith, Contains}
+
+// Validate returns an error if the anchoring is not one of the known ones.
+func (a RulePatternAnchoring) Validate() error {
+	for _, anchoring := range anchorings {
+		if a == anchoring {
+			return nil
+		}
+	}
+	return fmt.Errorf("Invalid anchoring %q: should be one of %q", a, anchorings)
+}
+
 // NewSimpleRuleCondition generates a RuleCondition where only the `Anchoring`
 // and `Pattern` fields are specified. The optional `Context` field is then
 // excluded.
@@ -33,8 +143,20 @@ func NewSimpleRuleCondition(anchoring RulePatternAnchoring, pattern string) Rule
 	return NewRuleCondition(anchoring, pattern, "")
 }
 
-// NewRuleCondition generates a RuleCondition where all the possible fields can
-// be specified.
+// NewContextRuleCondition generates a RuleCondition which only matches the
+// queries sent with the given rule `context`, whatever their query string.
+func NewContextRuleCondition(context string) RuleCondition {
+	return RuleCondition{Context: context}
+}
+
+// NewFiltersRuleCondition generates a RuleCondition which only matches the
+// queries whose filters match the given `filters` (e.g. `brand:apple`).
+func NewFiltersRuleCondition(filters string) RuleCondition {
+	return RuleCondition{Filters: filters}
+}
+
+// NewRuleCondition generates a RuleCondition where the anchoring, pattern and
+// context fields can be specified.
 func NewRuleCondition(anchoring RulePatternAnchoring, pattern, context string) RuleCondition {
 	return RuleCondition{
 		Anchoring: anchoring,
@@ -43,21 +165,168 @@ func NewRuleCondition(anchoring RulePatternAnchoring, pattern, context string) R
 	}
 }
 
+// RuleConsequence is the part of an Algolia Rule which describes what
+// happens when the rule is triggered. Records can be promoted one by one
+// (`Promote`) or as blocks (`PromoteGroups`): both are sent in the `promote`
+// field of the consequence. If `FilterPromotes` is set, promoted records are
+// only kept if they match the filters of the query. `UserData` can be any
+// value which can be encoded as a JSON object or array.
 type RuleConsequence struct {
-	Params   Map              `json:"params,omitempty"`
-	Promote  []PromotedObject `json:"promote,omitempty"`
-	UserData interface{}      `json:"userData,omitempty"`
+	Params           Map               `json:"params,omitempty"`
+	Promote          []PromotedObject  `json:"promote,omitempty"`
+	PromoteGroups    []PromotedObjects `json:"-"`
+	FilterPromotes   bool              `json:"filterPromotes,omitempty"`
+	Hide             []HiddenObject    `json:"hide,omitempty"`
+	UserData         interface{}       `json:"userData,omitempty"`
+	RenderingContent *RenderingContent `json:"renderingContent,omitempty"`
+}
+
+func (c RuleConsequence) MarshalJSON() ([]byte, error) {
+	type ruleConsequence RuleConsequence
+	aux := struct {
+		ruleConsequence
+		Promote []interface{} `json:"promote,omitempty"`
+	}{ruleConsequence: ruleConsequence(c)}
+
+	for _, p := range c.Promote {
+		aux.Promote = append(aux.Promote, p)
+	}
+	for _, g := range c.PromoteGroups {
+		aux.Promote = append(aux.Promote, g)
+	}
+
+	return json.Marshal(aux)
 }
 
+// UnmarshalJSON splits the promoted records of the `promote` field into
+// `Promote` and `PromoteGroups` according to their form.
+func (c *RuleConsequence) UnmarshalJSON(data []byte) error {
+	type ruleConsequence RuleConsequence
+	aux := struct {
+		*ruleConsequence
+		Promote []struct {
+			ObjectID  string   `json:"objectID"`
+			ObjectIDs []string `json:"objectIDs"`
+			Position  int      `json:"position"`
+		} `json:"promote"`
+	}{ruleConsequence: (*ruleConsequence)(c)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	c.Promote, c.PromoteGroups = nil, nil
+	for _, p := range aux.Promote {
+		if p.ObjectIDs != nil {
+			c.PromoteGroups = append(c.PromoteGroups, PromotedObjects{ObjectIDs: p.ObjectIDs, Position: p.Position})
+		} else {
+			c.Promote = append(c.Promote, PromotedObject{ObjectID: p.ObjectID, Position: p.Position})
+		}
+	}
+
+	// The query edits are decoded as a QueryIncrementalEdit, as they would
+	// be given when saving the rule, instead of a generic map.
+	if query, ok := c.Params["query"].(map[string]interface{}); ok {
+		data, err := json.Marshal(query)
+		if err != nil {
+			return err
+		}
+		var edit QueryIncrementalEdit
+		if err = json.Unmarshal(data, &edit); err != nil {
+			return err
+		}
+		c.Params["query"] = edit
+	}
+
+	return nil
+}
+
+// QueryReplacement returns the string replacing the query string when the
+// rule is triggered, if any.
+func (c RuleConsequence) QueryReplacement() (query string, ok bool) {
+	query, ok = c.Params["query"].(string)
+	return
+}
+
+// QueryEdits returns the edits applied to the query string when the rule is
+// triggered, if any, including the ones given in the legacy `Remove` form.
+func (c RuleConsequence) QueryEdits() (edits []Edit, ok bool) {
+	edit, ok := c.Params["query"].(QueryIncrementalEdit)
+	if !ok {
+		return nil, false
+	}
+	return edit.AllEdits(), true
+}
+
+// QueryIncrementalEdit is used as the `query` parameter of a RuleConsequence
+// to modify the query string instead of replacing it. `Remove` is the legacy
+// form of the edits and is equivalent to `Edits` of type EditTypeRemove. The
+// `query` parameter of the retrieved rules is decoded either as a string or
+// as a QueryIncrementalEdit (see RuleConsequence.QueryReplacement and
+// RuleConsequence.QueryEdits).
 type QueryIncrementalEdit struct {
-	Remove []string `json:"remove"`
+	Remove []string `json:"remove,omitempty"`
+	Edits  []Edit   `json:"edits,omitempty"`
 }
 
+// AllEdits returns the edits of the QueryIncrementalEdit, the words of the
+// legacy `Remove` form being returned first as edits of type EditTypeRemove.
+func (e QueryIncrementalEdit) AllEdits() []Edit {
+	edits := make([]Edit, 0, len(e.Remove)+len(e.Edits))
+	for _, word := range e.Remove {
+		edits = append(edits, NewRemoveEdit(word))
+	}
+	return append(edits, e.Edits...)
+}
+
+// Types of the edits of a QueryIncrementalEdit.
+const (
+	EditTypeRemove  string = "remove"
+	EditTypeReplace string = "replace"
+)
+
+// Edit is a modification of the query string: the `Delete` word is removed
+// from the query (EditTypeRemove) or replaced by `Insert`
+// (EditTypeReplace).
+type Edit struct {
+	Type   string `json:"type"`
+	Delete string `json:"delete"`
+	Insert string `json:"insert,omitempty"`
+}
+
+// NewRemoveEdit returns an Edit removing the `word` from the query.
+func NewRemoveEdit(word string) Edit {
+	return Edit{Type: EditTypeRemove, Delete: word}
+}
+
+// NewReplaceEdit returns an Edit replacing the `word` of the query by the
+// `replacement`.
+func NewReplaceEdit(word, replacement string) Edit {
+	return Edit{Type: EditTypeReplace, Delete: word, Insert: replacement}
+}
+
+// PromotedObject is a record promoted at the given position (starting at 0)
+// when a rule is triggered.
 type PromotedObject struct {
 	ObjectID string `json:"objectID"`
 	Position int    `json:"position"`
 }
 
+// PromotedObjects is a group of records promoted as a block, in the given
+// order, from the given position (starting at 0) when a rule is triggered:
+// the group occupies the positions `Position` to
+// `Position+len(ObjectIDs)-1`.
+type PromotedObjects struct {
+	ObjectIDs []string `json:"objectIDs"`
+	Position  int      `json:"position"`
+}
+
+// HiddenObject is a record removed from the results when a rule is
+// triggered.
+type HiddenObject struct {
+	ObjectID string `json:"objectID"`
+}
+
 type SaveRuleRes struct {
 	TaskID    int    `json:"taskID"`
 	UpdatedAt string `json:"updatedAt"`
@@ -78,6 +347,19 @@ type ClearRulesRes struct {
 	UpdatedAt string `json:"updatedAt"`
 }
 
+// SearchRulesParams are the parameters of Index.SearchRules. All of them are
+// optional: zero values are not sent, hence the engine defaults apply (first
+// page of 20 rules, whatever their anchoring, context or status). `Enabled`
+// restricts the search to the enabled (or disabled) rules when set.
+type SearchRulesParams struct {
+	Query       string               `json:"query,omitempty"`
+	Anchoring   RulePatternAnchoring `json:"anchoring,omitempty"`
+	Context     string               `json:"context,omitempty"`
+	Page        int                  `json:"page,omitempty"`
+	HitsPerPage int                  `json:"hitsPerPage,omitempty"`
+	Enabled     *bool                `json:"enabled,omitempty"`
+}
+
 type SearchRulesRes struct {
 	Hits    []Rule `json:"hits"`
 	NbHits  int    `json:"nbHits"`
@@ -0,0 +1,299 @@
+package algoliasearch
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleValidity(t *testing.T) {
+	from := time.Date(2017, 11, 24, 0, 0, 0, 0, time.UTC)
+	until := from.Add(72 * time.Hour)
+
+	rule := Rule{
+		ObjectID:    "black_friday",
+		Condition:   NewSimpleRuleCondition(Contains, "deals"),
+		Consequence: RuleConsequence{Params: Map{"filters": "onSale:true"}},
+		Validity:    []TimeRange{{From: from, Until: until}},
+	}
+
+	t.Log("TestRuleValidity: Check the encoding of the validity")
+	{
+		data, err := json.Marshal(rule)
+		require.Nil(t, err)
+		require.Contains(t, string(data), `"validity":[{"from":1511481600,"until":1511740800}]`)
+
+		var decoded Rule
+		require.Nil(t, json.Unmarshal(data, &decoded))
+		require.Len(t, decoded.Validity, 1)
+		require.True(t, from.Equal(decoded.Validity[0].From))
+		require.True(t, until.Equal(decoded.Validity[0].Until))
+	}
+
+	t.Log("TestRuleValidity: Check the validation of the validity")
+	{
+		require.Nil(t, checkRule(rule))
+
+		rule.Validity = []TimeRange{{From: until, Until: from}}
+		require.NotNil(t, checkRule(rule), "should reject empty time ranges")
+	}
+
+	t.Log("TestRuleValidity: Check that rules without validity omit it")
+	{
+		data, err := json.Marshal(Rule{ObjectID: "always"})
+		require.Nil(t, err)
+		require.NotContains(t, string(data), "validity")
+	}
+}
+
+func TestRuleConditions(t *testing.T) {
+	t.Log("TestRuleConditions: Check the encoding of single-condition rules")
+	{
+		data, err := json.Marshal(Rule{
+			ObjectID:  "single",
+			Condition: NewSimpleRuleCondition(Is, ""),
+		})
+		require.Nil(t, err)
+		require.Contains(t, string(data), `"condition":{"anchoring":"is","pattern":""}`)
+		require.NotContains(t, string(data), "conditions")
+	}
+
+	t.Log("TestRuleConditions: Check the encoding of multiple-condition rules")
+	{
+		data, err := json.Marshal(Rule{
+			ObjectID: "multiple",
+			Conditions: []RuleCondition{
+				NewSimpleRuleCondition(Contains, "phone"),
+				NewContextRuleCondition("mobile"),
+				NewFiltersRuleCondition("brand:apple"),
+			},
+		})
+		require.Nil(t, err)
+		require.Contains(t, string(data), `"conditions":[{"anchoring":"contains","pattern":"phone"},{"context":"mobile"},{"filters":"brand:apple"}]`)
+		require.NotContains(t, string(data), `"condition":`)
+
+		var decoded Rule
+		require.Nil(t, json.Unmarshal(data, &decoded))
+		require.Len(t, decoded.Conditions, 3)
+		require.Equal(t, "mobile", decoded.Conditions[1].Context)
+		require.Equal(t, RuleCondition{}, decoded.Condition)
+	}
+
+	t.Log("TestRuleConditions: Check the encoding of the alternatives")
+	{
+		condition := NewSimpleRuleCondition(StartsWith, "phone")
+		condition.Alternatives = true
+
+		data, err := json.Marshal(condition)
+		require.Nil(t, err)
+		require.JSONEq(t, `{"anchoring":"startsWith","pattern":"phone","alternatives":true}`, string(data))
+
+		var decoded RuleCondition
+		require.Nil(t, json.Unmarshal(data, &decoded))
+		require.Equal(t, condition, decoded)
+		require.Nil(t, checkRule(Rule{Condition: condition}))
+	}
+
+	t.Log("TestRuleConditions: Check rules without condition")
+	{
+		data, err := json.Marshal(Rule{ObjectID: "always"})
+		require.Nil(t, err)
+		require.NotContains(t, string(data), "condition")
+	}
+
+	t.Log("TestRuleConditions: Check the validation of the conditions")
+	{
+		require.Nil(t, checkRule(Rule{Conditions: []RuleCondition{NewContextRuleCondition("mobile")}}))
+		require.NotNil(t, checkRule(Rule{Conditions: []RuleCondition{{Pattern: "phone"}}}))
+		require.NotNil(t, checkRule(Rule{Conditions: []RuleCondition{NewSimpleRuleCondition("startWith", "phone")}}))
+		require.NotNil(t, checkRule(Rule{Conditions: []RuleCondition{{Context: "mobile", Alternatives: true}}}))
+		require.Equal(t, StartsWith, StarstWith)
+	}
+}
+
+func TestRuleConsequence(t *testing.T) {
+	consequence := RuleConsequence{
+		Params: Map{
+			"query": QueryIncrementalEdit{Edits: []Edit{
+				NewRemoveEdit("cheap"),
+				NewReplaceEdit("tv", "television"),
+			}},
+		},
+		Promote:       []PromotedObject{{ObjectID: "iphone", Position: 0}},
+		PromoteGroups: []PromotedObjects{{ObjectIDs: []string{"pixel", "galaxy"}, Position: 2}},
+		Hide:          []HiddenObject{{ObjectID: "discontinued"}},
+		UserData:      Map{"banner": "black-friday.png"},
+		RenderingContent: &RenderingContent{
+			FacetOrdering: &FacetOrdering{
+				Facets: &FacetsOrder{Order: []string{"brand", "*"}},
+				Values: map[string]FacetValuesOrder{
+					"brand": {Order: []string{"Apple"}, SortRemainingBy: SortRemainingByCount},
+				},
+			},
+		},
+	}
+
+	t.Log("TestRuleConsequence: Check the encoding of the consequence")
+	{
+		data, err := json.Marshal(consequence)
+		require.Nil(t, err)
+		require.JSONEq(t, `{
+			"params": {"query": {"edits": [
+				{"type": "remove", "delete": "cheap"},
+				{"type": "replace", "delete": "tv", "insert": "television"}
+			]}},
+			"promote": [
+				{"objectID": "iphone", "position": 0},
+				{"objectIDs": ["pixel", "galaxy"], "position": 2}
+			],
+			"hide": [{"objectID": "discontinued"}],
+			"userData": {"banner": "black-friday.png"},
+			"renderingContent": {"facetOrdering": {
+				"facets": {"order": ["brand", "*"]},
+				"values": {"brand": {"order": ["Apple"], "sortRemainingBy": "count"}}
+			}}
+		}`, string(data))
+		require.Nil(t, checkRule(Rule{Consequence: consequence}))
+
+		var decoded RuleConsequence
+		require.Nil(t, json.Unmarshal(data, &decoded))
+		require.Equal(t, consequence.Promote, decoded.Promote)
+		require.Equal(t, consequence.PromoteGroups, decoded.PromoteGroups)
+		require.Equal(t, consequence.Params, decoded.Params)
+	}
+
+	t.Log("TestRuleConsequence: Check the validation of the consequence")
+	for _, c := range []RuleConsequence{
+		{Promote: []PromotedObject{{ObjectID: "", Position: 0}}},
+		{Promote: []PromotedObject{{ObjectID: "iphone", Position: -1}}},
+		{Promote: []PromotedObject{{ObjectID: "iphone", Position: 0}, {ObjectID: "pixel", Position: 0}}},
+		{PromoteGroups: []PromotedObjects{{Position: 0}}},
+		{PromoteGroups: []PromotedObjects{{ObjectIDs: []string{"iphone", ""}, Position: 0}}},
+		{PromoteGroups: []PromotedObjects{{ObjectIDs: []string{"iphone"}, Position: -1}}},
+		{
+			Promote:       []PromotedObject{{ObjectID: "iphone", Position: 3}},
+			PromoteGroups: []PromotedObjects{{ObjectIDs: []string{"pixel", "galaxy"}, Position: 2}},
+		},
+		{PromoteGroups: []PromotedObjects{
+			{ObjectIDs: []string{"pixel", "galaxy"}, Position: 0},
+			{ObjectIDs: []string{"iphone"}, Position: 1},
+		}},
+		{Hide: []HiddenObject{{}}},
+		{UserData: "banner"},
+		{Params: Map{"query": QueryIncrementalEdit{Edits: []Edit{{Type: "insert", Delete: "tv"}}}}},
+		{Params: Map{"query": QueryIncrementalEdit{Remove: []string{""}}}},
+		{Params: Map{"query": QueryIncrementalEdit{Edits: []Edit{{Type: EditTypeReplace, Delete: "tv"}}}}},
+		{Params: Map{"query": QueryIncrementalEdit{Edits: []Edit{{Type: EditTypeRemove, Delete: "tv", Insert: "television"}}}}},
+		{RenderingContent: &RenderingContent{FacetOrdering: &FacetOrdering{
+			Values: map[string]FacetValuesOrder{"brand": {SortRemainingBy: "random"}},
+		}}},
+	} {
+		require.NotNil(t, checkRule(Rule{Consequence: c}), "should reject %#v", c)
+	}
+}
+
+func TestRuleConsequenceQuery(t *testing.T) {
+	t.Log("TestRuleConsequenceQuery: Check that a replacement query is decoded as a string")
+	{
+		var c RuleConsequence
+		require.Nil(t, json.Unmarshal([]byte(`{"params":{"query":"television"}}`), &c))
+
+		query, ok := c.QueryReplacement()
+		require.True(t, ok)
+		require.Equal(t, "television", query)
+		_, ok = c.QueryEdits()
+		require.False(t, ok)
+	}
+
+	t.Log("TestRuleConsequenceQuery: Check that the query edits are decoded as typed edits")
+	{
+		var c RuleConsequence
+		require.Nil(t, json.Unmarshal([]byte(`{"params":{"query":{"edits":[
+			{"type":"remove","delete":"cheap"},
+			{"type":"replace","delete":"tv","insert":"television"}
+		]}}}`), &c))
+
+		edits, ok := c.QueryEdits()
+		require.True(t, ok)
+		require.Equal(t, []Edit{NewRemoveEdit("cheap"), NewReplaceEdit("tv", "television")}, edits)
+		_, ok = c.QueryReplacement()
+		require.False(t, ok)
+		require.Nil(t, checkRule(Rule{Consequence: c}))
+	}
+
+	t.Log("TestRuleConsequenceQuery: Check that the legacy form is still decoded")
+	{
+		var c RuleConsequence
+		require.Nil(t, json.Unmarshal([]byte(`{"params":{"query":{"remove":["cheap","used"]}}}`), &c))
+		require.Equal(t, QueryIncrementalEdit{Remove: []string{"cheap", "used"}}, c.Params["query"])
+
+		edits, ok := c.QueryEdits()
+		require.True(t, ok)
+		require.Equal(t, []Edit{NewRemoveEdit("cheap"), NewRemoveEdit("used")}, edits)
+	}
+}
+
+func TestSearchRulesParams(t *testing.T) {
+	t.Log("TestSearchRulesParams: Check that only the set parameters are sent")
+	{
+		data, err := json.Marshal(SearchRulesParams{})
+		require.Nil(t, err)
+		require.JSONEq(t, `{}`, string(data))
+
+		disabled := false
+		data, err = json.Marshal(SearchRulesParams{
+			Query:       "tea",
+			Anchoring:   Contains,
+			Context:     "mobile",
+			Page:        1,
+			HitsPerPage: 50,
+			Enabled:     &disabled,
+		})
+		require.Nil(t, err)
+		require.JSONEq(t, `{
+			"query": "tea",
+			"anchoring": "contains",
+			"context": "mobile",
+			"page": 1,
+			"hitsPerPage": 50,
+			"enabled": false
+		}`, string(data))
+	}
+
+	t.Log("TestSearchRulesParams: Check the validation of the parameters")
+	{
+		require.Nil(t, checkSearchRulesParams(SearchRulesParams{Page: 2, HitsPerPage: 10}))
+		require.NotNil(t, checkSearchRulesParams(SearchRulesParams{Page: -1}))
+		require.NotNil(t, checkSearchRulesParams(SearchRulesParams{HitsPerPage: -1}))
+		require.Nil(t, checkSearchRulesParams(SearchRulesParams{Anchoring: EndsWith}))
+		require.NotNil(t, checkSearchRulesParams(SearchRulesParams{Anchoring: "ends"}))
+	}
+
+	t.Log("TestSearchRulesParams: Check that hits are decoded as typed rules")
+	{
+		var res SearchRulesRes
+		err := json.Unmarshal([]byte(`{
+			"hits": [{
+				"objectID": "tea",
+				"condition": {"anchoring": "contains", "pattern": "tea"},
+				"consequence": {"params": {"query": "coffee"}},
+				"enabled": false,
+				"_highlightResult": {"objectID": {"value": "<em>tea</em>", "matchLevel": "full"}}
+			}],
+			"nbHits": 1,
+			"page": 0,
+			"nbPages": 1
+		}`), &res)
+		require.Nil(t, err)
+		require.Len(t, res.Hits, 1)
+		require.Equal(t, NewSimpleRuleCondition(Contains, "tea"), res.Hits[0].Condition)
+		require.NotNil(t, res.Hits[0].Enabled)
+		require.False(t, *res.Hits[0].Enabled)
+
+		highlighted, ok := toHighlightedResult(res.Hits[0].HighlightResult["objectID"])
+		require.True(t, ok)
+		require.Equal(t, "<em>tea</em>", highlighted.Value)
+	}
+}
@@ -6,7 +6,47 @@ type FacetHit struct {
 	Count       int    `json:"count"`
 }
 
+// FacetStat holds the statistics of the values of a numeric facet among the
+// hits of a search (see QueryRes.FacetStats).
+type FacetStat struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+	Avg float64 `json:"avg"`
+	Sum float64 `json:"sum"`
+}
+
 type SearchFacetRes struct {
-	FacetHits        []FacetHit `json:"facetHits"`
-	ProcessingTimeMS int        `json:"processingTimeMS"`
+	FacetHits             []FacetHit `json:"facetHits"`
+	ExhaustiveFacetsCount bool       `json:"exhaustiveFacetsCount"`
+	ProcessingTimeMS      int        `json:"processingTimeMS"`
+}
+
+// SearchForFacetValuesParams are the parameters of
+// Index.SearchForFacetValuesWithParams. `MaxFacetHits` (between 1 and 100,
+// defaults to 10) and `SortFacetValuesBy` ("count" or "alpha") are only sent
+// if set. `SearchParams` are regular search parameters restricting the
+// records the facet values are taken from: they are validated as any search
+// query and are overridden by the typed fields.
+type SearchForFacetValuesParams struct {
+	FacetQuery        string
+	MaxFacetHits      int
+	SortFacetValuesBy string
+	SearchParams      Map
+}
+
+// toMap returns the search parameters corresponding to the typed fields,
+// merged into a copy of `SearchParams`.
+func (p SearchForFacetValuesParams) toMap() Map {
+	m := duplicateMap(p.SearchParams)
+	m["facetQuery"] = p.FacetQuery
+
+	if p.MaxFacetHits != 0 {
+		m["maxFacetHits"] = p.MaxFacetHits
+	}
+
+	if p.SortFacetValuesBy != "" {
+		m["sortFacetValuesBy"] = p.SortFacetValuesBy
+	}
+
+	return m
 }
@@ -0,0 +1,51 @@
+package algoliasearch
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchForFacetValuesParams(t *testing.T) {
+	t.Log("TestSearchForFacetValuesParams: Check the merge of the typed fields")
+	{
+		params := SearchForFacetValuesParams{
+			FacetQuery:        "app",
+			MaxFacetHits:      50,
+			SortFacetValuesBy: "alpha",
+			SearchParams:      Map{"filters": "category:phone", "maxFacetHits": 5},
+		}
+		require.Equal(t, Map{
+			"facetQuery":        "app",
+			"maxFacetHits":      50,
+			"sortFacetValuesBy": "alpha",
+			"filters":           "category:phone",
+		}, params.toMap())
+		require.Equal(t, Map{"filters": "category:phone", "maxFacetHits": 5}, params.SearchParams, "should not modify the search params")
+
+		require.Equal(t, Map{"facetQuery": ""}, SearchForFacetValuesParams{}.toMap())
+	}
+
+	t.Log("TestSearchForFacetValuesParams: Check the validation of the parameters")
+	{
+		require.Nil(t, checkQuery(SearchForFacetValuesParams{MaxFacetHits: 100}.toMap()))
+		require.NotNil(t, checkQuery(SearchForFacetValuesParams{MaxFacetHits: 101}.toMap()))
+		require.NotNil(t, checkQuery(SearchForFacetValuesParams{MaxFacetHits: -1}.toMap()))
+		require.NotNil(t, checkQuery(SearchForFacetValuesParams{SortFacetValuesBy: "random"}.toMap()))
+		require.NotNil(t, checkSettings(Map{"maxFacetHits": 1000}))
+	}
+
+	t.Log("TestSearchForFacetValuesParams: Check the decoding of the results")
+	{
+		var res SearchFacetRes
+		err := json.Unmarshal([]byte(`{
+			"facetHits": [{"value": "Apple", "highlighted": "<em>App</em>le", "count": 42}],
+			"exhaustiveFacetsCount": true,
+			"processingTimeMS": 1
+		}`), &res)
+		require.Nil(t, err)
+		require.True(t, res.ExhaustiveFacetsCount)
+		require.Equal(t, 42, res.FacetHits[0].Count)
+	}
+}
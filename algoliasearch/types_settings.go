@@ -24,6 +24,17 @@ type Settings struct {
 	Slaves                         []string `json:"slaves"`
 	UnretrievableAttributes        []string `json:"unretrievableAttributes"`
 
+	// Languages
+	AttributesToTransliterate []string                     `json:"attributesToTransliterate"`
+	CustomNormalization       map[string]map[string]string `json:"customNormalization"`
+	DecompoundedAttributes    map[string][]string          `json:"decompoundedAttributes"`
+	IndexLanguages            []string                     `json:"indexLanguages"`
+	QueryLanguages            []string                     `json:"queryLanguages"`
+
+	// Relevance
+	AttributeCriteriaComputedByMinProximity bool `json:"attributeCriteriaComputedByMinProximity"`
+	RelevancyStrictness                     *int `json:"relevancyStrictness"` // nil if not set, as 0 is a valid strictness
+
 	// Query expansion
 	DisableTypoToleranceOnAttributes []string `json:"disableTypoToleranceOnAttributes"`
 	DisableTypoToleranceOnWords      []string `json:"disableTypoToleranceOnWords"`
@@ -52,6 +63,10 @@ type Settings struct {
 	SnippetEllipsisText        string      `json:"snippetEllipsisText"`
 	SortFacetValuesBy          string      `json:"sortFacetValuesBy"`
 	TypoTolerance              string      `json:"typoTolerance"`
+
+	// Presentation
+	RenderingContent *RenderingContent `json:"renderingContent"`
+	UserData         interface{}       `json:"userData"` // map[string]interface{} or []interface{}
 }
 
 // clean sets the nil `interface{}` fields of any `Settings struct` generated
@@ -98,6 +113,14 @@ func (s *Settings) ToMap() Map {
 		"slaves":                         s.Slaves,
 		"unretrievableAttributes":        s.UnretrievableAttributes,
 
+		// Languages
+		"attributesToTransliterate": s.AttributesToTransliterate,
+		"indexLanguages":            s.IndexLanguages,
+		"queryLanguages":            s.QueryLanguages,
+
+		// Relevance
+		"attributeCriteriaComputedByMinProximity": s.AttributeCriteriaComputedByMinProximity,
+
 		// Query expansion
 		"disableTypoToleranceOnAttributes": s.DisableTypoToleranceOnAttributes,
 		"disableTypoToleranceOnWords":      s.DisableTypoToleranceOnWords,
@@ -140,6 +163,28 @@ func (s *Settings) ToMap() Map {
 		delete(m, attr)
 	}
 
+	// Only add the optional fields which are set, as their zero values are
+	// either invalid or meaningful for the API
+	if len(s.CustomNormalization) > 0 {
+		m["customNormalization"] = s.CustomNormalization
+	}
+
+	if len(s.DecompoundedAttributes) > 0 {
+		m["decompoundedAttributes"] = s.DecompoundedAttributes
+	}
+
+	if s.RelevancyStrictness != nil {
+		m["relevancyStrictness"] = *s.RelevancyStrictness
+	}
+
+	if s.RenderingContent != nil {
+		m["renderingContent"] = s.RenderingContent
+	}
+
+	if s.UserData != nil {
+		m["userData"] = s.UserData
+	}
+
 	// Handle `Distinct` separately as it may be either a `bool` or a `float64`
 	// which is in fact a `int`.
 	switch v := s.Distinct.(type) {
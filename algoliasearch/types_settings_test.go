@@ -0,0 +1,69 @@
+package algoliasearch
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSettingsModernFields(t *testing.T) {
+	var settings Settings
+	err := json.Unmarshal([]byte(`{
+		"attributesToTransliterate": ["name"],
+		"customNormalization": {"default": {"ä": "ae"}},
+		"decompoundedAttributes": {"de": ["name"]},
+		"indexLanguages": ["ja"],
+		"queryLanguages": ["ja", "en"],
+		"attributeCriteriaComputedByMinProximity": true,
+		"relevancyStrictness": 0,
+		"renderingContent": {"facetOrdering": {"facets": {"order": ["brand"]}}},
+		"userData": {"banner": "sales.png"}
+	}`), &settings)
+	require.Nil(t, err)
+	settings.clean()
+
+	t.Log("TestSettingsModernFields: Check that the fields are kept by ToMap")
+	{
+		m := settings.ToMap()
+		require.Nil(t, checkSettings(m), "ToMap should produce valid settings")
+		require.Equal(t, []string{"name"}, m["attributesToTransliterate"])
+		require.Equal(t, map[string]map[string]string{"default": {"ä": "ae"}}, m["customNormalization"])
+		require.Equal(t, map[string][]string{"de": {"name"}}, m["decompoundedAttributes"])
+		require.Equal(t, []string{"ja"}, m["indexLanguages"])
+		require.Equal(t, []string{"ja", "en"}, m["queryLanguages"])
+		require.Equal(t, true, m["attributeCriteriaComputedByMinProximity"])
+		require.Equal(t, 0, m["relevancyStrictness"])
+		require.Equal(t, settings.RenderingContent, m["renderingContent"])
+		require.Equal(t, map[string]interface{}{"banner": "sales.png"}, m["userData"])
+	}
+
+	t.Log("TestSettingsModernFields: Check that unset fields are not sent")
+	{
+		var empty Settings
+		empty.clean()
+		m := empty.ToMap()
+		for _, k := range []string{
+			"customNormalization",
+			"decompoundedAttributes",
+			"indexLanguages",
+			"relevancyStrictness",
+			"renderingContent",
+			"userData",
+		} {
+			_, ok := m[k]
+			require.False(t, ok, "should not send unset %s", k)
+		}
+	}
+
+	t.Log("TestSettingsModernFields: Check the validation of the new settings")
+	for _, settings := range []Map{
+		{"indexLanguages": "ja"},
+		{"relevancyStrictness": "90"},
+		{"decompoundedAttributes": Map{"de": []string{"name"}}},
+		{"userData": "banner"},
+		{"renderingContent": Map{}},
+	} {
+		require.NotNil(t, checkSettings(settings), "should reject %v", settings)
+	}
+}
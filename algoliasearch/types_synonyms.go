@@ -1,5 +1,10 @@
 package algoliasearch
 
+import (
+	"fmt"
+	"strings"
+)
+
 const (
 	AltCorrection1 string = "altCorrection1"
 	AltCorrection2 string = "altCorrection2"
@@ -32,7 +37,7 @@ type Synonym struct {
 	// Synonyms []string `json:"synonyms"`
 }
 
-func NewAltCorrectionSynonym(objectID string, corrections []string, word string, t string) Synonym {
+func NewAltCorrectionSynonym(objectID string, word string, corrections []string, t string) Synonym {
 	return Synonym{
 		ObjectID:    objectID,
 		Type:        t,
@@ -41,6 +46,18 @@ func NewAltCorrectionSynonym(objectID string, corrections []string, word string,
 	}
 }
 
+// NewAltCorrection1Synonym returns an AltCorrection1 synonym: the given
+// `corrections` are considered as one-typo variants of `word`.
+func NewAltCorrection1Synonym(objectID string, word string, corrections []string) Synonym {
+	return NewAltCorrectionSynonym(objectID, word, corrections, AltCorrection1)
+}
+
+// NewAltCorrection2Synonym returns an AltCorrection2 synonym: the given
+// `corrections` are considered as two-typo variants of `word`.
+func NewAltCorrection2Synonym(objectID string, word string, corrections []string) Synonym {
+	return NewAltCorrectionSynonym(objectID, word, corrections, AltCorrection2)
+}
+
 func NewOneWaySynonym(objectID string, input string, synonyms []string) Synonym {
 	return Synonym{
 		ObjectID: objectID,
@@ -66,3 +83,60 @@ func NewSynonym(objectID string, synonyms []string) Synonym {
 		Synonyms: synonyms,
 	}
 }
+
+// Validate checks that the fields required by the type of the synonym are
+// set, so that malformed synonyms are rejected before reaching the API. As
+// for the API, the type is case-insensitive: the synonyms it returns have
+// lowercase types, e.g. "onewaysynonym".
+func (s Synonym) Validate() error {
+	if s.ObjectID == "" {
+		return fmt.Errorf("synonym `objectID` should not be empty")
+	}
+
+	switch strings.ToLower(s.Type) {
+	case "synonym":
+		if len(s.Synonyms) < 2 {
+			return fmt.Errorf("synonym %q should have at least 2 `synonyms`", s.ObjectID)
+		}
+		return checkSynonymWords(s.ObjectID, "synonyms", s.Synonyms)
+
+	case "onewaysynonym":
+		if s.Input == "" {
+			return fmt.Errorf("synonym %q should have an `input`", s.ObjectID)
+		}
+		if len(s.Synonyms) == 0 {
+			return fmt.Errorf("synonym %q should have at least 1 element in `synonyms`", s.ObjectID)
+		}
+		return checkSynonymWords(s.ObjectID, "synonyms", s.Synonyms)
+
+	case "altcorrection1", "altcorrection2":
+		if s.Word == "" {
+			return fmt.Errorf("synonym %q should have a `word`", s.ObjectID)
+		}
+		if len(s.Corrections) == 0 {
+			return fmt.Errorf("synonym %q should have at least 1 element in `corrections`", s.ObjectID)
+		}
+		return checkSynonymWords(s.ObjectID, "corrections", s.Corrections)
+
+	case "placeholder":
+		if len(s.Placeholder) < 3 || !strings.HasPrefix(s.Placeholder, "<") || !strings.HasSuffix(s.Placeholder, ">") {
+			return fmt.Errorf("synonym %q should have a `placeholder` of the form `<name>`, got %q", s.ObjectID, s.Placeholder)
+		}
+		if len(s.Replacements) == 0 {
+			return fmt.Errorf("synonym %q should have at least 1 element in `replacements`", s.ObjectID)
+		}
+		return checkSynonymWords(s.ObjectID, "replacements", s.Replacements)
+
+	default:
+		return fmt.Errorf("synonym %q has an unknown type %q", s.ObjectID, s.Type)
+	}
+}
+
+func checkSynonymWords(objectID, field string, words []string) error {
+	for _, w := range words {
+		if strings.TrimSpace(w) == "" {
+			return fmt.Errorf("synonym %q should not contain empty `%s`", objectID, field)
+		}
+	}
+	return nil
+}
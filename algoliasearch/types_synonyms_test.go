@@ -0,0 +1,55 @@
+package algoliasearch
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSynonymValidate(t *testing.T) {
+	t.Log("TestSynonymValidate: Check that well-formed synonyms are accepted")
+	for _, s := range []Synonym{
+		NewSynonym("tesla", []string{"tesla", "tesla motors"}),
+		NewOneWaySynonym("julien", "speedblue", []string{"julien lemoine"}),
+		NewAltCorrection1Synonym("rob", "rob", []string{"robpike"}),
+		NewAltCorrection2Synonym("pike", "pike", []string{"robpike"}),
+		NewPlaceholderSynonym("google", "<GOOG>", []string{"Google", "GOOG"}),
+	} {
+		require.Nil(t, s.Validate(), "should accept %#v", s)
+	}
+
+	t.Log("TestSynonymValidate: Check that malformed synonyms are rejected")
+	for _, s := range []Synonym{
+		NewSynonym("", []string{"tesla", "tesla motors"}),
+		NewSynonym("tesla", []string{"tesla"}),
+		NewSynonym("tesla", []string{"tesla", " "}),
+		NewOneWaySynonym("julien", "", []string{"julien lemoine"}),
+		NewOneWaySynonym("julien", "speedblue", nil),
+		NewAltCorrection1Synonym("rob", "", []string{"robpike"}),
+		NewAltCorrection2Synonym("pike", "pike", nil),
+		NewPlaceholderSynonym("google", "GOOG", []string{"Google"}),
+		NewPlaceholderSynonym("google", "<>", []string{"Google"}),
+		NewPlaceholderSynonym("google", "<GOOG>", nil),
+		{ObjectID: "unknown", Type: "antonym", Synonyms: []string{"up", "down"}},
+	} {
+		require.NotNil(t, s.Validate(), "should reject %#v", s)
+	}
+
+	t.Log("TestSynonymValidate: Check that the lowercase types returned by the API are accepted")
+	{
+		var synonyms []Synonym
+		require.Nil(t, json.Unmarshal([]byte(`[
+			{"objectID":"julien","type":"onewaysynonym","input":"speedblue","synonyms":["julien lemoine"]},
+			{"objectID":"rob","type":"altcorrection1","word":"rob","corrections":["robpike"]},
+			{"objectID":"pike","type":"altcorrection2","word":"pike","corrections":["robpike"]}
+		]`), &synonyms))
+		for _, s := range synonyms {
+			require.Nil(t, s.Validate(), "should accept %#v", s)
+		}
+
+		data, err := json.Marshal(synonyms)
+		require.Nil(t, err)
+		require.Contains(t, string(data), `"type":"onewaysynonym"`)
+	}
+}
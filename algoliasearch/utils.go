@@ -1,10 +1,12 @@
 package algoliasearch
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"math/rand"
 	"net/url"
+	"sort"
 	"strconv"
 	"time"
 )
@@ -30,25 +32,59 @@ func duplicateMap(m Map) Map {
 	return copy
 }
 
-// encodeMap transforms `params` to a URL-safe string.
+// paramEncoder is implemented by the typed values of query parameters whose
+// URL encoding differs from their JSON encoding (e.g. `LatLng`).
+type paramEncoder interface {
+	encodeParam() string
+}
+
+// encodeMap transforms `params` to a URL-safe string. The parameters are
+// sorted by key, as done by `url.Values.Encode`, so that the encoding is
+// deterministic. As it is called for every search, the common types of
+// values are encoded without reflection.
 func encodeMap(params Map) string {
-	values := url.Values{}
-
-	if params != nil {
-		for k, v := range params {
-			switch v := v.(type) {
-			case string:
-				values.Add(k, v)
-			case float64:
-				values.Add(k, strconv.FormatFloat(v, 'f', -1, 64))
-			case int:
-				values.Add(k, strconv.Itoa(v))
-			default:
-				jsonValue, _ := json.Marshal(v)
-				values.Add(k, string(jsonValue[:]))
-			}
+	if len(params) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.Grow(len(params) * 32)
+
+	for n, k := range keys {
+		if n > 0 {
+			buf.WriteByte('&')
 		}
+		buf.WriteString(url.QueryEscape(k))
+		buf.WriteByte('=')
+		buf.WriteString(url.QueryEscape(encodeParamValue(params[k])))
 	}
 
-	return values.Encode()
+	return buf.String()
+}
+
+// encodeParamValue returns the string form of the value `v` of a query
+// parameter: strings and numbers are sent as-is while other values are sent
+// as JSON.
+func encodeParamValue(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case paramEncoder:
+		return v.encodeParam()
+	case int:
+		return strconv.Itoa(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		jsonValue, _ := json.Marshal(v)
+		return string(jsonValue)
+	}
 }
@@ -0,0 +1,72 @@
+package algoliasearch
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// encodeMapWithValues is the reference implementation of encodeMap, relying
+// on `url.Values`.
+func encodeMapWithValues(params Map) string {
+	values := url.Values{}
+	for k, v := range params {
+		switch v := v.(type) {
+		case string:
+			values.Add(k, v)
+		case paramEncoder:
+			values.Add(k, v.encodeParam())
+		case float64:
+			values.Add(k, strconv.FormatFloat(v, 'f', -1, 64))
+		case int:
+			values.Add(k, strconv.Itoa(v))
+		default:
+			jsonValue, _ := json.Marshal(v)
+			values.Add(k, string(jsonValue))
+		}
+	}
+	return values.Encode()
+}
+
+var benchmarkParams = Map{
+	"query":                 "apple iphone 12 & accessories",
+	"hitsPerPage":           20,
+	"page":                  3,
+	"attributesToRetrieve":  []string{"name", "price", "brand", "image"},
+	"attributesToHighlight": []string{"name"},
+	"facets":                []string{"brand", "category"},
+	"filters":               "price > 100 AND (brand:apple OR brand:samsung)",
+	"analytics":             true,
+	"aroundLatLng":          LatLng{Lat: 40.71, Lng: -74.01},
+	"aroundRadius":          1500.5,
+	"queryType":             PrefixAll,
+}
+
+func TestEncodeMap(t *testing.T) {
+	for _, params := range []Map{
+		nil,
+		{},
+		{"query": ""},
+		{"query": "a&b=c d/é", "k y": "v"},
+		benchmarkParams,
+	} {
+		require.Equal(t, encodeMapWithValues(params), encodeMap(params), "should encode %v as url.Values", params)
+	}
+}
+
+func BenchmarkEncodeMap(b *testing.B) {
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		encodeMap(benchmarkParams)
+	}
+}
+
+func BenchmarkEncodeMapWithValues(b *testing.B) {
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		encodeMapWithValues(benchmarkParams)
+	}
+}
@@ -0,0 +1,114 @@
+package algoliasearch
+
+import (
+	"context"
+	"time"
+)
+
+// WaitSchedule is the polling schedule used to wait for tasks (see
+// Index.WaitTask). The status of the task is polled right away and then
+// after a random delay between 0 and an upper bound which starts at
+// `Initial` and is doubled after each poll, up to `Max`.
+type WaitSchedule struct {
+	Initial time.Duration
+	Max     time.Duration
+}
+
+// Predefined polling schedules, selectable per client with
+// Client.SetWaitSchedule or per call with RequestOptions.WaitSchedule.
+var (
+	// WaitScheduleAggressive gives sub-second feedback, e.g. for interactive
+	// tools, at the cost of more requests.
+	WaitScheduleAggressive = WaitSchedule{Initial: 100 * time.Millisecond, Max: time.Second}
+
+	// WaitScheduleDefault is the schedule used if none is specified.
+	WaitScheduleDefault = WaitSchedule{Initial: time.Second, Max: 10 * time.Minute}
+
+	// WaitScheduleRelaxed sends few requests, e.g. for batch jobs which do
+	// not need to resume as soon as the task is published.
+	WaitScheduleRelaxed = WaitSchedule{Initial: 5 * time.Second, Max: 10 * time.Minute}
+)
+
+// waitSchedule returns the polling schedule to use for a call made with the
+// given `opts`. A non-positive `Initial` delay is replaced by the default one.
+func (c *client) waitSchedule(opts *RequestOptions) WaitSchedule {
+	schedule := WaitScheduleDefault
+	if c.waitSched != (WaitSchedule{}) {
+		schedule = c.waitSched
+	}
+	return callWaitSchedule(opts, schedule)
+}
+
+// callWaitSchedule returns the polling schedule of the call made with the
+// given `opts`, if any, or `schedule` otherwise. A non-positive `Initial`
+// delay is replaced by the default one.
+func callWaitSchedule(opts *RequestOptions, schedule WaitSchedule) WaitSchedule {
+	if opts != nil && opts.WaitSchedule != nil {
+		schedule = *opts.WaitSchedule
+	}
+
+	if schedule.Initial <= 0 {
+		schedule.Initial = WaitScheduleDefault.Initial
+	}
+	return schedule
+}
+
+// pollTask polls the status of a task with `getStatus`, according to the
+// polling schedule of the call made with the given `opts`, until the task is
+// published.
+func (c *client) pollTask(getStatus func() (TaskStatusRes, error), opts *RequestOptions) error {
+	return c.pollTaskContext(context.Background(), getStatus, opts)
+}
+
+// pollTaskContext is the same as pollTask but it stops polling, returning
+// the error of the `ctx`, as soon as the `ctx` is done.
+func (c *client) pollTaskContext(ctx context.Context, getStatus func() (TaskStatusRes, error), opts *RequestOptions) error {
+	return c.waitSchedule(opts).poll(ctx, func() (bool, error) {
+		res, err := getStatus()
+		return res.Status == "published", err
+	})
+}
+
+// poll calls `done`, according to the schedule, until it either reports
+// that the awaited operation is over or fails. It stops polling, returning
+// the error of the `ctx`, as soon as the `ctx` is done.
+func (s WaitSchedule) poll(ctx context.Context, done func() (bool, error)) error {
+	var maxDuration = s.Initial
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		ok, err := done()
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(randDuration(maxDuration)):
+		}
+
+		// Increase the upper boundary used to generate the sleep
+		// duration
+		maxDuration = s.next(maxDuration)
+	}
+}
+
+// next returns the upper bound of the delay following the one bounded by
+// `current`: `current` doubled, without exceeding `Max`.
+func (s WaitSchedule) next(current time.Duration) time.Duration {
+	if current >= s.Max {
+		return current
+	}
+	if current *= 2; current > s.Max {
+		return s.Max
+	}
+	return current
+}
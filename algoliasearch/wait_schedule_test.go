@@ -0,0 +1,114 @@
+package algoliasearch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitSchedule(t *testing.T) {
+	t.Log("TestWaitSchedule: Start a server publishing the task after three polls")
+	polls := 0
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		if polls%3 != 0 {
+			w.Write([]byte(`{"status":"notPublished"}`))
+			return
+		}
+		w.Write([]byte(`{"status":"published"}`))
+	}))
+	defer server.Close()
+	c := &client{transport: newTestTransport(server)}
+	index := c.InitIndex("products")
+
+	t.Log("TestWaitSchedule: Check the schedule selection")
+	{
+		require.Equal(t, WaitScheduleDefault, c.waitSchedule(nil))
+
+		c.SetWaitSchedule(WaitScheduleRelaxed)
+		require.Equal(t, WaitScheduleRelaxed, c.waitSchedule(nil))
+		require.Equal(t, WaitScheduleAggressive, c.waitSchedule(&RequestOptions{WaitSchedule: &WaitScheduleAggressive}))
+
+		c.SetWaitSchedule(WaitSchedule{Max: time.Second})
+		require.Equal(t, WaitSchedule{Initial: time.Second, Max: time.Second}, c.waitSchedule(nil))
+	}
+
+	t.Log("TestWaitSchedule: Check that the aggressive schedule gives sub-second feedback")
+	{
+		c.SetWaitSchedule(WaitScheduleAggressive)
+		start := time.Now()
+		require.Nil(t, index.WaitTask(42))
+		require.Equal(t, 3, polls)
+		require.True(t, time.Since(start) < time.Second, "should poll at least every 200ms")
+	}
+
+	t.Log("TestWaitSchedule: Check that the delays never exceed the maximum one")
+	{
+		delay := WaitScheduleAggressive.Initial
+		for n := 0; n < 10; n++ {
+			delay = WaitScheduleAggressive.next(delay)
+			require.True(t, delay <= WaitScheduleAggressive.Max, "should cap the delay, got %s", delay)
+		}
+		require.Equal(t, WaitScheduleAggressive.Max, delay)
+		require.Equal(t, 10*time.Minute, WaitScheduleDefault.next(8*time.Minute))
+	}
+}
+
+func TestWaitTaskAsync(t *testing.T) {
+	t.Log("TestWaitTaskAsync: Start a server publishing the task after two polls")
+	polls := make(chan struct{}, 10)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls <- struct{}{}
+		if r.URL.Path == "/1/indexes/missing/task/42" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"Index does not exist","status":404}`))
+			return
+		}
+		if len(polls) < 2 || r.URL.Path == "/1/indexes/pending/task/43" {
+			w.Write([]byte(`{"status":"notPublished"}`))
+			return
+		}
+		w.Write([]byte(`{"status":"published"}`))
+	}))
+	defer server.Close()
+	c := &client{transport: newTestTransport(server), waitSched: WaitScheduleAggressive}
+
+	t.Log("TestWaitTaskAsync: Check that the completion of the task is received")
+	{
+		select {
+		case err, ok := <-c.InitIndex("products").WaitTaskAsync(context.Background(), 42):
+			require.True(t, ok)
+			require.Nil(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("should complete once the task is published")
+		}
+	}
+
+	t.Log("TestWaitTaskAsync: Check that errors are received and the channel closed")
+	{
+		done := c.InitIndex("missing").WaitTaskAsync(context.Background(), 42)
+		err := <-done
+		require.True(t, IsNotFound(err))
+		_, ok := <-done
+		require.False(t, ok, "should be closed")
+	}
+
+	t.Log("TestWaitTaskAsync: Check that the polling stops once the context is cancelled")
+	{
+		ctx, cancel := context.WithCancel(context.Background())
+		done := c.InitIndex("pending").WaitTaskAsync(ctx, 43)
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+
+		select {
+		case err := <-done:
+			require.Equal(t, context.Canceled, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("should stop polling once the context is cancelled")
+		}
+	}
+}